@@ -1,12 +1,16 @@
 package env
 
-import "github.com/caarlos0/env"
+import (
+	"time"
+
+	"github.com/caarlos0/env"
+)
 
 type config struct {
-	ServiceName           string `env:"SERVICE_NAME" envDefault:"accounting"`
-	KafkaBootstrapServers string `env:"KAFKA_BOOTSTRAP_SERVERS" envDefault:"localhost:9092"`
-	KafkaTopics           string `env:"KAFKA_TOPICS" envDefault:"service.accounting.request"`
-	KafkaGroupID          string `env:"KAFKA_GROUP_ID" envDefault:"accounting-service-group"`
+	ServiceName           string        `env:"SERVICE_NAME" envDefault:"accounting"`
+	KafkaBootstrapServers string        `env:"KAFKA_BOOTSTRAP_SERVERS" envDefault:"localhost:9092"`
+	KafkaGroupID          string        `env:"KAFKA_GROUP_ID" envDefault:"accounting-service-group"`
+	ShutdownTimeout       time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
 }
 
 func Load() (*config, error) {
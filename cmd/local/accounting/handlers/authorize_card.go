@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 
-	"github.com/bmviniciuss/sagas-golang/cmd/local/accounting/application"
-	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	goval "github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
@@ -14,58 +14,33 @@ type AuthorizeCardHandler struct {
 }
 
 var (
-	_ application.MessageHandler = (*AuthorizeCardHandler)(nil)
+	ErrCardNotAuthorized = errors.New("card not authorized for this purchase")
 )
 
-type request struct {
-	Card   string `json:"card"`
-	Amount *int64 `json:"amount"`
+type AuthorizeCardRequest struct {
+	Card   string `json:"card" validate:"required"`
+	Amount *int64 `json:"amount" validate:"required,gt=0"`
 }
 
-func NewAuthorizeCardHandler(logger *zap.SugaredLogger) *AuthorizeCardHandler {
-	return &AuthorizeCardHandler{
+func NewAuthorizeCardHandler(logger *zap.SugaredLogger, validate *goval.Validate) *participant.TypedHandler[AuthorizeCardRequest, struct{}] {
+	h := &AuthorizeCardHandler{
 		logger: logger,
 	}
+	return participant.NewTypedHandler(validate, h.Handle)
 }
 
-func parseInput(data map[string]interface{}, dest interface{}) error {
-	raw, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(raw, dest)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-// TODO: add enum
-// Request:      "authorize_card",
-// Success:      "card_authorized",
-// Failure:      "card_authorization_failed",
-
-func (h *AuthorizeCardHandler) Handle(ctx context.Context, msg *events.Event) (*events.Event, error) {
+func (h *AuthorizeCardHandler) Handle(ctx context.Context, cmd participant.Command[AuthorizeCardRequest]) (struct{}, error) {
 	lggr := h.logger
-	lggr.Infof("Handling message [%s]", msg.Type)
+	lggr.Infof("Handling authorize card command [%s]", cmd.CorrelationID)
 
-	globalID := msg.CorrelationID
-	var req request
-	err := parseInput(msg.Data, &req)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error reading input")
-		return nil, err
-	}
+	req := cmd.Data
 	lggr.Infof("Authoring card [%s] for amount [%d]", req.Card, *req.Amount)
 
 	if req.Card == "0000000000000000" {
 		lggr.Error("Card not authorized for this purchase")
-		errEvt := events.NewEvent("card_authorization_failed", "accounting", make(map[string]interface{})).WithCorrelationID(globalID)
-		return errEvt, nil
+		return struct{}{}, ErrCardNotAuthorized
 	}
 
 	lggr.Infof("Successfully authorized card [%s] for amount [%d]", req.Card, *req.Amount)
-	res := map[string]interface{}{}
-	successEvt := events.NewEvent("card_authorized", "accounting", res).WithCorrelationID(globalID)
-	return successEvt, nil
+	return struct{}{}, nil
 }
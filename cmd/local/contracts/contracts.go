@@ -0,0 +1,71 @@
+// Package contracts declares the step contracts of the create order saga.
+// It is imported by the orchestrator workflow definition and by the participant services,
+// so the event types and topics of every step are declared in a single place.
+package contracts
+
+import "github.com/bmviniciuss/sagas-golang/internal/saga"
+
+const (
+	OrdersService     = "orders"
+	CustomersService  = "customers"
+	AccountingService = "accounting"
+)
+
+var (
+	OrdersTopics = saga.Topics{
+		Request:  "service.orders.request",
+		Response: "service.orders.events",
+	}
+	CustomersTopics = saga.Topics{
+		Request:  "service.customers.request",
+		Response: "service.customers.events",
+	}
+	AccountingTopics = saga.Topics{
+		Request:  "service.accounting.request",
+		Response: "service.accounting.events",
+	}
+)
+
+var (
+	CreateOrder = saga.StepContract{
+		ServiceName: OrdersService,
+		EventTypes: saga.EventTypes{
+			Request:             "create_order",
+			Success:             "order_created",
+			Failure:             "order_creation_failed",
+			CompesationRequest:  "reject_order",
+			Compensation:        "order_rejected",
+			CompensationFailure: "order_rejection_failed",
+		},
+		Topics: OrdersTopics,
+	}
+
+	VerifyCustomer = saga.StepContract{
+		ServiceName: CustomersService,
+		EventTypes: saga.EventTypes{
+			Request: "verify_customer",
+			Success: "customer_verified",
+			Failure: "customer_verification_failed",
+		},
+		Topics: CustomersTopics,
+	}
+
+	AuthorizeCard = saga.StepContract{
+		ServiceName: AccountingService,
+		EventTypes: saga.EventTypes{
+			Request: "authorize_card",
+			Success: "card_authorized",
+			Failure: "card_authorization_failed",
+		},
+		Topics: AccountingTopics,
+	}
+
+	ApproveOrder = saga.StepContract{
+		ServiceName: OrdersService,
+		EventTypes: saga.EventTypes{
+			Request: "approve_order",
+			Success: "order_approved",
+		},
+		Topics: OrdersTopics,
+	}
+)
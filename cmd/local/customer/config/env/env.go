@@ -1,6 +1,10 @@
 package env
 
-import "github.com/caarlos0/env"
+import (
+	"time"
+
+	"github.com/caarlos0/env"
+)
 
 type (
 	Config struct {
@@ -20,10 +24,10 @@ type (
 )
 
 type config struct {
-	ServiceName           string `env:"SERVICE_NAME" envDefault:"customers"`
-	KafkaBootstrapServers string `env:"KAFKA_BOOTSTRAP_SERVERS" envDefault:"localhost:9092"`
-	KafkaTopics           string `env:"KAFKA_TOPICS" envDefault:"service.customers.request"`
-	KafkaGroupID          string `env:"KAFKA_GROUP_ID" envDefault:"customer-service-group"`
+	ServiceName           string        `env:"SERVICE_NAME" envDefault:"customers"`
+	KafkaBootstrapServers string        `env:"KAFKA_BOOTSTRAP_SERVERS" envDefault:"localhost:9092"`
+	KafkaGroupID          string        `env:"KAFKA_GROUP_ID" envDefault:"customer-service-group"`
+	ShutdownTimeout       time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
 }
 
 func Load() (*config, error) {
@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
-	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	goval "github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -15,56 +15,36 @@ type VerifyCustomer struct {
 }
 
 var (
-	_ application.MessageHandler = (*VerifyCustomer)(nil)
+	ErrCustomerNotAvailable = errors.New("customer not available to create order")
 )
 
-type request struct {
+type VerifyCustomerRequest struct {
 	CustomerID uuid.UUID `json:"customer_id"`
 }
 
-func NewVerifyCustomer(logger *zap.SugaredLogger) *VerifyCustomer {
-	return &VerifyCustomer{
-		logger: logger,
-	}
+type VerifyCustomerResponse struct {
+	CustomerID string `json:"customer_id"`
 }
 
-func parseInput(data map[string]interface{}, dest interface{}) error {
-	raw, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(raw, dest)
-	if err != nil {
-		return err
+func NewVerifyCustomer(logger *zap.SugaredLogger, validate *goval.Validate) *participant.TypedHandler[VerifyCustomerRequest, VerifyCustomerResponse] {
+	h := &VerifyCustomer{
+		logger: logger,
 	}
-	return nil
+	return participant.NewTypedHandler(validate, h.Handle)
 }
 
-// Request:      "verify_customer",
-// Success:      "customer_verified",
-// Failure:      "customer_verification_failed",
-
-func (h *VerifyCustomer) Handle(ctx context.Context, msg *events.Event) (*events.Event, error) {
+func (h *VerifyCustomer) Handle(ctx context.Context, cmd participant.Command[VerifyCustomerRequest]) (VerifyCustomerResponse, error) {
 	lggr := h.logger
-	lggr.Infof("Handling message with message [%s]", msg.Type)
+	lggr.Infof("Handling verify customer command [%s]", cmd.CorrelationID)
 
-	globalID := msg.CorrelationID
-	var req request
-	err := parseInput(msg.Data, &req)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error reading input")
-		return nil, err
-	}
-	lggr.Infof("Validating customer [%s]", req.CustomerID.String())
+	customerID := cmd.Data.CustomerID
+	lggr.Infof("Validating customer [%s]", customerID.String())
 
-	if req.CustomerID.String() == "00000000-0000-0000-0000-000000000000" {
+	if customerID == uuid.Nil {
 		lggr.Error("Customer not available to create order")
-		errEvt := events.NewEvent("customer_verification_failed", "customers", make(map[string]interface{})).WithCorrelationID(globalID) // TODO: fix null on data
-		return errEvt, nil
+		return VerifyCustomerResponse{}, ErrCustomerNotAvailable
 	}
 
 	lggr.Infof("Customer can create order")
-	res := map[string]interface{}{"customer_id": req.CustomerID.String()}
-	successEvt := events.NewEvent("customer_verified", "customers", res).WithCorrelationID(globalID)
-	return successEvt, nil
+	return VerifyCustomerResponse{CustomerID: customerID.String()}, nil
 }
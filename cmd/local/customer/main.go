@@ -2,30 +2,25 @@ package main
 
 import (
 	"context"
-	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/customer/config/env"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/customer/handlers"
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kv"
 	"github.com/bmviniciuss/sagas-golang/internal/config/logger"
+	"github.com/bmviniciuss/sagas-golang/internal/lifecycle"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
 	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
 )
 
 func main() {
-	var (
-		ctx, cancel = context.WithCancel(context.Background())
-		sigCh       = make(chan os.Signal, 1)
-		errCh       = make(chan error, 1)
-	)
-	defer cancel()
-	defer close(sigCh)
-	defer close(errCh)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	cfg, err := env.Load()
 	if err != nil {
@@ -39,7 +34,6 @@ func main() {
 
 	var (
 		bootstrapServers = cfg.KafkaBootstrapServers
-		topics           = strings.Split(cfg.KafkaTopics, ",")
 		group            = cfg.KafkaGroupID
 	)
 
@@ -47,12 +41,13 @@ func main() {
 		"bootstrap.servers": bootstrapServers,
 	})
 
-	verifyCustomerHandler := handlers.NewVerifyCustomer(lggr)
-	handlersMap := map[string]application.MessageHandler{
-		"verify_customer": verifyCustomerHandler,
+	handler, err := participant.NewRuntime(lggr, publisher, kv.NewInmemAdapter(),
+		participant.RequestRoute(contracts.VerifyCustomer, handlers.NewVerifyCustomer(lggr, validator.New())),
+	)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error creating participant runtime")
 	}
-	handler := handlers.NewCustomerMessageHandler(lggr, *publisher, handlersMap)
-	consumer, err := streaming.NewConsumer(lggr, topics, &kafka.ConfigMap{
+	consumer, err := streaming.NewConsumer(lggr, handler.Topics(), &kafka.ConfigMap{
 		"bootstrap.servers":        bootstrapServers,
 		"broker.address.family":    "v4",
 		"group.id":                 group,
@@ -65,22 +60,12 @@ func main() {
 		lggr.With(zap.Error(err)).Fatal("Got error creating consumer")
 	}
 
-	go func() {
-		if err := consumer.Start(ctx); err != nil {
-			lggr.With(zap.Error(err)).Fatal("Got in consumer")
-			errCh <- err
-		}
-	}()
-
-	lggr.Info("Running Order Service. Waiting for signal to stop...")
-	select {
-	case <-sigCh:
-		lggr.Info("Got signal, stopping consumer")
-		cancel()
-	case err := <-errCh:
-		lggr.With(zap.Error(err)).Fatal("Got error in consumer")
-		cancel()
-		os.Exit(1)
+	err = lifecycle.New(lggr, cfg.ShutdownTimeout).
+		Go("consumer", consumer.Start).
+		OnStop("publisher", publisher.Close).
+		Run(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error running Customer Service")
 	}
 
 	lggr.Info("Exiting")
@@ -10,10 +10,22 @@ import (
 )
 
 type SagasExecution struct {
-	Identifier   int32
-	Uuid         uuid.UUID
-	WorkflowName string
-	State        []byte
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
+	Identifier           int32
+	Uuid                 uuid.UUID
+	WorkflowName         string
+	State                []byte
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+	Status               string
+	Intervention         []byte
+	CompensationAttempts []byte
+	CurrentStep          string
+	Transitions          []byte
+	BusinessKey          string
+	IdempotencyKey       pgtype.Text
+	IdempotencyExpiresAt pgtype.Timestamptz
+	CallbackUrl          string
+	StartedBy            string
+	Version              int32
+	OutcomePublished     bool
 }
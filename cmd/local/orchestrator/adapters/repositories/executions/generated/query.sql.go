@@ -9,10 +9,62 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countActiveExecutions = `-- name: CountActiveExecutions :one
+SELECT count(*)
+FROM sagas.executions
+WHERE workflow_name = $1 AND status IN ('running', 'compensating', 'cancelling')
+`
+
+func (q *Queries) CountActiveExecutions(ctx context.Context, workflowName string) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveExecutions, workflowName)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const findExecutionByIdempotencyKey = `-- name: FindExecutionByIdempotencyKey :one
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
+FROM sagas.executions
+WHERE workflow_name = $1 AND idempotency_key = $2 AND idempotency_expires_at > now()
+LIMIT 1
+`
+
+type FindExecutionByIdempotencyKeyParams struct {
+	WorkflowName   string
+	IdempotencyKey pgtype.Text
+}
+
+func (q *Queries) FindExecutionByIdempotencyKey(ctx context.Context, arg FindExecutionByIdempotencyKeyParams) (SagasExecution, error) {
+	row := q.db.QueryRow(ctx, findExecutionByIdempotencyKey, arg.WorkflowName, arg.IdempotencyKey)
+	var i SagasExecution
+	err := row.Scan(
+		&i.Identifier,
+		&i.Uuid,
+		&i.WorkflowName,
+		&i.State,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Intervention,
+		&i.CompensationAttempts,
+		&i.CurrentStep,
+		&i.Transitions,
+		&i.BusinessKey,
+		&i.IdempotencyKey,
+		&i.IdempotencyExpiresAt,
+		&i.CallbackUrl,
+		&i.StartedBy,
+		&i.Version,
+		&i.OutcomePublished,
+	)
+	return i, err
+}
+
 const findExecutionByUUID = `-- name: FindExecutionByUUID :one
-SELECT id, uuid, workflow_name, state, created_at, updated_at
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
 FROM sagas.executions
 WHERE uuid = $1 LIMIT 1
 `
@@ -27,39 +79,208 @@ func (q *Queries) FindExecutionByUUID(ctx context.Context, argUuid uuid.UUID) (S
 		&i.State,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.Intervention,
+		&i.CompensationAttempts,
+		&i.CurrentStep,
+		&i.Transitions,
+		&i.BusinessKey,
+		&i.IdempotencyKey,
+		&i.IdempotencyExpiresAt,
+		&i.CallbackUrl,
+		&i.StartedBy,
+		&i.Version,
+		&i.OutcomePublished,
 	)
 	return i, err
 }
 
-const insertExecution = `-- name: InsertExecution :exec
+const insertExecution = `-- name: InsertExecution :one
 INSERT INTO sagas.executions
-	("uuid", workflow_name, state, created_at, updated_at)
-VALUES ($1, $2, $3, now(), now()) RETURNING id
+	("uuid", workflow_name, state, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now(), now()) RETURNING version, created_at, updated_at
 `
 
 type InsertExecutionParams struct {
-	Uuid         uuid.UUID
-	WorkflowName string
-	State        []byte
+	Uuid                 uuid.UUID
+	WorkflowName         string
+	State                []byte
+	Status               string
+	Intervention         []byte
+	CompensationAttempts []byte
+	CurrentStep          string
+	Transitions          []byte
+	BusinessKey          string
+	IdempotencyKey       pgtype.Text
+	IdempotencyExpiresAt pgtype.Timestamptz
+	CallbackUrl          string
+	StartedBy            string
+}
+
+type InsertExecutionRow struct {
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
 }
 
-func (q *Queries) InsertExecution(ctx context.Context, arg InsertExecutionParams) error {
-	_, err := q.db.Exec(ctx, insertExecution, arg.Uuid, arg.WorkflowName, arg.State)
+func (q *Queries) InsertExecution(ctx context.Context, arg InsertExecutionParams) (InsertExecutionRow, error) {
+	row := q.db.QueryRow(ctx, insertExecution,
+		arg.Uuid,
+		arg.WorkflowName,
+		arg.State,
+		arg.Status,
+		arg.Intervention,
+		arg.CompensationAttempts,
+		arg.CurrentStep,
+		arg.Transitions,
+		arg.BusinessKey,
+		arg.IdempotencyKey,
+		arg.IdempotencyExpiresAt,
+		arg.CallbackUrl,
+		arg.StartedBy,
+	)
+	var i InsertExecutionRow
+	err := row.Scan(&i.Version, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const listExecutions = `-- name: ListExecutions :many
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
+FROM sagas.executions
+WHERE ($1::varchar IS NULL OR workflow_name = $1)
+  AND ($2::varchar IS NULL OR status = $2)
+  AND ($3::timestamptz IS NULL OR created_at >= $3)
+  AND ($4::timestamptz IS NULL OR created_at < $4)
+  AND ($5::uuid IS NULL OR uuid = $5)
+  AND ($6::varchar IS NULL OR business_key = $6)
+  AND ($7::timestamptz IS NULL OR (created_at, uuid) < ($7, $8::uuid))
+ORDER BY created_at DESC, uuid DESC
+LIMIT $9
+`
+
+type ListExecutionsParams struct {
+	WorkflowName   pgtype.Text
+	Status         pgtype.Text
+	CreatedFrom    pgtype.Timestamptz
+	CreatedTo      pgtype.Timestamptz
+	CorrelationID  pgtype.UUID
+	BusinessKey    pgtype.Text
+	AfterCreatedAt pgtype.Timestamptz
+	AfterUuid      pgtype.UUID
+	PageSize       int32
+}
+
+func (q *Queries) ListExecutions(ctx context.Context, arg ListExecutionsParams) ([]SagasExecution, error) {
+	rows, err := q.db.Query(ctx, listExecutions,
+		arg.WorkflowName,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CorrelationID,
+		arg.BusinessKey,
+		arg.AfterCreatedAt,
+		arg.AfterUuid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SagasExecution
+	for rows.Next() {
+		var i SagasExecution
+		if err := rows.Scan(
+			&i.Identifier,
+			&i.Uuid,
+			&i.WorkflowName,
+			&i.State,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Intervention,
+			&i.CompensationAttempts,
+			&i.CurrentStep,
+			&i.Transitions,
+			&i.BusinessKey,
+			&i.IdempotencyKey,
+			&i.IdempotencyExpiresAt,
+			&i.CallbackUrl,
+			&i.StartedBy,
+			&i.Version,
+			&i.OutcomePublished,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockWorkflowExecutions = `-- name: LockWorkflowExecutions :exec
+SELECT pg_advisory_xact_lock(hashtext($1::text))
+`
+
+func (q *Queries) LockWorkflowExecutions(ctx context.Context, workflowName string) error {
+	_, err := q.db.Exec(ctx, lockWorkflowExecutions, workflowName)
 	return err
 }
 
-const updateExecution = `-- name: UpdateExecution :exec
+const releaseExpiredIdempotencyKey = `-- name: ReleaseExpiredIdempotencyKey :exec
 UPDATE sagas.executions
-SET state = $2, updated_at = now()
-WHERE uuid = $1
+SET idempotency_key = NULL
+WHERE workflow_name = $1 AND idempotency_key = $2 AND idempotency_expires_at <= now()
 `
 
-type UpdateExecutionParams struct {
-	Uuid  uuid.UUID
-	State []byte
+type ReleaseExpiredIdempotencyKeyParams struct {
+	WorkflowName   string
+	IdempotencyKey pgtype.Text
 }
 
-func (q *Queries) UpdateExecution(ctx context.Context, arg UpdateExecutionParams) error {
-	_, err := q.db.Exec(ctx, updateExecution, arg.Uuid, arg.State)
+func (q *Queries) ReleaseExpiredIdempotencyKey(ctx context.Context, arg ReleaseExpiredIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseExpiredIdempotencyKey, arg.WorkflowName, arg.IdempotencyKey)
 	return err
 }
+
+const updateExecution = `-- name: UpdateExecution :one
+UPDATE sagas.executions
+SET state = $2, status = $3, intervention = $4, compensation_attempts = $5, current_step = $6, transitions = $7, outcome_published = $9, version = version + 1, updated_at = now()
+WHERE uuid = $1 AND version = $8
+RETURNING version, updated_at
+`
+
+type UpdateExecutionParams struct {
+	Uuid                 uuid.UUID
+	State                []byte
+	Status               string
+	Intervention         []byte
+	CompensationAttempts []byte
+	CurrentStep          string
+	Transitions          []byte
+	Version              int32
+	OutcomePublished     bool
+}
+
+type UpdateExecutionRow struct {
+	Version   int32
+	UpdatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpdateExecution(ctx context.Context, arg UpdateExecutionParams) (UpdateExecutionRow, error) {
+	row := q.db.QueryRow(ctx, updateExecution,
+		arg.Uuid,
+		arg.State,
+		arg.Status,
+		arg.Intervention,
+		arg.CompensationAttempts,
+		arg.CurrentStep,
+		arg.Transitions,
+		arg.Version,
+		arg.OutcomePublished,
+	)
+	var i UpdateExecutionRow
+	err := row.Scan(&i.Version, &i.UpdatedAt)
+	return i, err
+}
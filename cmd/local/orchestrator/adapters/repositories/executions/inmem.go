@@ -2,11 +2,18 @@ package executions
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 )
 
+var (
+	_ saga.ExecutionRepository = (*InmemRepository)(nil)
+)
+
+// InmemRepository keeps the executions in memory. It doesn't deliver their notifications.
 type InmemRepository struct {
 	mu   *sync.Mutex
 	data map[string]*saga.Execution
@@ -19,11 +26,52 @@ func NewInmemRepository() *InmemRepository {
 	}
 }
 
+func (r *InmemRepository) Insert(ctx context.Context, execution *saga.Execution, maxActive int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if execution.IdempotencyKey != "" && !r.findByIdempotencyKey(execution.Workflow.Name, execution.IdempotencyKey).IsEmpty() {
+		return saga.ErrDuplicateIdempotencyKey
+	}
+	if maxActive > 0 && r.countActive(execution.Workflow.Name) >= maxActive {
+		return saga.ErrTooManyExecutions
+	}
+	execution.Version = 1
+	execution.UpdatedAt = time.Now().UTC()
+	r.data[execution.ID.String()] = execution.Clone()
+	return nil
+}
+
+func (r *InmemRepository) FindByIdempotencyKey(ctx context.Context, workflowName string, key string) (*saga.Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.findByIdempotencyKey(workflowName, key), nil
+}
+
+func (r *InmemRepository) findByIdempotencyKey(workflowName string, key string) *saga.Execution {
+	now := time.Now()
+	for _, execution := range r.data {
+		if execution.Workflow.Name == workflowName && execution.IdempotencyKey == key && now.Before(execution.IdempotencyExpiresAt) {
+			return execution.Clone()
+		}
+	}
+	return &saga.Execution{}
+}
+
+func (r *InmemRepository) countActive(workflowName string) int {
+	count := 0
+	for _, execution := range r.data {
+		if execution.Workflow.Name == workflowName && execution.Status.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
 func (r *InmemRepository) Find(ctx context.Context, globalID string) (*saga.Execution, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if execution, ok := r.data[globalID]; ok {
-		return execution, nil
+		return execution.Clone(), nil
 	}
 	return &saga.Execution{}, nil
 }
@@ -31,6 +79,38 @@ func (r *InmemRepository) Find(ctx context.Context, globalID string) (*saga.Exec
 func (r *InmemRepository) Save(ctx context.Context, execution *saga.Execution) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.data[execution.ID.String()] = execution
+	stored, ok := r.data[execution.ID.String()]
+	if !ok || stored.Version != execution.Version {
+		return saga.ErrConcurrentUpdate
+	}
+	execution.Version++
+	execution.UpdatedAt = time.Now().UTC()
+	r.data[execution.ID.String()] = execution.Clone()
 	return nil
 }
+
+func (r *InmemRepository) List(ctx context.Context, filter saga.ExecutionFilter) (saga.ExecutionPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	executions := make([]*saga.Execution, 0)
+	for _, execution := range r.data {
+		if !filter.Matches(execution) {
+			continue
+		}
+		if filter.After != nil && !filter.After.Before(execution) {
+			continue
+		}
+		executions = append(executions, execution.Clone())
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		return saga.CursorOf(executions[i]).Before(executions[j])
+	})
+
+	pageSize := filter.PageSize()
+	page := saga.ExecutionPage{Executions: executions}
+	if len(executions) > pageSize {
+		page.Executions = executions[:pageSize]
+		page.Next = saga.CursorOf(executions[pageSize-1])
+	}
+	return page, nil
+}
@@ -0,0 +1,72 @@
+package executions
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInmemRepository_Insert(t *testing.T) {
+	ctx := context.Background()
+	newWorkflow := func(name string) *saga.Workflow {
+		return &saga.Workflow{Name: name, Steps: saga.NewStepList(&saga.StepData{Name: "create_order"})}
+	}
+
+	t.Run("should not exceed the maximum active executions with concurrent inserts", func(t *testing.T) {
+		repo := NewInmemRepository()
+		workflow := newWorkflow("create_order_v1")
+		const maxActive, inserts = 3, 50
+
+		var inserted, rejected atomic.Int32
+		var wg sync.WaitGroup
+		for range inserts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := repo.Insert(ctx, saga.NewExecution(workflow), maxActive)
+				switch {
+				case err == nil:
+					inserted.Add(1)
+				case errors.Is(err, saga.ErrTooManyExecutions):
+					rejected.Add(1)
+				default:
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.EqualValues(t, maxActive, inserted.Load())
+		assert.EqualValues(t, inserts-maxActive, rejected.Load())
+		page, err := repo.List(ctx, saga.ExecutionFilter{Limit: inserts})
+		require.NoError(t, err)
+		assert.Len(t, page.Executions, maxActive)
+	})
+
+	t.Run("should count only the active executions of the workflow", func(t *testing.T) {
+		repo := NewInmemRepository()
+		workflow := newWorkflow("create_order_v1")
+		finished := saga.NewExecution(workflow)
+		finished.Status = saga.ExecutionStatusCompleted
+		require.NoError(t, repo.Insert(ctx, finished, 1))
+		require.NoError(t, repo.Insert(ctx, saga.NewExecution(newWorkflow("other_v1")), 1))
+
+		require.NoError(t, repo.Insert(ctx, saga.NewExecution(workflow), 1))
+		err := repo.Insert(ctx, saga.NewExecution(workflow), 1)
+		assert.ErrorIs(t, err, saga.ErrTooManyExecutions)
+	})
+
+	t.Run("should not limit the executions when the maximum is zero", func(t *testing.T) {
+		repo := NewInmemRepository()
+		workflow := newWorkflow("create_order_v1")
+		for range 5 {
+			require.NoError(t, repo.Insert(ctx, saga.NewExecution(workflow), 0))
+		}
+	})
+}
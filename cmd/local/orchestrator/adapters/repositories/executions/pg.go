@@ -6,9 +6,13 @@ import (
 	"errors"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/executions/generated"
+	webhookrepo "github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/webhooks"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/internal/webhooks"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
@@ -27,37 +31,101 @@ func NewRepositoryAdapter(lggr *zap.SugaredLogger, pool *pgxpool.Pool, workflowR
 	}
 }
 
+const (
+	uniqueViolationCode = "23505"
+)
+
 var (
 	_ saga.ExecutionRepository = (*RepositoryAdapter)(nil)
 )
 
-func (r *RepositoryAdapter) Insert(ctx context.Context, execution *saga.Execution) error {
+func (r *RepositoryAdapter) Insert(ctx context.Context, execution *saga.Execution, maxActive int) error {
 	lggr := r.lggr
 	lggr.Info("RepositoryAdapter.Insert")
 
-	db, err := r.pool.Acquire(ctx)
+	cols, err := marshalColumns(execution)
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		lggr.With(zap.Error(err)).Error("Got error Marshalling execution")
 		return err
 	}
-	defer db.Release()
-	queries := generated.New(db)
 
-	state, err := json.Marshal(execution.State)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error Marshalling state")
+		lggr.With(zap.Error(err)).Error("Got error starting transaction")
 		return err
 	}
+	defer tx.Rollback(ctx)
+	queries := generated.New(tx)
 
-	err = queries.InsertExecution(ctx, generated.InsertExecutionParams{
-		Uuid:         execution.ID,
-		WorkflowName: execution.Workflow.Name,
-		State:        state,
+	idempotencyKey := pgtype.Text{String: execution.IdempotencyKey, Valid: execution.IdempotencyKey != ""}
+	if idempotencyKey.Valid {
+		// an expired key can be used again, so it is released from the execution retaining it
+		err = queries.ReleaseExpiredIdempotencyKey(ctx, generated.ReleaseExpiredIdempotencyKeyParams{
+			WorkflowName:   execution.Workflow.Name,
+			IdempotencyKey: idempotencyKey,
+		})
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error releasing expired idempotency key")
+			return err
+		}
+	}
+
+	if maxActive > 0 {
+		// the lock is held until the transaction ends, so the inserts of the workflow executions
+		// are counted one at a time and concurrent inserts can't exceed maxActive
+		err = queries.LockWorkflowExecutions(ctx, execution.Workflow.Name)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error locking workflow executions")
+			return err
+		}
+		active, err := queries.CountActiveExecutions(ctx, execution.Workflow.Name)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error counting active workflow executions")
+			return err
+		}
+		if int(active) >= maxActive {
+			lggr.Infof("Workflow has %d active executions", active)
+			return saga.ErrTooManyExecutions
+		}
+	}
+
+	row, err := queries.InsertExecution(ctx, generated.InsertExecutionParams{
+		Uuid:                 execution.ID,
+		WorkflowName:         execution.Workflow.Name,
+		State:                cols.state,
+		Status:               execution.Status.String(),
+		Intervention:         cols.intervention,
+		CompensationAttempts: cols.compensationAttempts,
+		CurrentStep:          execution.CurrentStep,
+		Transitions:          cols.transitions,
+		BusinessKey:          execution.BusinessKey,
+		IdempotencyKey:       idempotencyKey,
+		IdempotencyExpiresAt: pgtype.Timestamptz{Time: execution.IdempotencyExpiresAt, Valid: idempotencyKey.Valid},
+		CallbackUrl:          execution.CallbackURL,
+		StartedBy:            execution.StartedBy,
 	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		lggr.With(zap.Error(err)).Info("Idempotency key is already used by another execution")
+		return saga.ErrDuplicateIdempotencyKey
+	}
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error inserting workflow execution")
-		return nil
+		return err
 	}
+	err = insertNotifications(ctx, lggr, tx, execution)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error committing transaction")
+		return err
+	}
+	execution.Version = int(row.Version)
+	execution.CreatedAt = row.CreatedAt.Time.UTC()
+	execution.UpdatedAt = row.UpdatedAt.Time.UTC()
 
 	return nil
 }
@@ -66,32 +134,68 @@ func (r *RepositoryAdapter) Save(ctx context.Context, execution *saga.Execution)
 	lggr := r.lggr
 	lggr.Info("RepositoryAdapter.Save")
 
-	db, err := r.pool.Acquire(ctx)
+	cols, err := marshalColumns(execution)
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		lggr.With(zap.Error(err)).Error("Got error Marshalling execution")
 		return err
 	}
-	defer db.Release()
-	queries := generated.New(db)
 
-	state, err := json.Marshal(execution.State)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error Marshalling state")
+		lggr.With(zap.Error(err)).Error("Got error starting transaction")
 		return err
 	}
+	defer tx.Rollback(ctx)
+	queries := generated.New(tx)
 
-	err = queries.UpdateExecution(ctx, generated.UpdateExecutionParams{
-		Uuid:  execution.ID,
-		State: state,
+	row, err := queries.UpdateExecution(ctx, generated.UpdateExecutionParams{
+		Uuid:                 execution.ID,
+		State:                cols.state,
+		Status:               execution.Status.String(),
+		Intervention:         cols.intervention,
+		CompensationAttempts: cols.compensationAttempts,
+		CurrentStep:          execution.CurrentStep,
+		Transitions:          cols.transitions,
+		Version:              int32(execution.Version),
+		OutcomePublished:     execution.OutcomePublished,
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the version check matched no row, the execution was saved by someone else since it was read
+		lggr.Warnf("Execution [%s] was updated concurrently", execution.ID)
+		return saga.ErrConcurrentUpdate
+	}
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error updating workflow execution")
-		return nil
+		return err
 	}
+	err = insertNotifications(ctx, lggr, tx, execution)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error committing transaction")
+		return err
+	}
+	execution.Version = int(row.Version)
+	execution.UpdatedAt = row.UpdatedAt.Time.UTC()
 
 	return nil
 }
 
+// insertNotifications inserts the webhook deliveries of the notifications of execution in the transaction saving it.
+func insertNotifications(ctx context.Context, lggr *zap.SugaredLogger, tx pgx.Tx, execution *saga.Execution) error {
+	for _, notification := range execution.Notifications() {
+		delivery := webhooks.NewDelivery(execution.ID, notification.URL, notification.EventType, notification.Payload)
+		err := webhookrepo.InsertDelivery(ctx, lggr, tx, delivery)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *RepositoryAdapter) Find(ctx context.Context, globalID string) (*saga.Execution, error) {
 	lggr := r.lggr
 	lggr.Info("RepositoryAdapter.Find")
@@ -119,13 +223,127 @@ func (r *RepositoryAdapter) Find(ctx context.Context, globalID string) (*saga.Ex
 		return nil, err
 	}
 
+	return r.toExecution(ctx, execRow)
+}
+
+func (r *RepositoryAdapter) FindByIdempotencyKey(ctx context.Context, workflowName string, key string) (*saga.Execution, error) {
+	lggr := r.lggr
+	lggr.Info("RepositoryAdapter.FindByIdempotencyKey")
+
+	db, err := r.pool.Acquire(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		return nil, err
+	}
+	defer db.Release()
+	queries := generated.New(db)
+
+	execRow, err := queries.FindExecutionByIdempotencyKey(ctx, generated.FindExecutionByIdempotencyKeyParams{
+		WorkflowName:   workflowName,
+		IdempotencyKey: pgtype.Text{String: key, Valid: true},
+	})
+	if err == pgx.ErrNoRows {
+		return &saga.Execution{}, nil
+	}
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding workflow execution by idempotency key")
+		return nil, err
+	}
+
+	return r.toExecution(ctx, execRow)
+}
+
+func (r *RepositoryAdapter) List(ctx context.Context, filter saga.ExecutionFilter) (saga.ExecutionPage, error) {
+	lggr := r.lggr
+	lggr.Info("RepositoryAdapter.List")
+
+	db, err := r.pool.Acquire(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		return saga.ExecutionPage{}, err
+	}
+	defer db.Release()
+	queries := generated.New(db)
+
+	pageSize := filter.PageSize()
+	params := generated.ListExecutionsParams{
+		WorkflowName: pgtype.Text{String: filter.WorkflowName, Valid: filter.WorkflowName != ""},
+		Status:       pgtype.Text{String: filter.Status.String(), Valid: filter.Status != ""},
+		BusinessKey:  pgtype.Text{String: filter.BusinessKey, Valid: filter.BusinessKey != ""},
+		// fetching one more row tells if there is a next page
+		PageSize: int32(pageSize + 1),
+	}
+	if filter.CreatedFrom != nil {
+		params.CreatedFrom = pgtype.Timestamptz{Time: *filter.CreatedFrom, Valid: true}
+	}
+	if filter.CreatedTo != nil {
+		params.CreatedTo = pgtype.Timestamptz{Time: *filter.CreatedTo, Valid: true}
+	}
+	if filter.CorrelationID != nil {
+		params.CorrelationID = pgtype.UUID{Bytes: *filter.CorrelationID, Valid: true}
+	}
+	if filter.After != nil {
+		params.AfterCreatedAt = pgtype.Timestamptz{Time: filter.After.CreatedAt, Valid: true}
+		params.AfterUuid = pgtype.UUID{Bytes: filter.After.ID, Valid: true}
+	}
+
+	rows, err := queries.ListExecutions(ctx, params)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error listing workflow executions")
+		return saga.ExecutionPage{}, err
+	}
+
+	page := saga.ExecutionPage{Executions: make([]*saga.Execution, 0, len(rows))}
+	for i, row := range rows {
+		if i == pageSize {
+			page.Next = saga.CursorOf(page.Executions[pageSize-1])
+			break
+		}
+		execution, err := r.toExecution(ctx, row)
+		if err != nil {
+			return saga.ExecutionPage{}, err
+		}
+		page.Executions = append(page.Executions, execution)
+	}
+	return page, nil
+}
+
+func (r *RepositoryAdapter) toExecution(ctx context.Context, execRow generated.SagasExecution) (*saga.Execution, error) {
+	lggr := r.lggr
 	var state map[string]interface{}
-	err = json.Unmarshal(execRow.State, &state)
+	err := json.Unmarshal(execRow.State, &state)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error Unmarshalling state")
 		return nil, err
 	}
 
+	var intervention *saga.Intervention
+	if len(execRow.Intervention) > 0 {
+		err = json.Unmarshal(execRow.Intervention, &intervention)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error Unmarshalling intervention")
+			return nil, err
+		}
+	}
+
+	attempts := make(map[string]int)
+	if len(execRow.CompensationAttempts) > 0 {
+		err = json.Unmarshal(execRow.CompensationAttempts, &attempts)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error Unmarshalling compensation attempts")
+			return nil, err
+		}
+	}
+
+	transitions := make([]saga.Transition, 0)
+	if len(execRow.Transitions) > 0 {
+		err = json.Unmarshal(execRow.Transitions, &transitions)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error Unmarshalling transitions")
+			return nil, err
+		}
+	}
+
 	wflw, err := r.workflowRepository.Find(ctx, execRow.WorkflowName)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error finding workflow")
@@ -136,8 +354,60 @@ func (r *RepositoryAdapter) Find(ctx context.Context, globalID string) (*saga.Ex
 	}
 
 	return &saga.Execution{
-		ID:       execRow.Uuid,
-		Workflow: wflw,
-		State:    state,
+		ID:                   execRow.Uuid,
+		Workflow:             wflw,
+		State:                state,
+		Status:               saga.ExecutionStatus(execRow.Status),
+		Intervention:         intervention,
+		CompensationAttempts: attempts,
+		BusinessKey:          execRow.BusinessKey,
+		IdempotencyKey:       execRow.IdempotencyKey.String,
+		IdempotencyExpiresAt: execRow.IdempotencyExpiresAt.Time.UTC(),
+		CallbackURL:          execRow.CallbackUrl,
+		StartedBy:            execRow.StartedBy,
+		CurrentStep:          execRow.CurrentStep,
+		Transitions:          transitions,
+		Version:              int(execRow.Version),
+		OutcomePublished:     execRow.OutcomePublished,
+		CreatedAt:            execRow.CreatedAt.Time.UTC(),
+		UpdatedAt:            execRow.UpdatedAt.Time.UTC(),
 	}, nil
 }
+
+// columns holds the JSON encoded columns of an execution.
+type columns struct {
+	state                []byte
+	intervention         []byte
+	compensationAttempts []byte
+	transitions          []byte
+}
+
+func marshalColumns(execution *saga.Execution) (cols columns, err error) {
+	cols.state, err = json.Marshal(execution.State)
+	if err != nil {
+		return cols, err
+	}
+	if execution.Intervention != nil {
+		cols.intervention, err = json.Marshal(execution.Intervention)
+		if err != nil {
+			return cols, err
+		}
+	}
+	compensationAttempts := execution.CompensationAttempts
+	if compensationAttempts == nil {
+		compensationAttempts = make(map[string]int)
+	}
+	cols.compensationAttempts, err = json.Marshal(compensationAttempts)
+	if err != nil {
+		return cols, err
+	}
+	transitions := execution.Transitions
+	if transitions == nil {
+		transitions = make([]saga.Transition, 0)
+	}
+	cols.transitions, err = json.Marshal(transitions)
+	if err != nil {
+		return cols, err
+	}
+	return cols, nil
+}
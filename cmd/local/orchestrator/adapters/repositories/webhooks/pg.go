@@ -0,0 +1,114 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/webhooks/generated"
+	"github.com/bmviniciuss/sagas-golang/internal/webhooks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type RepositoryAdapter struct {
+	lggr *zap.SugaredLogger
+	pool *pgxpool.Pool
+}
+
+func NewRepositoryAdapter(lggr *zap.SugaredLogger, pool *pgxpool.Pool) *RepositoryAdapter {
+	return &RepositoryAdapter{
+		lggr: lggr,
+		pool: pool,
+	}
+}
+
+var (
+	_ webhooks.Repository = (*RepositoryAdapter)(nil)
+)
+
+// InsertDelivery inserts the delivery with db, which can be the transaction saving the execution it notifies.
+func InsertDelivery(ctx context.Context, lggr *zap.SugaredLogger, db generated.DBTX, delivery *webhooks.Delivery) error {
+	queries := generated.New(db)
+	row, err := queries.InsertWebhookDelivery(ctx, generated.InsertWebhookDeliveryParams{
+		Uuid:          delivery.ID,
+		ExecutionUuid: delivery.ExecutionID,
+		Url:           delivery.URL,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        delivery.Status.String(),
+		Attempts:      int32(delivery.Attempts),
+		NextAttemptAt: pgtype.Timestamptz{Time: delivery.NextAttemptAt, Valid: true},
+		LastError:     delivery.LastError,
+	})
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error inserting webhook delivery")
+		return err
+	}
+	delivery.CreatedAt = row.CreatedAt.Time.UTC()
+	delivery.UpdatedAt = row.UpdatedAt.Time.UTC()
+	return nil
+}
+
+func (r *RepositoryAdapter) Save(ctx context.Context, delivery *webhooks.Delivery) error {
+	lggr := r.lggr
+	db, err := r.pool.Acquire(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		return err
+	}
+	defer db.Release()
+	queries := generated.New(db)
+
+	updatedAt, err := queries.UpdateWebhookDelivery(ctx, generated.UpdateWebhookDeliveryParams{
+		Uuid:          delivery.ID,
+		Status:        delivery.Status.String(),
+		Attempts:      int32(delivery.Attempts),
+		NextAttemptAt: pgtype.Timestamptz{Time: delivery.NextAttemptAt, Valid: true},
+		LastError:     delivery.LastError,
+	})
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error updating webhook delivery")
+		return err
+	}
+	delivery.UpdatedAt = updatedAt.Time.UTC()
+	return nil
+}
+
+func (r *RepositoryAdapter) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*webhooks.Delivery, error) {
+	lggr := r.lggr
+	db, err := r.pool.Acquire(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error acquiring connection")
+		return nil, err
+	}
+	defer db.Release()
+	queries := generated.New(db)
+
+	rows, err := queries.ClaimDueWebhookDeliveries(ctx, generated.ClaimDueWebhookDeliveriesParams{
+		Lease:     pgtype.Interval{Microseconds: lease.Microseconds(), Valid: true},
+		BatchSize: int32(limit),
+	})
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error claiming due webhook deliveries")
+		return nil, err
+	}
+
+	deliveries := make([]*webhooks.Delivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, &webhooks.Delivery{
+			ID:            row.Uuid,
+			ExecutionID:   row.ExecutionUuid,
+			URL:           row.Url,
+			EventType:     row.EventType,
+			Payload:       row.Payload,
+			Status:        webhooks.DeliveryStatus(row.Status),
+			Attempts:      int(row.Attempts),
+			NextAttemptAt: row.NextAttemptAt.Time.UTC(),
+			LastError:     row.LastError,
+			CreatedAt:     row.CreatedAt.Time.UTC(),
+			UpdatedAt:     row.UpdatedAt.Time.UTC(),
+		})
+	}
+	return deliveries, nil
+}
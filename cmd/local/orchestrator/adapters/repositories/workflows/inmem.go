@@ -2,6 +2,7 @@ package workflows
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
@@ -12,6 +13,10 @@ type InmemRepository struct {
 	data map[string]*saga.Workflow
 }
 
+var (
+	_ saga.WorkflowRepository = (*InmemRepository)(nil)
+)
+
 func NewInmemRepository(workflows []saga.Workflow) *InmemRepository {
 	data := make(map[string]*saga.Workflow)
 	for _, w := range workflows {
@@ -32,3 +37,16 @@ func (r *InmemRepository) Find(ctx context.Context, name string) (*saga.Workflow
 	}
 	return &saga.Workflow{}, nil
 }
+
+func (r *InmemRepository) List(ctx context.Context) ([]*saga.Workflow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	workflows := make([]*saga.Workflow, 0, len(r.data))
+	for _, workflow := range r.data {
+		workflows = append(workflows, workflow)
+	}
+	sort.Slice(workflows, func(i, j int) bool {
+		return workflows[i].Name < workflows[j].Name
+	})
+	return workflows, nil
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/lifecycle"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventsKeepAliveInterval is how often a comment is sent to keep idle event streams open.
+	eventsKeepAliveInterval = 15 * time.Second
+)
+
+// StreamExecutionEvents streams the progress of an execution as Server-Sent Events.
+// The transitions already recorded are sent first, then the new ones as they happen,
+// and the stream ends after the finished event.
+func (h *Handlers) StreamExecutionEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding execution id")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "id",
+				Message: "Invalid execution ID",
+			},
+		}))
+		return
+	}
+	lggr = lggr.With("execution_id", id.String())
+	lggr.Info("Streaming execution events")
+
+	// subscribes before reading the execution, so no transition happens unnoticed in between
+	events, unsubscribe, err := h.progressBroker.Subscribe(ctx, id)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error subscribing to execution events")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	defer unsubscribe()
+
+	execution, err := h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding execution")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if execution.IsEmpty() {
+		lggr.Error("Execution not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	// the stream outlives the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		lggr.With(zap.Error(err)).Error("Got error disabling write deadline")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	sent := make(map[saga.ProgressEvent]bool)
+	for _, event := range saga.ProgressEvents(execution, execution.Transitions) {
+		if err := writeEvent(w, rc, event); err != nil {
+			lggr.With(zap.Error(err)).Error("Got error writing event")
+			return
+		}
+		if event.Type == saga.ProgressEventFinished {
+			return
+		}
+		sent[transitionOf(event)] = true
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			lggr.Info("Client closed the event stream")
+			return
+		case <-lifecycle.Stopping(ctx):
+			lggr.Info("Server is shutting down. Closing event stream")
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if sent[transitionOf(event)] {
+				continue
+			}
+			if err := writeEvent(w, rc, event); err != nil {
+				lggr.With(zap.Error(err)).Error("Got error writing event")
+				return
+			}
+			if event.Type == saga.ProgressEventFinished {
+				lggr.Info("Execution finished. Closing event stream")
+				return
+			}
+		}
+	}
+}
+
+// transitionOf identifies the transition of event regardless of the execution status it was published with.
+func transitionOf(event saga.ProgressEvent) saga.ProgressEvent {
+	event.Status = ""
+	event.At = event.At.UTC()
+	return event
+}
+
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, event saga.ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
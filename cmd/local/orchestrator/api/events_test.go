@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is an event read from a Server-Sent Events stream.
+type sseEvent struct {
+	name string
+	data saga.ProgressEvent
+}
+
+// readEvent reads the next event of the stream, failing on lines that are not part of the event framing.
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			require.NotEmpty(t, event.name, "events are terminated by a blank line")
+			return event
+		case strings.HasPrefix(line, "event: "):
+			event.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.data))
+		default:
+			t.Fatalf("unexpected line in event stream: %q", line)
+		}
+	}
+}
+
+// openStream requests the event stream of the execution identified by id from a running server,
+// as the stream needs a connection that supports write deadlines.
+func openStream(t *testing.T, server *testServer, id uuid.UUID) *http.Response {
+	t.Helper()
+	httpServer := httptest.NewServer(server.handler)
+	t.Cleanup(httpServer.Close)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/v1/executions/"+id.String()+"/events", nil)
+	require.NoError(t, err)
+	res, err := httpServer.Client().Do(r)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	return res
+}
+
+func TestHandlers_StreamExecutionEvents(t *testing.T) {
+	t.Run("should send the recorded transitions and end after the finished event", func(t *testing.T) {
+		server := newTestServer(t)
+		execution := server.insert(t, func(execution *saga.Execution) {
+			execution.Status = saga.ExecutionStatusCancelled
+			step, _ := execution.Workflow.Steps.GetStep("create_order")
+			execution.StepCancelled(step)
+		})
+
+		res := openStream(t, server, execution.ID)
+
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+		reader := bufio.NewReader(res.Body)
+		for _, want := range []struct {
+			name string
+			step string
+		}{
+			{name: saga.TransitionTypeRequested.String(), step: "create_order"},
+			{name: saga.TransitionTypeCancelled.String(), step: "create_order"},
+			{name: saga.ProgressEventFinished},
+		} {
+			event := readEvent(t, reader)
+			assert.Equal(t, want.name, event.name)
+			assert.Equal(t, want.name, event.data.Type, "the data has the type of the event")
+			assert.Equal(t, want.step, event.data.Step)
+			assert.Equal(t, execution.ID, event.data.ExecutionID)
+			assert.Equal(t, saga.ExecutionStatusCancelled, event.data.Status)
+		}
+		_, err := reader.ReadByte()
+		assert.Error(t, err, "the stream ends after the finished event")
+	})
+
+	t.Run("should stream the published events until the execution finishes", func(t *testing.T) {
+		server := newTestServer(t)
+		execution := server.insert(t)
+		ctx := context.Background()
+		reader := bufio.NewReader(openStream(t, server, execution.ID).Body)
+
+		requested := readEvent(t, reader)
+		assert.Equal(t, saga.TransitionTypeRequested.String(), requested.name)
+		// the handler subscribes before sending the recorded transitions, so nothing published from now on is missed
+		recorded := requested.data
+		recorded.Status = saga.ExecutionStatusCompensating
+		events := []saga.ProgressEvent{
+			recorded,
+			{ExecutionID: execution.ID, Type: saga.TransitionTypeFailed.String(), Step: "create_order", EventType: "order_creation_failed", Error: "out of stock", Status: saga.ExecutionStatusCompensated, At: time.Now().UTC()},
+			{ExecutionID: execution.ID, Type: saga.ProgressEventFinished, Status: saga.ExecutionStatusCompensated, At: time.Now().UTC()},
+		}
+		for _, event := range events {
+			require.NoError(t, server.broker.Publish(ctx, event))
+		}
+
+		failed := readEvent(t, reader)
+		assert.Equal(t, saga.TransitionTypeFailed.String(), failed.name, "events already sent are skipped")
+		assert.Equal(t, "out of stock", failed.data.Error)
+		finished := readEvent(t, reader)
+		assert.Equal(t, saga.ProgressEventFinished, finished.name)
+		assert.Equal(t, saga.ExecutionStatusCompensated, finished.data.Status)
+		_, err := reader.ReadByte()
+		assert.Error(t, err, "the stream ends after the finished event")
+	})
+
+	t.Run("should return not found for an unknown execution", func(t *testing.T) {
+		server := newTestServer(t)
+
+		res := server.serve(http.MethodGet, "/v1/executions/"+uuid.NewString()+"/events", "")
+
+		require.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, responses.TypeNotFound, decodeBody[responses.Problem](t, res).Type)
+	})
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/bmviniciuss/sagas-golang/pkg/structs"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ExecutionResponse struct {
+	ID           string                 `json:"id"`
+	Workflow     string                 `json:"workflow"`
+	Status       string                 `json:"status"`
+	CurrentStep  string                 `json:"current_step"`
+	Steps        []StepResponse         `json:"steps"`
+	Intervention *saga.Intervention     `json:"intervention"`
+	State        map[string]interface{} `json:"state"`
+	StartedBy    string                 `json:"started_by,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+type ExecutionSummaryResponse struct {
+	ID          string    `json:"id"`
+	Workflow    string    `json:"workflow"`
+	Status      string    `json:"status"`
+	CurrentStep string    `json:"current_step"`
+	BusinessKey string    `json:"business_key"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ExecutionList struct {
+	Content    []ExecutionSummaryResponse `json:"content"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+type ResumeRequest struct {
+	Step string `json:"step" validate:"required"`
+}
+
+type StepResponse struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// ListExecutions returns the executions matching the query parameters, newest created first.
+//
+// Accepted parameters: workflow, status, created_from and created_to (RFC 3339), correlation_id,
+// business_key, cursor (the next_cursor of the previous page) and limit.
+func (h *Handlers) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+	lggr.Info("Listing executions")
+
+	filter, fieldErrs := parseExecutionFilter(r)
+	if len(fieldErrs) > 0 {
+		lggr.Error("Got invalid execution filters")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, fieldErrs))
+		return
+	}
+
+	page, err := h.executionRepository.List(ctx, filter)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error listing executions")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+
+	res := ExecutionList{Content: make([]ExecutionSummaryResponse, len(page.Executions))}
+	for i, execution := range page.Executions {
+		res.Content[i] = ExecutionSummaryResponse{
+			ID:          execution.ID.String(),
+			Workflow:    execution.Workflow.Name,
+			Status:      execution.Status.String(),
+			CurrentStep: execution.CurrentStep,
+			BusinessKey: execution.BusinessKey,
+			CreatedAt:   execution.CreatedAt,
+			UpdatedAt:   execution.UpdatedAt,
+		}
+	}
+	if page.Next != nil {
+		res.NextCursor = page.Next.Encode()
+	}
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+func (h *Handlers) GetExecution(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+	lggr.Info("Getting execution by ID")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding execution id")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "id",
+				Message: "Invalid execution ID",
+			},
+		}))
+		return
+	}
+
+	execution, err := h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding execution")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if execution.IsEmpty() {
+		lggr.Error("Execution not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	res, err := h.toExecutionResponse(execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error redacting execution state")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+func (h *Handlers) CancelExecution(w http.ResponseWriter, r *http.Request) {
+	h.handleExecutionAction(w, r, "cancel execution", h.workflowService.Cancel)
+}
+
+func (h *Handlers) RetryStep(w http.ResponseWriter, r *http.Request) {
+	h.handleExecutionAction(w, r, "retry step", h.workflowService.RetryStep)
+}
+
+func (h *Handlers) ResumeExecution(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+
+	var req ResumeRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding request")
+		responses.RenderError(w, r, responses.ParseErrorToResponse(reqID, err))
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		lggr.With(zap.Error(err)).Error("Got error validating request")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, responses.ValidatorErrorToFieldError(err)))
+		return
+	}
+	h.handleExecutionAction(w, r, "resume execution", func(ctx context.Context, execution *saga.Execution) error {
+		return h.workflowService.ResumeFrom(ctx, execution, req.Step)
+	})
+}
+
+func (h *Handlers) toExecutionResponse(execution *saga.Execution) (ExecutionResponse, error) {
+	state, err := structs.Redact(execution.State, h.redactedFields)
+	if err != nil {
+		return ExecutionResponse{}, err
+	}
+	outcomes := execution.StepOutcomes()
+	steps := make([]StepResponse, len(outcomes))
+	for i, outcome := range outcomes {
+		steps[i] = StepResponse{
+			Name:      outcome.Step,
+			Status:    outcome.Status,
+			UpdatedAt: outcome.UpdatedAt,
+		}
+	}
+	return ExecutionResponse{
+		ID:           execution.ID.String(),
+		Workflow:     execution.Workflow.Name,
+		Status:       execution.Status.String(),
+		CurrentStep:  execution.CurrentStep,
+		Steps:        steps,
+		Intervention: execution.Intervention,
+		State:        state,
+		StartedBy:    execution.StartedBy,
+		CreatedAt:    execution.CreatedAt,
+		UpdatedAt:    execution.UpdatedAt,
+	}, nil
+}
+
+func parseExecutionFilter(r *http.Request) (saga.ExecutionFilter, []responses.FieldError) {
+	var (
+		query     = r.URL.Query()
+		filter    = saga.ExecutionFilter{}
+		fieldErrs = parsePage(r, &filter)
+	)
+	filter.WorkflowName = query.Get("workflow")
+	filter.BusinessKey = query.Get("business_key")
+
+	if status := query.Get("status"); status != "" {
+		filter.Status = saga.ExecutionStatus(status)
+		if !filter.Status.IsValid() {
+			fieldErrs = append(fieldErrs, responses.FieldError{Field: "status", Message: "Invalid execution status"})
+		}
+	}
+	if correlationID := query.Get("correlation_id"); correlationID != "" {
+		id, err := uuid.Parse(correlationID)
+		if err != nil {
+			fieldErrs = append(fieldErrs, responses.FieldError{Field: "correlation_id", Message: "Invalid correlation ID"})
+		} else {
+			filter.CorrelationID = &id
+		}
+	}
+	for field, dest := range map[string]**time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+	} {
+		value := query.Get(field)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			fieldErrs = append(fieldErrs, responses.FieldError{Field: field, Message: "Invalid RFC 3339 date"})
+			continue
+		}
+		*dest = &t
+	}
+	return filter, fieldErrs
+}
+
+// parsePage reads the cursor and limit query parameters into filter.
+func parsePage(r *http.Request, filter *saga.ExecutionFilter) []responses.FieldError {
+	var (
+		query     = r.URL.Query()
+		fieldErrs = make([]responses.FieldError, 0)
+	)
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := saga.DecodeCursor(cursor)
+		if err != nil {
+			fieldErrs = append(fieldErrs, responses.FieldError{Field: "cursor", Message: "Invalid cursor"})
+		}
+		filter.After = after
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > saga.MaxPageSize {
+			fieldErrs = append(fieldErrs, responses.FieldError{
+				Field:   "limit",
+				Message: fmt.Sprintf("Must be a number between 1 and %d", saga.MaxPageSize),
+			})
+		}
+		filter.Limit = n
+	}
+	return fieldErrs
+}
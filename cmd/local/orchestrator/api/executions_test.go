@@ -0,0 +1,416 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/bmviniciuss/sagas-golang/pkg/structs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers_GetExecution(t *testing.T) {
+	t.Run("should return the execution with its steps", func(t *testing.T) {
+		server := newTestServer(t)
+		execution := server.insert(t, func(execution *saga.Execution) {
+			execution.StartedBy = "operator"
+		})
+
+		res := server.serve(http.MethodGet, "/v1/executions/"+execution.ID.String(), "")
+
+		require.Equal(t, http.StatusOK, res.Code)
+		body := decodeBody[ExecutionResponse](t, res)
+		assert.Equal(t, execution.ID.String(), body.ID)
+		assert.Equal(t, "create_order_v1", body.Workflow)
+		assert.Equal(t, saga.ExecutionStatusRunning.String(), body.Status)
+		assert.Equal(t, "create_order", body.CurrentStep)
+		assert.Equal(t, "operator", body.StartedBy)
+		require.Len(t, body.Steps, 2)
+		assert.Equal(t, "create_order", body.Steps[0].Name)
+		assert.Equal(t, "requested", body.Steps[0].Status)
+		assert.Equal(t, "verify_customer", body.Steps[1].Name)
+		assert.Equal(t, "pending", body.Steps[1].Status)
+	})
+
+	t.Run("should redact the configured fields of the state", func(t *testing.T) {
+		server := newTestServer(t, "input.card", "input.items.price")
+		execution := server.insert(t, func(execution *saga.Execution) {
+			execution.SetState("input", map[string]interface{}{
+				"customer_id": "customer",
+				"card":        map[string]interface{}{"number": "4111111111111111"},
+				"items": []interface{}{
+					map[string]interface{}{"sku": "A", "price": 10.5},
+					map[string]interface{}{"sku": "B", "price": 2.0},
+				},
+			})
+		})
+
+		res := server.serve(http.MethodGet, "/v1/executions/"+execution.ID.String(), "")
+
+		require.Equal(t, http.StatusOK, res.Code)
+		body := decodeBody[ExecutionResponse](t, res)
+		assert.Equal(t, map[string]interface{}{
+			"input": map[string]interface{}{
+				"customer_id": "customer",
+				"card":        structs.RedactedValue,
+				"items": []interface{}{
+					map[string]interface{}{"sku": "A", "price": structs.RedactedValue},
+					map[string]interface{}{"sku": "B", "price": structs.RedactedValue},
+				},
+			},
+		}, body.State)
+	})
+
+	t.Run("should return not found for an unknown execution", func(t *testing.T) {
+		server := newTestServer(t)
+
+		res := server.serve(http.MethodGet, "/v1/executions/"+uuid.NewString(), "")
+
+		require.Equal(t, http.StatusNotFound, res.Code)
+		problem := decodeBody[responses.Problem](t, res)
+		assert.Equal(t, responses.TypeNotFound, problem.Type)
+		assert.Equal(t, testRequestID, problem.RequestID)
+	})
+
+	t.Run("should reject an invalid execution ID", func(t *testing.T) {
+		server := newTestServer(t)
+
+		res := server.serve(http.MethodGet, "/v1/executions/123", "")
+
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, []string{"id"}, fieldsOf(decodeBody[responses.Problem](t, res)))
+	})
+}
+
+func TestHandlers_ListExecutions(t *testing.T) {
+	var (
+		server = newTestServer(t)
+		now    = time.Now().UTC().Truncate(time.Second)
+		at     = func(ago time.Duration) func(execution *saga.Execution) {
+			return func(execution *saga.Execution) {
+				execution.CreatedAt = now.Add(-ago)
+			}
+		}
+		oldest = server.insert(t, at(3*time.Hour), func(execution *saga.Execution) {
+			execution.Status = saga.ExecutionStatusCompleted
+			execution.BusinessKey = "customer-a"
+		})
+		middle = server.insert(t, at(2*time.Hour), func(execution *saga.Execution) {
+			execution.BusinessKey = "customer-b"
+		})
+		newest = server.insert(t, at(time.Hour), func(execution *saga.Execution) {
+			execution.BusinessKey = "customer-a"
+		})
+	)
+	list := func(t *testing.T, query url.Values) []string {
+		t.Helper()
+		res := server.serve(http.MethodGet, "/v1/executions?"+query.Encode(), "")
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		body := decodeBody[ExecutionList](t, res)
+		ids := make([]string, len(body.Content))
+		for i, execution := range body.Content {
+			ids[i] = execution.ID
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []*saga.Execution
+	}{
+		{name: "should list every execution newest first", query: url.Values{}, want: []*saga.Execution{newest, middle, oldest}},
+		{name: "should filter by workflow", query: url.Values{"workflow": {"create_order_v1"}}, want: []*saga.Execution{newest, middle, oldest}},
+		{name: "should filter by an unknown workflow", query: url.Values{"workflow": {"other_v1"}}, want: []*saga.Execution{}},
+		{name: "should filter by status", query: url.Values{"status": {"completed"}}, want: []*saga.Execution{oldest}},
+		{name: "should filter by business key", query: url.Values{"business_key": {"customer-a"}}, want: []*saga.Execution{newest, oldest}},
+		{name: "should filter by correlation ID", query: url.Values{"correlation_id": {middle.ID.String()}}, want: []*saga.Execution{middle}},
+		{
+			name: "should filter by creation time",
+			query: url.Values{
+				"created_from": {now.Add(-150 * time.Minute).Format(time.RFC3339)},
+				"created_to":   {now.Add(-time.Hour).Format(time.RFC3339)},
+			},
+			want: []*saga.Execution{middle},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make([]string, len(tt.want))
+			for i, execution := range tt.want {
+				want[i] = execution.ID.String()
+			}
+
+			assert.Equal(t, want, list(t, tt.query))
+		})
+	}
+
+	t.Run("should page through the executions with the next cursor", func(t *testing.T) {
+		res := server.serve(http.MethodGet, "/v1/executions?limit=2", "")
+		require.Equal(t, http.StatusOK, res.Code)
+		first := decodeBody[ExecutionList](t, res)
+		require.Len(t, first.Content, 2)
+		assert.Equal(t, newest.ID.String(), first.Content[0].ID)
+		assert.Equal(t, middle.ID.String(), first.Content[1].ID)
+		require.NotEmpty(t, first.NextCursor)
+
+		res = server.serve(http.MethodGet, "/v1/executions?limit=2&cursor="+url.QueryEscape(first.NextCursor), "")
+		require.Equal(t, http.StatusOK, res.Code)
+		second := decodeBody[ExecutionList](t, res)
+		require.Len(t, second.Content, 1)
+		assert.Equal(t, oldest.ID.String(), second.Content[0].ID)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("should return the summary of the executions", func(t *testing.T) {
+		res := server.serve(http.MethodGet, "/v1/executions?limit=1", "")
+
+		require.Equal(t, http.StatusOK, res.Code)
+		body := decodeBody[ExecutionList](t, res)
+		require.Len(t, body.Content, 1)
+		assert.Equal(t, ExecutionSummaryResponse{
+			ID:          newest.ID.String(),
+			Workflow:    "create_order_v1",
+			Status:      "running",
+			CurrentStep: "create_order",
+			BusinessKey: "customer-a",
+			CreatedAt:   newest.CreatedAt,
+			UpdatedAt:   body.Content[0].UpdatedAt,
+		}, body.Content[0])
+	})
+
+	invalid := []struct {
+		name  string
+		query url.Values
+		want  []string
+	}{
+		{name: "should reject an unknown status", query: url.Values{"status": {"unknown"}}, want: []string{"status"}},
+		{name: "should reject an invalid correlation ID", query: url.Values{"correlation_id": {"123"}}, want: []string{"correlation_id"}},
+		{name: "should reject a creation time that is not RFC 3339", query: url.Values{"created_from": {"2024-01-01"}}, want: []string{"created_from"}},
+		{name: "should reject an invalid cursor", query: url.Values{"cursor": {"abc"}}, want: []string{"cursor"}},
+		{name: "should reject a limit that is not a number", query: url.Values{"limit": {"ten"}}, want: []string{"limit"}},
+		{name: "should reject a limit out of range", query: url.Values{"limit": {"0"}}, want: []string{"limit"}},
+		{name: "should reject a limit above the maximum page size", query: url.Values{"limit": {"1000"}}, want: []string{"limit"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			res := server.serve(http.MethodGet, "/v1/executions?"+tt.query.Encode(), "")
+
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			problem := decodeBody[responses.Problem](t, res)
+			assert.Equal(t, responses.TypeValidation, problem.Type)
+			assert.Equal(t, tt.want, fieldsOf(problem))
+		})
+	}
+}
+
+func TestParseExecutionFilter(t *testing.T) {
+	t.Run("should parse every filter", func(t *testing.T) {
+		var (
+			id    = uuid.New()
+			from  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			to    = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			after = &saga.Cursor{CreatedAt: from, ID: id}
+			query = url.Values{
+				"workflow":       {"create_order_v1"},
+				"status":         {"compensating"},
+				"business_key":   {"customer"},
+				"correlation_id": {id.String()},
+				"created_from":   {from.Format(time.RFC3339)},
+				"created_to":     {to.Format(time.RFC3339)},
+				"cursor":         {after.Encode()},
+				"limit":          {"10"},
+			}
+		)
+		r, _ := http.NewRequest(http.MethodGet, "/v1/executions?"+query.Encode(), nil)
+
+		filter, fieldErrs := parseExecutionFilter(r)
+
+		assert.Empty(t, fieldErrs)
+		assert.Equal(t, saga.ExecutionFilter{
+			WorkflowName:  "create_order_v1",
+			Status:        saga.ExecutionStatusCompensating,
+			BusinessKey:   "customer",
+			CorrelationID: &id,
+			CreatedFrom:   &from,
+			CreatedTo:     &to,
+			After:         after,
+			Limit:         10,
+		}, filter)
+	})
+
+	t.Run("should report every invalid filter", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/v1/executions?status=unknown&correlation_id=1&created_to=today&cursor=abc&limit=-1", nil)
+
+		_, fieldErrs := parseExecutionFilter(r)
+
+		fields := make([]string, len(fieldErrs))
+		for i, err := range fieldErrs {
+			fields[i] = err.Field
+		}
+		assert.ElementsMatch(t, []string{"status", "correlation_id", "created_to", "cursor", "limit"}, fields)
+	})
+}
+
+// waitingVerification makes the execution wait for the reply of verify_customer after create_order succeeded.
+func waitingVerification(execution *saga.Execution) {
+	createOrder, _ := execution.Workflow.Steps.GetStep("create_order")
+	verifyCustomer, _ := execution.Workflow.Steps.GetStep("verify_customer")
+	execution.StepReplied(createOrder, events.NewEvent("order_created", "orders", nil))
+	execution.StepRequested(verifyCustomer, saga.REQUEST_ACTION_TYPE)
+}
+
+func withStatus(status saga.ExecutionStatus) func(execution *saga.Execution) {
+	return func(execution *saga.Execution) {
+		execution.Status = status
+	}
+}
+
+func TestHandlers_ExecutionActions(t *testing.T) {
+	tests := []struct {
+		name             string
+		path             string
+		body             string
+		changes          []func(execution *saga.Execution)
+		wantStatus       saga.ExecutionStatus
+		wantCurrentStep  string
+		wantDestinations []string
+	}{
+		{
+			name:             "should cancel a running execution and compensate the completed steps",
+			path:             "cancel",
+			changes:          []func(execution *saga.Execution){waitingVerification},
+			wantStatus:       saga.ExecutionStatusCancelling,
+			wantCurrentStep:  "create_order",
+			wantDestinations: []string{"service.orders.request"},
+		},
+		{
+			name:             "should cancel a running execution without completed steps and publish its outcome",
+			path:             "cancel",
+			wantStatus:       saga.ExecutionStatusCancelled,
+			wantCurrentStep:  "create_order",
+			wantDestinations: []string{"saga.create_order_v1.response"},
+		},
+		{
+			name:             "should retry the step the execution waits for",
+			path:             "retry",
+			changes:          []func(execution *saga.Execution){waitingVerification},
+			wantStatus:       saga.ExecutionStatusRunning,
+			wantCurrentStep:  "verify_customer",
+			wantDestinations: []string{"service.customers.request"},
+		},
+		{
+			name:             "should publish again the outcome of a finished execution",
+			path:             "retry",
+			changes:          []func(execution *saga.Execution){withStatus(saga.ExecutionStatusCompensated)},
+			wantStatus:       saga.ExecutionStatusCompensated,
+			wantCurrentStep:  "create_order",
+			wantDestinations: []string{"saga.create_order_v1.response"},
+		},
+		{
+			name:             "should resume a running execution from a step",
+			path:             "resume",
+			body:             `{"step":"verify_customer"}`,
+			wantStatus:       saga.ExecutionStatusRunning,
+			wantCurrentStep:  "verify_customer",
+			wantDestinations: []string{"service.customers.request"},
+		},
+		{
+			name:             "should resume a compensating execution from the compensation of a step",
+			path:             "resume",
+			body:             `{"step":"create_order"}`,
+			changes:          []func(execution *saga.Execution){waitingVerification, withStatus(saga.ExecutionStatusCompensating)},
+			wantStatus:       saga.ExecutionStatusCompensating,
+			wantCurrentStep:  "create_order",
+			wantDestinations: []string{"service.orders.request"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			execution := server.insert(t, tt.changes...)
+
+			res := server.serve(http.MethodPost, "/v1/executions/"+execution.ID.String()+"/"+tt.path, tt.body)
+
+			require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+			assert.Equal(t, ExecutionStatusResponse{ID: execution.ID.String(), Status: tt.wantStatus.String()}, decodeBody[ExecutionStatusResponse](t, res))
+			saved := server.find(t, execution.ID.String())
+			assert.Equal(t, tt.wantStatus, saved.Status)
+			assert.Equal(t, tt.wantCurrentStep, saved.CurrentStep)
+			assert.Equal(t, tt.wantDestinations, server.publisher.destinations)
+		})
+	}
+
+	conflicts := []struct {
+		name    string
+		path    string
+		body    string
+		changes []func(execution *saga.Execution)
+	}{
+		{name: "should not cancel a compensating execution", path: "cancel", changes: []func(execution *saga.Execution){waitingVerification, withStatus(saga.ExecutionStatusCompensating)}},
+		{name: "should not cancel a finished execution", path: "cancel", changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusCompleted)}},
+		{
+			name: "should not retry a finished execution whose outcome was published",
+			path: "retry",
+			changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusCompleted), func(execution *saga.Execution) {
+				execution.OutcomePublished = true
+			}},
+		},
+		{name: "should not retry an execution that needs intervention", path: "retry", changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusNeedsIntervention)}},
+		{name: "should not resume a cancelled execution", path: "resume", body: `{"step":"create_order"}`, changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusCancelled)}},
+		{name: "should not resume an execution that needs intervention", path: "resume", body: `{"step":"create_order"}`, changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusNeedsIntervention)}},
+	}
+	for _, tt := range conflicts {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			execution := server.insert(t, tt.changes...)
+
+			res := server.serve(http.MethodPost, "/v1/executions/"+execution.ID.String()+"/"+tt.path, tt.body)
+
+			require.Equal(t, http.StatusConflict, res.Code, res.Body.String())
+			problem := decodeBody[responses.Problem](t, res)
+			assert.Equal(t, responses.TypeInvalidState, problem.Type)
+			assert.Equal(t, testRequestID, problem.RequestID)
+			assert.Equal(t, execution.Status, server.find(t, execution.ID.String()).Status, "the execution is not changed")
+			assert.Empty(t, server.publisher.destinations)
+		})
+	}
+
+	t.Run("should return not found for an unknown execution", func(t *testing.T) {
+		for _, path := range []string{"cancel", "retry"} {
+			server := newTestServer(t)
+
+			res := server.serve(http.MethodPost, "/v1/executions/"+uuid.NewString()+"/"+path, "")
+
+			require.Equal(t, http.StatusNotFound, res.Code, path)
+		}
+	})
+
+	invalid := []struct {
+		name    string
+		body    string
+		changes []func(execution *saga.Execution)
+	}{
+		{name: "should not resume without a step", body: `{}`},
+		{name: "should not resume from an unknown step", body: `{"step":"ship_order"}`},
+		{name: "should not resume a compensating execution from a step that is not compensable", body: `{"step":"verify_customer"}`, changes: []func(execution *saga.Execution){withStatus(saga.ExecutionStatusCompensating)}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			execution := server.insert(t, tt.changes...)
+
+			res := server.serve(http.MethodPost, "/v1/executions/"+execution.ID.String()+"/resume", tt.body)
+
+			require.Equal(t, http.StatusBadRequest, res.Code, res.Body.String())
+			assert.Equal(t, []string{"step"}, fieldsOf(decodeBody[responses.Problem](t, res)))
+			assert.Empty(t, server.publisher.destinations)
+		})
+	}
+}
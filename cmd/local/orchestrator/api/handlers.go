@@ -2,28 +2,77 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/bmviniciuss/sagas-golang/internal/ratelimit"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/internal/webhooks"
 	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	goval "github.com/go-playground/validator/v10"
 
 	"go.uber.org/zap"
 )
 
+const (
+	// createOrderWorkflow is the workflow started by the deprecated create orders route.
+	createOrderWorkflow     = "create_order_v1"
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+	callbackURLHeader       = "Callback-URL"
+	maxCallbackURLLength    = 2048
+	// tooManyExecutionsRetryAfter is how long clients are asked to wait when a workflow has too many active executions.
+	tooManyExecutionsRetryAfter = 5 * time.Second
+)
+
+// problems maps the domain errors to the responses of the API.
+var problems = responses.NewMapper().
+	InvalidState(saga.ErrNoIntervention, saga.ErrNotCancellable, saga.ErrNotResumable).
+	InvalidField("step", saga.ErrStepNotFound, saga.ErrStepNotCompensable).
+	Conflict(saga.ErrDuplicateIdempotencyKey, saga.ErrConcurrentUpdate).
+	TooManyRequests(saga.ErrTooManyExecutions)
+
 type HandlersPort interface {
 	Health(w http.ResponseWriter, r *http.Request)
 	CreateOrder(w http.ResponseWriter, r *http.Request)
+	StartExecution(w http.ResponseWriter, r *http.Request)
+	ListInterventions(w http.ResponseWriter, r *http.Request)
+	RetryCompensation(w http.ResponseWriter, r *http.Request)
+	ResolveIntervention(w http.ResponseWriter, r *http.Request)
+	ForceComplete(w http.ResponseWriter, r *http.Request)
+	GetExecution(w http.ResponseWriter, r *http.Request)
+	ListExecutions(w http.ResponseWriter, r *http.Request)
+	CancelExecution(w http.ResponseWriter, r *http.Request)
+	RetryStep(w http.ResponseWriter, r *http.Request)
+	ResumeExecution(w http.ResponseWriter, r *http.Request)
+	StreamExecutionEvents(w http.ResponseWriter, r *http.Request)
+	GetExecutionTimeline(w http.ResponseWriter, r *http.Request)
+	ListWorkflows(w http.ResponseWriter, r *http.Request)
+	GetWorkflow(w http.ResponseWriter, r *http.Request)
+	GetWorkflowDiagram(w http.ResponseWriter, r *http.Request)
 }
 
 type Handlers struct {
-	logger             *zap.SugaredLogger
-	workflowRepository saga.WorkflowRepository
-	workflowService    saga.ServicePort
-	validator          *goval.Validate
+	logger              *zap.SugaredLogger
+	workflowRepository  saga.WorkflowRepository
+	executionRepository saga.ExecutionRepository
+	workflowService     saga.ServicePort
+	validator           *goval.Validate
+	// redactedFields are the dot separated paths of the execution state hidden from API responses.
+	redactedFields []string
+	waiter         *saga.Waiter
+	progressBroker saga.ProgressBroker
+	// maxWait is the longest a start request can wait for its execution to finish.
+	maxWait time.Duration
+	// callbackURLs restricts the Callback-URL header of the start requests.
+	callbackURLs webhooks.Allowlist
 }
 
 var (
@@ -33,14 +82,26 @@ var (
 func NewHandlers(
 	logger *zap.SugaredLogger,
 	workflowRepository saga.WorkflowRepository,
+	executionRepository saga.ExecutionRepository,
 	workflowService saga.ServicePort,
 	validator *goval.Validate,
+	redactedFields []string,
+	waiter *saga.Waiter,
+	maxWait time.Duration,
+	progressBroker saga.ProgressBroker,
+	callbackURLs webhooks.Allowlist,
 ) *Handlers {
 	return &Handlers{
-		logger:             logger,
-		workflowRepository: workflowRepository,
-		workflowService:    workflowService,
-		validator:          validator,
+		logger:              logger,
+		workflowRepository:  workflowRepository,
+		executionRepository: executionRepository,
+		workflowService:     workflowService,
+		validator:           validator,
+		redactedFields:      redactedFields,
+		waiter:              waiter,
+		maxWait:             maxWait,
+		progressBroker:      progressBroker,
+		callbackURLs:        callbackURLs,
 	}
 }
 
@@ -62,80 +123,178 @@ func StructToMap(data interface{}) (map[string]interface{}, error) {
 	return m, nil
 }
 
-type CreateOrderRequest struct {
-	CustomerID   string `json:"customer_id" validate:"required,uuid"`
-	Card         string `json:"card" validate:"required"`
-	Amount       *int64 `json:"amount" validate:"required,gt=0"`
-	CurrencyCode string `json:"currency_code" validate:"required"`
-	Items        []Item `json:"items" validate:"required,min=1,dive"`
+// CreateOrder starts the create_order_v1 workflow.
+// Deprecated: use POST /v1/workflows/create_order_v1/executions.
+func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	h.startExecution(w, r, createOrderWorkflow, http.StatusOK)
 }
 
-type Item struct {
-	ID        string `json:"id" validate:"required,uuid"`
-	Quantity  *int32 `json:"quantity" validate:"required,gt=0"`
-	UnitPrice *int64 `json:"unit_price" validate:"required,gt=0"`
+// StartExecution starts an execution of the workflow named by the name URL param.
+func (h *Handlers) StartExecution(w http.ResponseWriter, r *http.Request) {
+	h.startExecution(w, r, chi.URLParam(r, "name"), http.StatusCreated)
 }
 
-func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) startExecution(w http.ResponseWriter, r *http.Request, workflowName string, status int) {
 	var (
 		lggr     = h.logger
 		ctx      = r.Context()
 		reqID, _ = appcontext.RequestID(r.Context())
 	)
-	lggr = lggr.With("request_id", reqID)
-	lggr.Info("Creating order")
+	lggr = lggr.With("request_id", reqID, "workflow", workflowName)
+	lggr.Info("Starting workflow execution")
 
-	var req CreateOrderRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		lggr.With(zap.Error(err)).Error("Got error decoding request")
-		errRes := responses.ParseErrorToResponse(reqID, err)
+	workflow, err := h.workflowRepository.Find(ctx, workflowName)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding workflow")
+		errRes := responses.NewInternalServerErrorResponse(reqID)
 		responses.RenderError(w, r, errRes)
 		return
 	}
-
-	err := h.validator.StructCtx(ctx, req)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error validating request")
-		fieldErrs := responses.ValidatorErrorToFieldError(err)
-		errRes := responses.NewBadRequestErrorResponse(reqID, fieldErrs)
+	if workflow.IsEmpty() {
+		lggr.Error("workflow not found")
+		errRes := responses.NewNotFoundErrorResponse(reqID)
 		responses.RenderError(w, r, errRes)
 		return
 	}
 
-	lggr.Infof("Received request: %+v", req)
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		lggr.Error("Idempotency key is too long")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   idempotencyKeyHeader,
+				Message: fmt.Sprintf("Must have at most %d characters", maxIdempotencyKeyLength),
+			},
+		}))
+		return
+	}
 
-	createOrderWorkflow, err := h.workflowRepository.Find(ctx, "create_order_v1")
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error finding workflow")
-		errRes := responses.NewInternalServerErrorResponse(reqID)
-		responses.RenderError(w, r, errRes)
+	callbackURL := r.Header.Get(callbackURLHeader)
+	if callbackURL != "" && !isCallbackURL(callbackURL) {
+		lggr.Error("Callback URL is invalid")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   callbackURLHeader,
+				Message: fmt.Sprintf("Must be an absolute http or https URL with at most %d characters", maxCallbackURLLength),
+			},
+		}))
+		return
+	}
+	if callbackURL != "" && !h.callbackURLs.Allows(callbackURL) {
+		lggr.Errorf("Callback URL [%s] is not allowed", callbackURL)
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   callbackURLHeader,
+				Message: "Must have an allowed scheme and host",
+			},
+		}))
 		return
 	}
 
-	data, err := StructToMap(req)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error decoding create order request to map")
-		errRes := responses.NewInternalServerErrorResponse(reqID)
-		responses.RenderError(w, r, errRes)
+	wait, fieldErrs := parseWait(r, h.maxWait)
+	if len(fieldErrs) > 0 {
+		lggr.Error("Got invalid wait parameter")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, fieldErrs))
 		return
 	}
 
-	if createOrderWorkflow.IsEmpty() {
-		lggr.Error("workflow not found")
-		errRes := responses.NewInternalServerErrorResponse(reqID)
+	data, errRes, ok := h.decodeInput(r, workflow)
+	if !ok {
 		responses.RenderError(w, r, errRes)
 		return
 	}
 
-	globalID, err := h.workflowService.Start(r.Context(), createOrderWorkflow, data)
+	principal, _ := auth.PrincipalFrom(ctx)
+	globalID, err := h.workflowService.Start(ctx, workflow, data, saga.StartOptions{
+		IdempotencyKey: idempotencyKey,
+		CallbackURL:    callbackURL,
+		StartedBy:      principal.Subject,
+	})
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error starting workflow")
-		errRes := responses.NewInternalServerErrorResponse(reqID)
-		responses.RenderError(w, r, errRes)
+		if errors.Is(err, saga.ErrTooManyExecutions) {
+			ratelimit.SetRetryAfter(w, tooManyExecutionsRetryAfter)
+		}
+		responses.RenderError(w, r, problems.Problem(reqID, err))
 		return
 	}
 
 	lggr.Infof("Successfully started workflow with global ID: %s", globalID.String())
-	w.WriteHeader(http.StatusOK)
+	if wait > 0 {
+		execution, finished, err := h.waitExecution(ctx, *globalID, wait)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error waiting for execution to finish")
+			responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+			return
+		}
+		if finished {
+			res, err := h.toExecutionResponse(execution)
+			if err != nil {
+				lggr.With(zap.Error(err)).Error("Got error redacting execution state")
+				responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			render.JSON(w, r, res)
+			return
+		}
+		lggr.Infof("Execution did not finish in %s", wait)
+		status = http.StatusAccepted
+	}
+
+	if status != http.StatusOK {
+		w.Header().Set("Location", fmt.Sprintf("/v1/executions/%s", globalID.String()))
+	}
+	w.WriteHeader(status)
 	render.JSON(w, r, map[string]string{"id": globalID.String()})
 }
+
+func isCallbackURL(value string) bool {
+	if len(value) > maxCallbackURLLength {
+		return false
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// decodeInput decodes the request body into the workflow input and validates it.
+// When it is not ok, the returned error response must be rendered.
+func (h *Handlers) decodeInput(r *http.Request, workflow *saga.Workflow) (map[string]interface{}, responses.Problem, bool) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+
+	if workflow.NewInput == nil {
+		var data map[string]interface{}
+		if err := render.DecodeJSON(r.Body, &data); err != nil {
+			lggr.With(zap.Error(err)).Error("Got error decoding request")
+			return nil, responses.ParseErrorToResponse(reqID, err), false
+		}
+		return data, responses.Problem{}, true
+	}
+
+	input := workflow.NewInput()
+	if err := render.DecodeJSON(r.Body, input); err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding request")
+		return nil, responses.ParseErrorToResponse(reqID, err), false
+	}
+
+	err := h.validator.StructCtx(ctx, input)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error validating request")
+		fieldErrs := responses.ValidatorErrorToFieldError(err)
+		return nil, responses.NewBadRequestErrorResponse(reqID, fieldErrs), false
+	}
+
+	data, err := StructToMap(input)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding workflow input to map")
+		return nil, responses.NewInternalServerErrorResponse(reqID), false
+	}
+	return data, responses.Problem{}, true
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/executions"
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/workflows"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/progress"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/internal/webhooks"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testRequestID = "request-id"
+
+type payloadBuilderStub struct{}
+
+func (payloadBuilderStub) Build(_ context.Context, _ *saga.Execution, action saga.ActionType) (map[string]interface{}, error) {
+	return map[string]interface{}{"action": action.String()}, nil
+}
+
+type publisherMock struct {
+	mu           sync.Mutex
+	destinations []string
+}
+
+func (p *publisherMock) Publish(_ context.Context, destination string, _ []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.destinations = append(p.destinations, destination)
+	return nil
+}
+
+func newTestWorkflow() *saga.Workflow {
+	return &saga.Workflow{
+		Name:            "create_order_v1",
+		ReplyChannel:    "saga.create_order_v1.response",
+		BusinessKeyPath: "customer_id",
+		Steps: saga.NewStepList(
+			&saga.StepData{Name: "create_order", Compensable: true, PayloadBuilder: payloadBuilderStub{}, StepContract: contracts.CreateOrder},
+			&saga.StepData{Name: "verify_customer", PayloadBuilder: payloadBuilderStub{}, StepContract: contracts.VerifyCustomer},
+		),
+	}
+}
+
+// testServer serves the API routes over in memory repositories.
+type testServer struct {
+	handler    http.Handler
+	workflow   *saga.Workflow
+	executions *executions.InmemRepository
+	broker     *progress.InmemBroker
+	publisher  *publisherMock
+}
+
+func newTestServer(t *testing.T, redactedFields ...string) *testServer {
+	t.Helper()
+	var (
+		lggr       = zap.NewNop().Sugar()
+		workflow   = newTestWorkflow()
+		repository = executions.NewInmemRepository()
+		broker     = progress.NewInmemBroker()
+		publisher  = &publisherMock{}
+		service    = saga.NewService(lggr, repository, publisher)
+	)
+	handlers := NewHandlers(
+		lggr,
+		workflows.NewInmemRepository([]saga.Workflow{*workflow}),
+		repository,
+		service,
+		validator.New(),
+		redactedFields,
+		nil,
+		0,
+		broker,
+		webhooks.Allowlist{},
+	)
+	return &testServer{
+		handler:    NewRouter(handlers, nil, nil, NewOpenAPI([]saga.Workflow{*workflow})).Build(),
+		workflow:   workflow,
+		executions: repository,
+		broker:     broker,
+		publisher:  publisher,
+	}
+}
+
+// insert saves a running execution waiting for the reply of the first step, after applying the given changes.
+func (s *testServer) insert(t *testing.T, changes ...func(execution *saga.Execution)) *saga.Execution {
+	t.Helper()
+	execution := saga.NewExecution(s.workflow)
+	step, _ := s.workflow.Steps.GetStep("create_order")
+	execution.StepRequested(step, saga.REQUEST_ACTION_TYPE)
+	for _, change := range changes {
+		change(execution)
+	}
+	require.NoError(t, s.executions.Insert(context.Background(), execution, 0))
+	return execution
+}
+
+// find returns the saved state of the execution identified by id.
+func (s *testServer) find(t *testing.T, id string) *saga.Execution {
+	t.Helper()
+	execution, err := s.executions.Find(context.Background(), id)
+	require.NoError(t, err)
+	return execution
+}
+
+func (s *testServer) serve(method, target, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	r := httptest.NewRequest(method, target, reader)
+	r.Header.Set("X-Request-ID", testRequestID)
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	res := httptest.NewRecorder()
+	s.handler.ServeHTTP(res, r)
+	return res
+}
+
+func decodeBody[T any](t *testing.T, res *httptest.ResponseRecorder) T {
+	t.Helper()
+	var body T
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), res.Body.String())
+	return body
+}
+
+func fieldsOf(problem responses.Problem) []string {
+	fields := make([]string, len(problem.Errors))
+	for i, err := range problem.Errors {
+		fields[i] = err.Field
+	}
+	return fields
+}
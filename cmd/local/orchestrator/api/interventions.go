@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type InterventionResponse struct {
+	ExecutionID string    `json:"execution_id"`
+	Workflow    string    `json:"workflow"`
+	Step        string    `json:"step"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InterventionList struct {
+	Content    []InterventionResponse `json:"content"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+type ExecutionStatusResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (h *Handlers) ListInterventions(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+	lggr.Info("Listing executions that need intervention")
+
+	filter := saga.ExecutionFilter{Status: saga.ExecutionStatusNeedsIntervention}
+	if fieldErrs := parsePage(r, &filter); len(fieldErrs) > 0 {
+		lggr.Error("Got invalid pagination parameters")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, fieldErrs))
+		return
+	}
+
+	page, err := h.executionRepository.List(ctx, filter)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error listing executions")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+
+	res := InterventionList{Content: make([]InterventionResponse, 0, len(page.Executions))}
+	if page.Next != nil {
+		res.NextCursor = page.Next.Encode()
+	}
+	for _, execution := range page.Executions {
+		if execution.Intervention == nil {
+			continue
+		}
+		res.Content = append(res.Content, InterventionResponse{
+			ExecutionID: execution.ID.String(),
+			Workflow:    execution.Workflow.Name,
+			Step:        execution.Intervention.Step,
+			Error:       execution.Intervention.Error,
+			Attempts:    execution.Intervention.Attempts,
+			CreatedAt:   execution.Intervention.CreatedAt,
+		})
+	}
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+func (h *Handlers) RetryCompensation(w http.ResponseWriter, r *http.Request) {
+	h.handleExecutionAction(w, r, "retry compensation", h.workflowService.RetryCompensation)
+}
+
+func (h *Handlers) ResolveIntervention(w http.ResponseWriter, r *http.Request) {
+	h.handleExecutionAction(w, r, "resolve intervention", h.workflowService.ResolveIntervention)
+}
+
+func (h *Handlers) ForceComplete(w http.ResponseWriter, r *http.Request) {
+	h.handleExecutionAction(w, r, "force complete", h.workflowService.ForceComplete)
+}
+
+// handleExecutionAction runs an operator action on the execution identified by the id URL param.
+func (h *Handlers) handleExecutionAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	fn func(ctx context.Context, execution *saga.Execution) error,
+) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding execution id")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "id",
+				Message: "Invalid execution ID",
+			},
+		}))
+		return
+	}
+	lggr = lggr.With("execution_id", id.String())
+	lggr.Infof("Operator requested to %s", action)
+
+	execution, err := h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding execution")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if execution.IsEmpty() {
+		lggr.Error("Execution not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	err = fn(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Errorf("Got error trying to %s", action)
+		responses.RenderError(w, r, problems.Problem(reqID, err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, ExecutionStatusResponse{
+		ID:     execution.ID.String(),
+		Status: execution.Status.String(),
+	})
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/bmviniciuss/sagas-golang/internal/diagram"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+)
+
+// NewOpenAPI returns the OpenAPI document of the API. Each workflow with an input type gets its own start operation.
+func NewOpenAPI(workflows []saga.Workflow) *openapi.Document {
+	doc := openapi.New("Orchestrator API", "1.0.0").WithAuthentication(auth.APIKeyHeader)
+	var (
+		errorSchema = doc.SchemaOf(responses.Problem{})
+		errorRes    = func(description string) *openapi.Response {
+			return openapi.ContentResponse(description, responses.ProblemContentType, errorSchema)
+		}
+		tooManyRequests = &openapi.Response{
+			Description: "Client exceeded its rate limit or the workflow has too many active executions",
+			Headers: map[string]openapi.Header{
+				"Retry-After": {Description: "Seconds to wait before retrying", Schema: openapi.Integer()},
+			},
+			Content: errorRes("").Content,
+		}
+		executionID    = openapi.PathParam("id", "Execution ID", openapi.String("uuid"))
+		executionIDRes = openapi.Object(map[string]*openapi.Schema{"id": openapi.String("uuid")})
+		startParams    = []openapi.Parameter{
+			openapi.HeaderParam(idempotencyKeyHeader, "Starting again with the same key returns the execution it started", openapi.String("").WithMaxLength(maxIdempotencyKeyLength)),
+			openapi.HeaderParam(callbackURLHeader, "Receives the webhook notifications of the execution. Its scheme and host must be allowed by the orchestrator", openapi.String("uri").WithMaxLength(maxCallbackURLLength)),
+			openapi.QueryParam("wait", "Duration, such as 10s, to wait for the execution to finish before responding", openapi.String("")),
+		}
+		startResponses = func(started int) map[string]*openapi.Response {
+			res := map[int]*openapi.Response{
+				started:                        openapi.JSONResponse("Execution started", executionIDRes),
+				http.StatusAccepted:            openapi.JSONResponse("Execution did not finish while waiting", executionIDRes),
+				http.StatusOK:                  openapi.JSONResponse("Execution finished while waiting", doc.SchemaOf(ExecutionResponse{})),
+				http.StatusBadRequest:          errorRes("Invalid request"),
+				http.StatusNotFound:            errorRes("Workflow not found"),
+				http.StatusTooManyRequests:     tooManyRequests,
+				http.StatusInternalServerError: errorRes("Internal error"),
+			}
+			if started == http.StatusOK {
+				res[http.StatusOK] = openapi.JSONResponse("Execution started, or finished while waiting", &openapi.Schema{
+					OneOf: []*openapi.Schema{executionIDRes, doc.SchemaOf(ExecutionResponse{})},
+				})
+			}
+			return openapi.Responses(res)
+		}
+		actionResponses = openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Action performed", doc.SchemaOf(ExecutionStatusResponse{})),
+			http.StatusBadRequest:          errorRes("Invalid request"),
+			http.StatusNotFound:            errorRes("Execution not found"),
+			http.StatusConflict:            errorRes("Execution status does not allow the action"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		})
+		pageParams = []openapi.Parameter{
+			openapi.QueryParam("cursor", "next_cursor of the previous page", openapi.String("")),
+			openapi.QueryParam("limit", "Page size", openapi.Integer().WithRange(1, saga.MaxPageSize)),
+		}
+	)
+
+	doc.Add(http.MethodGet, "/v1/health", (&openapi.Operation{
+		OperationID: "getHealth",
+		Summary:     "Health check",
+		Tags:        []string{"health"},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK: openapi.JSONResponse("Service is healthy", openapi.Object(map[string]*openapi.Schema{
+				"status": openapi.String(""),
+				"time":   openapi.String("date-time"),
+			})),
+		}),
+	}).Public())
+
+	for i := range workflows {
+		workflow := &workflows[i]
+		if workflow.Name == createOrderWorkflow && workflow.NewInput != nil {
+			doc.Add(http.MethodPost, "/v1/create-orders", (&openapi.Operation{
+				OperationID: "createOrder",
+				Summary:     "Start the " + createOrderWorkflow + " workflow",
+				Description: "Deprecated: use POST /v1/workflows/" + createOrderWorkflow + "/executions.",
+				Tags:        []string{"executions"},
+				Parameters:  startParams,
+				RequestBody: openapi.JSONBody(doc.Named("CreateOrderRequest", workflow.NewInput())),
+				Responses:   startResponses(http.StatusOK),
+			}).RequiresScopes(ScopeExecutionsWrite))
+		}
+		if workflow.NewInput == nil {
+			continue
+		}
+		doc.Add(http.MethodPost, fmt.Sprintf("/v1/workflows/%s/executions", workflow.Name), (&openapi.Operation{
+			OperationID: "start" + pascalCase(workflow.Name),
+			Summary:     fmt.Sprintf("Start an execution of the %s workflow", workflow.Name),
+			Tags:        []string{"executions"},
+			Parameters:  startParams,
+			RequestBody: openapi.JSONBody(doc.Named(pascalCase(workflow.Name)+"Input", workflow.NewInput())),
+			Responses:   startResponses(http.StatusCreated),
+		}).RequiresScopes(ScopeExecutionsWrite))
+	}
+	doc.Add(http.MethodPost, "/v1/workflows/{name}/executions", (&openapi.Operation{
+		OperationID: "startExecution",
+		Summary:     "Start an execution of a workflow",
+		Tags:        []string{"executions"},
+		Parameters:  append([]openapi.Parameter{openapi.PathParam("name", "Workflow name", openapi.String(""))}, startParams...),
+		RequestBody: openapi.JSONBody(&openapi.Schema{Type: "object"}),
+		Responses:   startResponses(http.StatusCreated),
+	}).RequiresScopes(ScopeExecutionsWrite))
+
+	doc.Add(http.MethodGet, "/v1/workflows", (&openapi.Operation{
+		OperationID: "listWorkflows",
+		Summary:     "List the workflows",
+		Tags:        []string{"workflows"},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Workflows", doc.SchemaOf(WorkflowList{})),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeWorkflowsRead))
+	doc.Add(http.MethodGet, "/v1/workflows/{name}", (&openapi.Operation{
+		OperationID: "getWorkflow",
+		Summary:     "Get a workflow",
+		Tags:        []string{"workflows"},
+		Parameters:  []openapi.Parameter{openapi.PathParam("name", "Workflow name", openapi.String(""))},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Workflow", doc.SchemaOf(WorkflowResponse{})),
+			http.StatusNotFound:            errorRes("Workflow not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeWorkflowsRead))
+	doc.Add(http.MethodGet, "/v1/workflows/{name}/diagram", (&openapi.Operation{
+		OperationID: "getWorkflowDiagram",
+		Summary:     "Render the diagram of a workflow",
+		Tags:        []string{"workflows"},
+		Parameters: []openapi.Parameter{
+			openapi.PathParam("name", "Workflow name", openapi.String("")),
+			openapi.QueryParam("format", "Diagram format, mermaid-state by default", enum(diagram.Formats())),
+		},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.ContentResponse("Diagram", "text/plain", openapi.String("")),
+			http.StatusBadRequest:          errorRes("Unknown format"),
+			http.StatusNotFound:            errorRes("Workflow not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeWorkflowsRead))
+
+	doc.Add(http.MethodGet, "/v1/executions", (&openapi.Operation{
+		OperationID: "listExecutions",
+		Summary:     "Search the executions, newest created first",
+		Tags:        []string{"executions"},
+		Parameters: append([]openapi.Parameter{
+			openapi.QueryParam("workflow", "Workflow name", openapi.String("")),
+			openapi.QueryParam("status", "Execution status", executionStatuses()),
+			openapi.QueryParam("created_from", "Inclusive lower bound of the creation time", openapi.String("date-time")),
+			openapi.QueryParam("created_to", "Exclusive upper bound of the creation time", openapi.String("date-time")),
+			openapi.QueryParam("correlation_id", "Execution ID sent as the correlation ID of the step commands", openapi.String("uuid")),
+			openapi.QueryParam("business_key", "Business key of the execution input", openapi.String("")),
+		}, pageParams...),
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Executions", doc.SchemaOf(ExecutionList{})),
+			http.StatusBadRequest:          errorRes("Invalid filters"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeExecutionsRead))
+	doc.Add(http.MethodGet, "/v1/executions/{id}", (&openapi.Operation{
+		OperationID: "getExecution",
+		Summary:     "Get an execution",
+		Tags:        []string{"executions"},
+		Parameters:  []openapi.Parameter{executionID},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Execution", doc.SchemaOf(ExecutionResponse{})),
+			http.StatusBadRequest:          errorRes("Invalid execution ID"),
+			http.StatusNotFound:            errorRes("Execution not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeExecutionsRead))
+	doc.Add(http.MethodGet, "/v1/executions/{id}/events", (&openapi.Operation{
+		OperationID: "streamExecutionEvents",
+		Summary:     "Stream the progress events of an execution as Server-Sent Events",
+		Tags:        []string{"executions"},
+		Parameters:  []openapi.Parameter{executionID},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.ContentResponse("Event stream", "text/event-stream", openapi.String("")),
+			http.StatusBadRequest:          errorRes("Invalid execution ID"),
+			http.StatusNotFound:            errorRes("Execution not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeExecutionsRead))
+	doc.Add(http.MethodGet, "/v1/executions/{id}/timeline", (&openapi.Operation{
+		OperationID: "getExecutionTimeline",
+		Summary:     "Render the timeline of an execution",
+		Tags:        []string{"executions"},
+		Parameters: []openapi.Parameter{
+			executionID,
+			openapi.QueryParam("format", "Timeline format, html by default", enum(diagram.TimelineFormats())),
+		},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.ContentResponse("Timeline", "text/html", openapi.String("")),
+			http.StatusBadRequest:          errorRes("Invalid execution ID or format"),
+			http.StatusNotFound:            errorRes("Execution not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeExecutionsRead))
+
+	for _, action := range []struct {
+		path, operationID, summary string
+		body                       *openapi.RequestBody
+	}{
+		{"/v1/executions/{id}/cancel", "cancelExecution", "Cancel a running execution and compensate its completed steps", nil},
+		{"/v1/executions/{id}/retry", "retryStep", "Publish again the command of the step the execution is waiting for", nil},
+		{"/v1/executions/{id}/resume", "resumeExecution", "Resume an execution from a step", openapi.JSONBody(doc.SchemaOf(ResumeRequest{}))},
+		{"/v1/interventions/{id}/retry", "retryCompensation", "Request again the compensation that required intervention", nil},
+		{"/v1/interventions/{id}/resolve", "resolveIntervention", "Mark the compensation that required intervention as done", nil},
+		{"/v1/interventions/{id}/force-complete", "forceComplete", "Finish an execution that required intervention", nil},
+	} {
+		tag := "executions"
+		if strings.HasPrefix(action.path, "/v1/interventions") {
+			tag = "interventions"
+		}
+		doc.Add(http.MethodPost, action.path, (&openapi.Operation{
+			OperationID: action.operationID,
+			Summary:     action.summary,
+			Tags:        []string{tag},
+			Parameters:  []openapi.Parameter{executionID},
+			RequestBody: action.body,
+			Responses:   actionResponses,
+		}).RequiresScopes(ScopeExecutionsWrite))
+	}
+	doc.Add(http.MethodGet, "/v1/interventions", (&openapi.Operation{
+		OperationID: "listInterventions",
+		Summary:     "List the executions that need intervention",
+		Tags:        []string{"interventions"},
+		Parameters:  pageParams,
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Interventions", doc.SchemaOf(InterventionList{})),
+			http.StatusBadRequest:          errorRes("Invalid page"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeExecutionsRead))
+	return doc
+}
+
+func executionStatuses() *openapi.Schema {
+	return openapi.Enum(
+		saga.ExecutionStatusRunning.String(),
+		saga.ExecutionStatusCompensating.String(),
+		saga.ExecutionStatusCompleted.String(),
+		saga.ExecutionStatusCompensated.String(),
+		saga.ExecutionStatusNeedsIntervention.String(),
+		saga.ExecutionStatusForceCompleted.String(),
+		saga.ExecutionStatusCancelling.String(),
+		saga.ExecutionStatusCancelled.String(),
+	)
+}
+
+func enum[T ~string](values []T) *openapi.Schema {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	return openapi.Enum(names...)
+}
+
+// pascalCase converts a snake case workflow name, such as create_order_v1, to CreateOrderV1.
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -4,17 +4,37 @@ import (
 	"net/http"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/bmviniciuss/sagas-golang/internal/ratelimit"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
+const (
+	ScopeWorkflowsRead  = "workflows:read"
+	ScopeExecutionsRead = "executions:read"
+	// ScopeExecutionsWrite allows starting executions and acting on them, including their interventions.
+	ScopeExecutionsWrite = "executions:write"
+)
+
 type Router struct {
 	handlers HandlersPort
+	guard    *auth.Guard
+	limiter  *ratelimit.Limiter
+	spec     *openapi.Document
 }
 
-func NewRouter(handlers HandlersPort) *Router {
+// NewRouter returns the router of the API. Routes other than health and the spec are protected by authenticator,
+// or open if it is nil. The routes starting executions are rate limited per client by limiter, if not nil.
+// Requests are validated against spec, which is served at /openapi.json.
+func NewRouter(handlers HandlersPort, authenticator auth.Authenticator, limiter *ratelimit.Limiter, spec *openapi.Document) *Router {
 	return &Router{
 		handlers: handlers,
+		guard:    auth.NewGuard(authenticator, responses.AuthErrorRenderer(appcontext.RequestID)),
+		limiter:  limiter,
+		spec:     spec,
 	}
 }
 
@@ -22,10 +42,39 @@ func (r *Router) Build() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(requestIDMiddleware)
 	router.Get("/v1/health", r.handlers.Health)
-	router.Post("/v1/create-orders", r.handlers.CreateOrder)
+	router.Get("/openapi.json", r.spec.Handler())
+
+	var (
+		validate        = r.spec.Validator(responses.ValidationErrorRenderer(appcontext.RequestID))
+		workflowsRead   = router.With(r.guard.Require(ScopeWorkflowsRead), validate)
+		executionsRead  = router.With(r.guard.Require(ScopeExecutionsRead), validate)
+		executionsWrite = router.With(r.guard.Require(ScopeExecutionsWrite), validate)
+		executionsStart = executionsWrite.With(r.limiter.Middleware(ratelimit.ClientKey, renderRateLimitError))
+	)
+	executionsStart.Post("/v1/create-orders", r.handlers.CreateOrder)
+	workflowsRead.Get("/v1/workflows", r.handlers.ListWorkflows)
+	workflowsRead.Get("/v1/workflows/{name}", r.handlers.GetWorkflow)
+	workflowsRead.Get("/v1/workflows/{name}/diagram", r.handlers.GetWorkflowDiagram)
+	executionsStart.Post("/v1/workflows/{name}/executions", r.handlers.StartExecution)
+	executionsRead.Get("/v1/executions", r.handlers.ListExecutions)
+	executionsRead.Get("/v1/executions/{id}", r.handlers.GetExecution)
+	executionsRead.Get("/v1/executions/{id}/events", r.handlers.StreamExecutionEvents)
+	executionsRead.Get("/v1/executions/{id}/timeline", r.handlers.GetExecutionTimeline)
+	executionsWrite.Post("/v1/executions/{id}/cancel", r.handlers.CancelExecution)
+	executionsWrite.Post("/v1/executions/{id}/retry", r.handlers.RetryStep)
+	executionsWrite.Post("/v1/executions/{id}/resume", r.handlers.ResumeExecution)
+	executionsRead.Get("/v1/interventions", r.handlers.ListInterventions)
+	executionsWrite.Post("/v1/interventions/{id}/retry", r.handlers.RetryCompensation)
+	executionsWrite.Post("/v1/interventions/{id}/resolve", r.handlers.ResolveIntervention)
+	executionsWrite.Post("/v1/interventions/{id}/force-complete", r.handlers.ForceComplete)
 	return router
 }
 
+func renderRateLimitError(w http.ResponseWriter, r *http.Request) {
+	reqID, _ := appcontext.RequestID(r.Context())
+	responses.RenderError(w, r, responses.NewTooManyRequestsErrorResponse(reqID, "Too Many Requests"))
+}
+
 func requestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := uuid.New().String()
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/diagram"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GetExecutionTimeline renders the steps run by an execution, with their durations, failures and compensations,
+// in the format of the "format" query parameter: an HTML page by default or a Mermaid Gantt chart.
+func (h *Handlers) GetExecutionTimeline(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+		format   = diagram.Format(r.URL.Query().Get("format"))
+	)
+	if format == "" {
+		format = diagram.TimelineFormatHTML
+	}
+	lggr = lggr.With("request_id", reqID, "format", format.String())
+	lggr.Info("Rendering execution timeline")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error decoding execution id")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "id",
+				Message: "Invalid execution ID",
+			},
+		}))
+		return
+	}
+
+	execution, err := h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding execution")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if execution.IsEmpty() {
+		lggr.Error("Execution not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	rendered, err := diagram.RenderTimeline(diagram.NewTimeline(execution, time.Now()), format)
+	if errors.Is(err, diagram.ErrUnknownFormat) {
+		lggr.With(zap.Error(err)).Error("Got unknown timeline format")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "format",
+				Message: fmt.Sprintf("Format must be one of %v", diagram.TimelineFormats()),
+			},
+		}))
+		return
+	}
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error rendering execution timeline")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if format == diagram.TimelineFormatHTML {
+		contentType = "text/html; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(rendered))
+}
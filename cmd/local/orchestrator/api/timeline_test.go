@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers_GetExecutionTimeline(t *testing.T) {
+	server := newTestServer(t)
+	execution := server.insert(t)
+
+	tests := []struct {
+		name            string
+		query           string
+		wantContentType string
+		wantContains    string
+	}{
+		{name: "should render an HTML page by default", wantContentType: "text/html; charset=utf-8", wantContains: "<html"},
+		{name: "should render a Mermaid Gantt chart", query: "?format=mermaid-gantt", wantContentType: "text/plain; charset=utf-8", wantContains: "gantt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := server.serve(http.MethodGet, "/v1/executions/"+execution.ID.String()+"/timeline"+tt.query, "")
+
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, tt.wantContentType, res.Header().Get("Content-Type"))
+			assert.Contains(t, res.Body.String(), tt.wantContains)
+			assert.Contains(t, res.Body.String(), "create_order")
+		})
+	}
+
+	t.Run("should reject an unknown format", func(t *testing.T) {
+		res := server.serve(http.MethodGet, "/v1/executions/"+execution.ID.String()+"/timeline?format=png", "")
+
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, []string{"format"}, fieldsOf(decodeBody[responses.Problem](t, res)))
+	})
+
+	t.Run("should return not found for an unknown execution", func(t *testing.T) {
+		res := server.serve(http.MethodGet, "/v1/executions/"+uuid.NewString()+"/timeline", "")
+
+		require.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, responses.TypeNotFound, decodeBody[responses.Problem](t, res).Type)
+	})
+}
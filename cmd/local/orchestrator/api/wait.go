@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/google/uuid"
+)
+
+// parseWait parses the optional wait query parameter, a duration such as 10s, capped by maxWait.
+func parseWait(r *http.Request, maxWait time.Duration) (time.Duration, []responses.FieldError) {
+	value := r.URL.Query().Get("wait")
+	if value == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait <= 0 || wait > maxWait {
+		return 0, []responses.FieldError{
+			{
+				Field:   "wait",
+				Message: fmt.Sprintf("Must be a positive duration of at most %s", maxWait),
+			},
+		}
+	}
+	return wait, nil
+}
+
+// waitExecution waits up to timeout for the execution identified by id to finish.
+// It returns the last saved execution and whether it is finished.
+func (h *Handlers) waitExecution(ctx context.Context, id uuid.UUID, timeout time.Duration) (*saga.Execution, bool, error) {
+	done, stop := h.waiter.Wait(id)
+	defer stop()
+
+	// the execution may have finished before the waiter was registered
+	execution, err := h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		return nil, false, err
+	}
+	if execution.Status.IsFinished() {
+		return execution, true, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case <-done:
+	case <-timer.C:
+	}
+
+	execution, err = h.executionRepository.Find(ctx, id.String())
+	if err != nil {
+		return nil, false, err
+	}
+	return execution, execution.Status.IsFinished(), nil
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/diagram"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+)
+
+type WorkflowResponse struct {
+	Name                    string                 `json:"name"`
+	ReplyChannel            string                 `json:"reply_channel"`
+	BusinessKeyPath         string                 `json:"business_key_path,omitempty"`
+	MaxCompensationAttempts int                    `json:"max_compensation_attempts"`
+	Steps                   []WorkflowStepResponse `json:"steps"`
+}
+
+type WorkflowStepResponse struct {
+	Position    int                `json:"position"`
+	Name        string             `json:"name"`
+	Service     string             `json:"service"`
+	Compensable bool               `json:"compensable"`
+	EventTypes  EventTypesResponse `json:"event_types"`
+	Topics      TopicsResponse     `json:"topics"`
+}
+
+type EventTypesResponse struct {
+	Request             string `json:"request"`
+	Success             string `json:"success"`
+	Failure             string `json:"failure,omitempty"`
+	CompensationRequest string `json:"compensation_request,omitempty"`
+	Compensation        string `json:"compensation,omitempty"`
+	CompensationFailure string `json:"compensation_failure,omitempty"`
+}
+
+type TopicsResponse struct {
+	Request  string `json:"request"`
+	Response string `json:"response"`
+}
+
+type WorkflowList struct {
+	Content []WorkflowResponse `json:"content"`
+}
+
+func (h *Handlers) ListWorkflows(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+	)
+	lggr = lggr.With("request_id", reqID)
+	lggr.Info("Listing workflows")
+
+	workflows, err := h.workflowRepository.List(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error listing workflows")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+
+	res := WorkflowList{Content: make([]WorkflowResponse, 0, len(workflows))}
+	for _, workflow := range workflows {
+		res.Content = append(res.Content, toWorkflowResponse(workflow))
+	}
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+func (h *Handlers) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+		name     = chi.URLParam(r, "name")
+	)
+	lggr = lggr.With("request_id", reqID, "workflow", name)
+	lggr.Info("Getting workflow by name")
+
+	workflow, err := h.workflowRepository.Find(ctx, name)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding workflow")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if workflow.IsEmpty() {
+		lggr.Error("Workflow not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, toWorkflowResponse(workflow))
+}
+
+// GetWorkflowDiagram renders the workflow in the format of the "format" query parameter, mermaid-state by default.
+func (h *Handlers) GetWorkflowDiagram(w http.ResponseWriter, r *http.Request) {
+	var (
+		lggr     = h.logger
+		ctx      = r.Context()
+		reqID, _ = appcontext.RequestID(ctx)
+		name     = chi.URLParam(r, "name")
+		format   = diagram.Format(r.URL.Query().Get("format"))
+	)
+	if format == "" {
+		format = diagram.FormatMermaidState
+	}
+	lggr = lggr.With("request_id", reqID, "workflow", name, "format", format.String())
+	lggr.Info("Rendering workflow diagram")
+
+	workflow, err := h.workflowRepository.Find(ctx, name)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error finding workflow")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+	if workflow.IsEmpty() {
+		lggr.Error("Workflow not found")
+		responses.RenderError(w, r, responses.NewNotFoundErrorResponse(reqID))
+		return
+	}
+
+	rendered, err := diagram.Render(workflow, format)
+	if errors.Is(err, diagram.ErrUnknownFormat) {
+		lggr.With(zap.Error(err)).Error("Got unknown diagram format")
+		responses.RenderError(w, r, responses.NewBadRequestErrorResponse(reqID, []responses.FieldError{
+			{
+				Field:   "format",
+				Message: fmt.Sprintf("Format must be one of %v", diagram.Formats()),
+			},
+		}))
+		return
+	}
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error rendering workflow diagram")
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if format == diagram.FormatDOT {
+		contentType = "text/vnd.graphviz; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(rendered))
+}
+
+func toWorkflowResponse(workflow *saga.Workflow) WorkflowResponse {
+	steps := workflow.Steps.ToList()
+	res := WorkflowResponse{
+		Name:                    workflow.Name,
+		ReplyChannel:            workflow.ReplyChannel,
+		BusinessKeyPath:         workflow.BusinessKeyPath,
+		MaxCompensationAttempts: workflow.CompensationAttempts(),
+		Steps:                   make([]WorkflowStepResponse, 0, len(steps)),
+	}
+	for i, step := range steps {
+		res.Steps = append(res.Steps, WorkflowStepResponse{
+			Position:    i + 1,
+			Name:        step.Name,
+			Service:     step.ServiceName,
+			Compensable: step.Compensable,
+			EventTypes: EventTypesResponse{
+				Request:             step.EventTypes.Request,
+				Success:             step.EventTypes.Success,
+				Failure:             step.EventTypes.Failure,
+				CompensationRequest: step.EventTypes.CompesationRequest,
+				Compensation:        step.EventTypes.Compensation,
+				CompensationFailure: step.EventTypes.CompensationFailure,
+			},
+			Topics: TopicsResponse{
+				Request:  step.Topics.Request,
+				Response: step.Topics.Response,
+			},
+		})
+	}
+	return res
+}
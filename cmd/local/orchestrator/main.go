@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,14 +13,24 @@ import (
 	"time"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/executions"
+	webhookrepo "github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/webhooks"
 	workflowrepo "github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/adapters/repositories/workflows"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/api"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/config/env"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/workflows"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kafkaadmin"
 	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kv"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/progress"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
 	"github.com/bmviniciuss/sagas-golang/internal/config/logger"
+	"github.com/bmviniciuss/sagas-golang/internal/diagram"
+	"github.com/bmviniciuss/sagas-golang/internal/lifecycle"
+	"github.com/bmviniciuss/sagas-golang/internal/ratelimit"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/internal/topics"
+	"github.com/bmviniciuss/sagas-golang/internal/webhooks"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
 	"github.com/bmviniciuss/sagas-golang/pkg/validator"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -26,17 +38,13 @@ import (
 	"go.uber.org/zap"
 )
 
-func main() {
-	var (
-		ctx, cancel = context.WithCancel(context.Background())
-		sigCh       = make(chan os.Signal, 1)
-		errCh       = make(chan error, 1)
-	)
-	defer cancel()
-	defer close(sigCh)
-	defer close(errCh)
+var (
+	errTopicsDrift = errors.New("topics drifted from the workflows")
+)
 
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	cfg, err := env.Load()
 	if err != nil {
@@ -46,99 +54,264 @@ func main() {
 	lggr := logger.New(cfg.ServiceName)
 	defer lggr.Sync()
 
+	retries, err := topics.ParseRetries(cfg.KafkaTopicRetries)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("KAFKA_TOPIC_RETRIES is invalid")
+	}
+	topicsConfig := topics.Config{
+		Partitions:        cfg.KafkaTopicPartitions,
+		ReplicationFactor: cfg.KafkaTopicReplication,
+		Retries:           retries,
+	}
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "diagram":
+			err = printDiagram(os.Stdout, newWorkflows(lggr), os.Args[2:])
+		case "topics":
+			err = provisionTopics(ctx, os.Stdout, lggr, cfg.KafkaBootstrapServers, topicsConfig, os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command [%s], expected diagram or topics", os.Args[1])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.KafkaProvisionTopics {
+		if err := provisionTopics(ctx, io.Discard, lggr, cfg.KafkaBootstrapServers, topicsConfig, []string{"apply"}); err != nil {
+			lggr.With(zap.Error(err)).Fatal("Got error provisioning topics")
+		}
+	}
+
+	lc := lifecycle.New(lggr, cfg.ShutdownTimeout)
+
 	redisConn := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
 	err = redisConn.Ping(ctx).Err()
 	if err != nil {
 		lggr.With(zap.Error(err)).Fatal("Got error connecting to Redis")
 	}
+	lc.OnStop("redis", func(context.Context) error {
+		return redisConn.Close()
+	})
 
 	dbpool, err := pgxpool.New(context.Background(), cfg.DBConnectionString)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to create connection pool: %v\n", err)
 		os.Exit(1)
 	}
-	defer dbpool.Close()
+	lc.OnStop("database", func(context.Context) error {
+		dbpool.Close()
+		return nil
+	})
 	lggr.Info("Connected to database")
 
-	var (
-		workflows = []saga.Workflow{
-			*workflows.NewCreateOrderV1(lggr),
-		}
-		workflowRepository = workflowrepo.NewInmemRepository(workflows)
-	)
+	workflowDefinitions := newWorkflows(lggr)
+	workflowRepository := workflowrepo.NewInmemRepository(workflowDefinitions)
 
 	var (
 		executionsRepository = executions.NewRepositoryAdapter(lggr, dbpool, workflowRepository)
 		bootstrapServers     = cfg.KafkaBootstrapServers
-		topics               = strings.Split(cfg.KafkaTopics, ",")
+		consumerTopics       = strings.Split(cfg.KafkaTopics, ",")
 		consumerGroupID      = cfg.KafkaGroupID
 		publisher            = newPublisher(lggr, bootstrapServers)
-		workflowService      = saga.NewService(lggr, executionsRepository, publisher)
+		waiter               = saga.NewWaiter()
+		progressBroker       = newProgressBroker(lggr, cfg.ProgressBroker, redisConn)
 		idempotenceService   = kv.NewAdapter(lggr, redisConn)
-		messageHandler       = streaming.NewMessageHandler(lggr, executionsRepository, workflowService, idempotenceService)
 	)
+	lc.OnStop("publisher", publisher.Close)
+	workflowService := saga.NewService(lggr, executionsRepository, publisher).
+		WithIdempotencyRetention(cfg.IdempotencyKeyRetention).
+		WithRedactedFields(cfg.ExecutionRedactedFields).
+		WithMaxActiveExecutions(cfg.MaxActiveExecutions).
+		WithObserver(waiter).
+		WithObserver(saga.NewProgressPublisher(lggr, progressBroker))
+
+	var dispatcher *webhooks.Dispatcher
+	if cfg.WebhookSecret != "" {
+		dispatcher = webhooks.NewDispatcher(
+			lggr,
+			webhookrepo.NewRepositoryAdapter(lggr, dbpool),
+			webhooks.NewClient(cfg.WebhookTimeout),
+			cfg.WebhookSecret,
+			webhooks.RetryPolicy{Delays: cfg.WebhookRetryDelays},
+		)
+		workflowService.WithNotifier(webhooks.NewNotifier(cfg.ExecutionRedactedFields))
+	} else {
+		lggr.Warn("WEBHOOK_SECRET is not set. Webhook notifications are disabled")
+	}
+	messageHandler := streaming.NewMessageHandler(lggr, executionsRepository, workflowService, idempotenceService)
+
+	authenticator, err := auth.NewAuthenticator(auth.Config{
+		APIKeysFile: cfg.AuthAPIKeysFile,
+		JWKSFile:    cfg.AuthJWKSFile,
+		JWT: auth.JWTConfig{
+			Issuer:   cfg.AuthJWTIssuer,
+			Audience: cfg.AuthJWTAudience,
+			Leeway:   cfg.AuthJWTLeeway,
+		},
+		Disabled: cfg.AuthDisabled,
+	})
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error loading API credentials")
+	}
+	if authenticator == nil {
+		lggr.Warn("AUTH_DISABLED is set. API authentication is disabled")
+	}
+
+	limiter := ratelimit.NewLimiter(lggr, newRateLimitStore(lggr, cfg.RateLimitStore, redisConn), ratelimit.Limit{
+		Requests: cfg.RateLimitRequests,
+		Period:   cfg.RateLimitPeriod,
+	})
 
 	var (
-		val         = validator.New()
-		apiHandlers = api.NewHandlers(lggr, workflowRepository, workflowService, val)
-		httpServer  = newApiServer(":3000", apiHandlers)
+		val          = validator.New()
+		callbackURLs = webhooks.Allowlist{Schemes: cfg.WebhookAllowedSchemes, Hosts: cfg.WebhookAllowedHosts}
+		apiHandlers  = api.NewHandlers(lggr, workflowRepository, executionsRepository, workflowService, val, cfg.ExecutionRedactedFields, waiter, cfg.StartMaxWait, progressBroker, callbackURLs)
+		httpServer   = newApiServer(":3000", apiHandlers, authenticator, limiter, api.NewOpenAPI(workflowDefinitions), cfg.StartMaxWait)
 	)
 
-	consumer, err := newConsumer(lggr, topics, bootstrapServers, consumerGroupID, messageHandler)
+	consumer, err := newConsumer(lggr, consumerTopics, bootstrapServers, consumerGroupID, messageHandler)
 	if err != nil {
 		lggr.With(zap.Error(err)).Fatal("Got error creating consumer")
 	}
 
-	go func() {
-		lggr.Infof("Starting orchestrator consumer go routine")
-		if err := consumer.Start(ctx); err != nil {
-			lggr.With(zap.Error(err)).Fatal("Got in consumer")
-			errCh <- err
+	lc.Go("orchestrator consumer", consumer.Start)
+	if dispatcher != nil {
+		lc.Go("webhook dispatcher", func(ctx context.Context) error {
+			return dispatcher.Run(ctx, cfg.WebhookPollInterval)
+		})
+	}
+	err = lc.Server("API server", httpServer).Run(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error running orchestrator")
+	}
+
+	lggr.Info("Exiting")
+}
+
+// newWorkflows returns the workflows run by the orchestrator, exiting if any of them is invalid.
+func newWorkflows(lggr *zap.SugaredLogger) []saga.Workflow {
+	workflows := []saga.Workflow{
+		*workflows.NewCreateOrderV1(lggr),
+	}
+	for _, w := range workflows {
+		if err := w.Validate(); err != nil {
+			lggr.With(zap.Error(err)).Fatalf("Workflow [%s] definition is invalid", w.Name)
 		}
-	}()
+	}
+	return workflows
+}
 
-	go func() {
-		lggr.Info("Starting API server go routine")
-		if err := httpServer.ListenAndServe(); err != nil {
-			lggr.With(zap.Error(err)).Fatal("Got error in API server")
-			errCh <- err
+// printDiagram writes the diagram of a workflow. args are the workflow name and, optionally, the diagram format.
+func printDiagram(out io.Writer, workflows []saga.Workflow, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: orchestrator diagram <workflow> [%v]", diagram.Formats())
+	}
+	format := diagram.FormatMermaidState
+	if len(args) == 2 {
+		format = diagram.Format(args[1])
+	}
+	for i := range workflows {
+		if workflows[i].Name != args[0] {
+			continue
 		}
-	}()
-
-	lggr.Info("Running. Waiting for signal to stop...")
-	select {
-	case <-sigCh:
-		lggr.Info("Got signal, stopping consumer")
-		cancel()
-	case err := <-errCh:
-		lggr.With(zap.Error(err)).Fatal("Got error in consumer")
-		cancel()
-		os.Exit(1)
+		rendered, err := diagram.Render(&workflows[i], format)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(out, rendered)
+		return err
 	}
+	return fmt.Errorf("workflow [%s] not found", args[0])
+}
 
-	lggr.Info("Exiting")
+// provisionTopics compares the topics required by the workflows with the cluster and writes the drift to out.
+// With the "apply" argument the missing topics are created, otherwise an error is returned if there is any drift.
+func provisionTopics(ctx context.Context, out io.Writer, lggr *zap.SugaredLogger, servers string, config topics.Config, args []string) error {
+	apply := len(args) == 1 && args[0] == "apply"
+	if len(args) > 1 || (len(args) == 1 && !apply) {
+		return errors.New("usage: orchestrator topics [apply]")
+	}
+
+	client, err := kafka.NewAdminClient(&kafka.ConfigMap{"bootstrap.servers": servers})
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error creating Kafka admin client")
+		return err
+	}
+	defer client.Close()
+	provisioner := topics.NewProvisioner(lggr, kafkaadmin.NewAdapter(lggr, client), config, newWorkflows(lggr))
+
+	if !apply {
+		report, err := provisioner.Diff(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, report)
+		if report.HasDrift() {
+			return errTopicsDrift
+		}
+		return nil
+	}
+
+	report, err := provisioner.Provision(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, report)
+	if len(report.Mismatched) > 0 || len(report.Unmanaged) > 0 {
+		lggr.Warnf("Topics drifted from the workflows. Mismatched: %d, unmanaged: %v", len(report.Mismatched), report.Unmanaged)
+	}
+	return nil
 }
 
-func newApiServer(addr string, handlers api.HandlersPort) *http.Server {
-	mux := api.NewRouter(handlers).Build()
+// newApiServer creates the API server. Its write timeout leaves room for start requests waiting up to maxWait.
+func newApiServer(addr string, handlers api.HandlersPort, authenticator auth.Authenticator, limiter *ratelimit.Limiter, spec *openapi.Document, maxWait time.Duration) *http.Server {
+	mux := api.NewRouter(handlers, authenticator, limiter, spec).Build()
 	return &http.Server{
 		Addr:              addr,
 		Handler:           mux,
 		ReadTimeout:       5 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      5 * time.Second,
+		WriteTimeout:      maxWait + 5*time.Second,
 		IdleTimeout:       5 * time.Second,
 	}
 }
 
+// newProgressBroker returns the broker named by kind: "redis" to stream progress across replicas or "local" to stream it in process.
+func newProgressBroker(lggr *zap.SugaredLogger, kind string, redisConn *redis.Client) saga.ProgressBroker {
+	switch kind {
+	case "redis":
+		return progress.NewRedisBroker(lggr, redisConn)
+	case "local":
+		return progress.NewInmemBroker()
+	}
+	lggr.Fatalf("Unknown progress broker [%s]", kind)
+	return nil
+}
+
+// newRateLimitStore returns the store named by kind: "redis" to share the rate limits across replicas or "local" to count requests in process.
+func newRateLimitStore(lggr *zap.SugaredLogger, kind string, redisConn *redis.Client) ratelimit.Store {
+	switch kind {
+	case "redis":
+		return kv.NewAdapter(lggr, redisConn)
+	case "local":
+		return kv.NewInmemAdapter()
+	}
+	lggr.Fatalf("Unknown rate limit store [%s]", kind)
+	return nil
+}
+
 func newPublisher(lggr *zap.SugaredLogger, servers string) *streaming.Publisher {
 	return streaming.NewPublisher(lggr, &kafka.ConfigMap{
 		"bootstrap.servers": servers,
 	})
 }
 
-func newConsumer(lggr *zap.SugaredLogger, topics []string, servers string, groupID string, handler streaming.Handler) (*streaming.Consumer, error) {
-	return streaming.NewConsumer(lggr, topics, &kafka.ConfigMap{
+func newConsumer(lggr *zap.SugaredLogger, consumerTopics []string, servers string, groupID string, handler streaming.Handler) (*streaming.Consumer, error) {
+	return streaming.NewConsumer(lggr, consumerTopics, &kafka.ConfigMap{
 		"bootstrap.servers":        servers,
 		"broker.address.family":    "v4",
 		"group.id":                 groupID,
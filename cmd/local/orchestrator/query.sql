@@ -1,14 +1,47 @@
--- name: InsertExecution :exec
+-- name: InsertExecution :one
 INSERT INTO sagas.executions
-	("uuid", workflow_name, state, created_at, updated_at)
-VALUES ($1, $2, $3, now(), now()) RETURNING id;
+	("uuid", workflow_name, state, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now(), now()) RETURNING version, created_at, updated_at;
 
--- name: UpdateExecution :exec
+-- name: UpdateExecution :one
 UPDATE sagas.executions
-SET state = $2, updated_at = now()
-WHERE uuid = $1;
+SET state = $2, status = $3, intervention = $4, compensation_attempts = $5, current_step = $6, transitions = $7, outcome_published = $9, version = version + 1, updated_at = now()
+WHERE uuid = $1 AND version = $8
+RETURNING version, updated_at;
 
 -- name: FindExecutionByUUID :one
-SELECT id, uuid, workflow_name, state, created_at, updated_at
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
 FROM sagas.executions
 WHERE uuid = $1 LIMIT 1;
+
+-- name: FindExecutionByIdempotencyKey :one
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
+FROM sagas.executions
+WHERE workflow_name = $1 AND idempotency_key = $2 AND idempotency_expires_at > now()
+LIMIT 1;
+
+-- name: ReleaseExpiredIdempotencyKey :exec
+UPDATE sagas.executions
+SET idempotency_key = NULL
+WHERE workflow_name = $1 AND idempotency_key = $2 AND idempotency_expires_at <= now();
+
+-- name: ListExecutions :many
+SELECT id, uuid, workflow_name, state, created_at, updated_at, status, intervention, compensation_attempts, current_step, transitions, business_key, idempotency_key, idempotency_expires_at, callback_url, started_by, version, outcome_published
+FROM sagas.executions
+WHERE (sqlc.narg('workflow_name')::varchar IS NULL OR workflow_name = sqlc.narg('workflow_name'))
+  AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('correlation_id')::uuid IS NULL OR uuid = sqlc.narg('correlation_id'))
+  AND (sqlc.narg('business_key')::varchar IS NULL OR business_key = sqlc.narg('business_key'))
+  AND (sqlc.narg('after_created_at')::timestamptz IS NULL OR (created_at, uuid) < (sqlc.narg('after_created_at'), sqlc.narg('after_uuid')::uuid))
+ORDER BY created_at DESC, uuid DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: CountActiveExecutions :one
+SELECT count(*)
+FROM sagas.executions
+WHERE workflow_name = $1 AND status IN ('running', 'compensating', 'cancelling');
+
+-- name: LockWorkflowExecutions :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg('workflow_name')::text));
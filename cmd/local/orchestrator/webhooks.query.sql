@@ -0,0 +1,23 @@
+-- name: InsertWebhookDelivery :one
+INSERT INTO sagas.webhook_deliveries
+	("uuid", execution_uuid, url, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now()) RETURNING created_at, updated_at;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE sagas.webhook_deliveries
+SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, updated_at = now()
+WHERE uuid = $1
+RETURNING updated_at;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE sagas.webhook_deliveries
+SET next_attempt_at = now() + sqlc.arg('lease')::interval, updated_at = now()
+WHERE id IN (
+	SELECT id
+	FROM sagas.webhook_deliveries
+	WHERE status = 'pending' AND next_attempt_at <= now()
+	ORDER BY next_attempt_at
+	LIMIT sqlc.arg('batch_size')
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, uuid, execution_uuid, url, event_type, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at;
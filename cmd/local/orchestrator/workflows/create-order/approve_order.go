@@ -3,6 +3,7 @@ package createorder
 import (
 	"context"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/bmviniciuss/sagas-golang/pkg/structs"
@@ -37,7 +38,7 @@ func (b *ApproveOrderPayloadBuilder) buildRequestPayload(_ context.Context, exec
 		return nil, err
 	}
 
-	evt := events.NewEvent("approve_order", "orchestrator", map[string]interface{}{}).WithCorrelationID(exec.ID.String())
+	evt := events.NewEvent(contracts.ApproveOrder.EventTypes.Request, "orchestrator", map[string]interface{}{}).WithCorrelationID(exec.ID.String())
 	eventMap, err := structs.ToMap(evt)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error converting event to map")
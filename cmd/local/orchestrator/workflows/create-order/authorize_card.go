@@ -3,6 +3,7 @@ package createorder
 import (
 	"context"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/bmviniciuss/sagas-golang/pkg/structs"
@@ -50,7 +51,7 @@ func (v *AuthorizeCardPayloadBuilder) buildRequestPayload(_ context.Context, exe
 		return nil, err
 	}
 
-	evt := events.NewEvent("authorize_card", "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
+	evt := events.NewEvent(contracts.AuthorizeCard.EventTypes.Request, "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
 	evtMap, err := structs.ToMap(evt)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error converting event to map")
@@ -3,7 +3,7 @@ package createorder
 // Input is the data structure that represents the input data for the create order step
 type Input struct {
 	CustomerID   string      `json:"customer_id" validate:"required,uuid"`
-	Card         string      `json:"card" validate:"required,uuid"`
+	Card         string      `json:"card" validate:"required"`
 	Amount       *int64      `json:"amount" validate:"required,gt=0"`
 	CurrencyCode string      `json:"currency_code" validate:"required"`
 	Items        []ItemInput `json:"items" validate:"required,min=1,dive"`
@@ -3,6 +3,7 @@ package createorder
 import (
 	"context"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/bmviniciuss/sagas-golang/pkg/structs"
@@ -59,7 +60,7 @@ func (b *CreateOrderStepPayloadBuilder) buildRequestPayload(_ context.Context, e
 		return nil, err
 	}
 
-	evt := events.NewEvent("create_order", "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
+	evt := events.NewEvent(contracts.CreateOrder.EventTypes.Request, "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
 	eventMap, err := structs.ToMap(evt)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error converting event to map")
@@ -72,7 +73,7 @@ func (b *CreateOrderStepPayloadBuilder) buildRequestPayload(_ context.Context, e
 func (b *CreateOrderStepPayloadBuilder) buildCompensationRequestPayload(_ context.Context, exec *saga.Execution) (map[string]interface{}, error) {
 	lggr := b.logger
 	lggr.Info("Building request payload to compensate create order step")
-	evt := events.NewEvent("reject_order", "orchestrator", map[string]interface{}{}).WithCorrelationID(exec.ID.String())
+	evt := events.NewEvent(contracts.CreateOrder.EventTypes.CompesationRequest, "orchestrator", map[string]interface{}{}).WithCorrelationID(exec.ID.String())
 	eventMap, err := structs.ToMap(evt)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error converting event to map")
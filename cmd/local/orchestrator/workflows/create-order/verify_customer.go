@@ -3,6 +3,7 @@ package createorder
 import (
 	"context"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/internal/saga"
 	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/bmviniciuss/sagas-golang/pkg/structs"
@@ -48,7 +49,7 @@ func (v *VerifyCustomerPayloadBuilder) buildRequestPayload(_ context.Context, ex
 		lggr.With(zap.Error(err)).Error("Got error converting payload to map")
 		return nil, err
 	}
-	evt := events.NewEvent("verify_customer", "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
+	evt := events.NewEvent(contracts.VerifyCustomer.EventTypes.Request, "orchestrator", payloadMap).WithCorrelationID(exec.ID.String())
 	evtMap, err := structs.ToMap(evt)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error converting event to map")
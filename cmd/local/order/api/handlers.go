@@ -44,17 +44,12 @@ func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handlers) ListAll(w http.ResponseWriter, r *http.Request) {
+	reqID, _ := appcontext.RequestID(r.Context())
 	h.lggr.Info("Listing all orders")
 	results, err := h.listUseCase.Execute(r.Context())
 	if err != nil {
 		h.lggr.With(zap.Error(err)).Error("Got error listing orders")
-		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, r, map[string]interface{}{
-			"error": map[string]interface{}{
-				"code":    "internal_error",
-				"message": "Got error listing orders",
-			},
-		})
+		responses.RenderError(w, r, responses.NewInternalServerErrorResponse(reqID))
 		return
 	}
 	var res presentation.OrderList
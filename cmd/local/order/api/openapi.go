@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/order/presentation"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+	"github.com/bmviniciuss/sagas-golang/pkg/utc"
+)
+
+// NewOpenAPI returns the OpenAPI document of the API.
+func NewOpenAPI() *openapi.Document {
+	doc := openapi.New("Orders API", "1.0.0").
+		WithAuthentication(auth.APIKeyHeader).
+		Define(utc.Time{}, openapi.String("date-time"))
+	var (
+		errorSchema = doc.SchemaOf(responses.Problem{})
+		errorRes    = func(description string) *openapi.Response {
+			return openapi.ContentResponse(description, responses.ProblemContentType, errorSchema)
+		}
+	)
+
+	doc.Add(http.MethodGet, "/v1/health", (&openapi.Operation{
+		OperationID: "getHealth",
+		Summary:     "Health check",
+		Tags:        []string{"health"},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK: openapi.JSONResponse("Service is healthy", openapi.Object(map[string]*openapi.Schema{
+				"status": openapi.String(""),
+				"time":   openapi.String("date-time"),
+			})),
+		}),
+	}).Public())
+	doc.Add(http.MethodGet, "/v1/orders", (&openapi.Operation{
+		OperationID: "listOrders",
+		Summary:     "List the orders",
+		Tags:        []string{"orders"},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Orders", doc.SchemaOf(presentation.OrderList{})),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeOrdersRead))
+	doc.Add(http.MethodGet, "/v1/orders/{id}", (&openapi.Operation{
+		OperationID: "getOrder",
+		Summary:     "Get an order",
+		Tags:        []string{"orders"},
+		Parameters:  []openapi.Parameter{openapi.PathParam("id", "Order ID", openapi.String("uuid"))},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK:                  openapi.JSONResponse("Order", doc.SchemaOf(presentation.OrderById{})),
+			http.StatusBadRequest:          errorRes("Invalid order ID"),
+			http.StatusNotFound:            errorRes("Order not found"),
+			http.StatusInternalServerError: errorRes("Internal error"),
+		}),
+	}).RequiresScopes(ScopeOrdersRead))
+	doc.Add(http.MethodGet, "/v1/metrics", (&openapi.Operation{
+		OperationID: "getMetrics",
+		Summary:     "Count the handled commands by event type",
+		Tags:        []string{"metrics"},
+		Responses: openapi.Responses(map[int]*openapi.Response{
+			http.StatusOK: openapi.JSONResponse("Counters by event type", doc.SchemaOf(map[string]participant.Counters{})),
+		}),
+	}).RequiresScopes(ScopeMetricsRead))
+	return doc
+}
@@ -4,27 +4,47 @@ import (
 	"net/http"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/orchestrator/appcontext"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
+const (
+	ScopeOrdersRead  = "orders:read"
+	ScopeMetricsRead = "metrics:read"
+)
+
 type Router struct {
 	handlers OrderHandlers
+	guard    *auth.Guard
+	spec     *openapi.Document
+	metrics  http.Handler
 }
 
-func NewRouter(handlers OrderHandlers) *Router {
+// NewRouter returns the router of the API. Routes other than health and the spec are protected by authenticator,
+// or open if it is nil. Requests are validated against spec, which is served at /openapi.json.
+// metrics serves the message counters of the participant runtime.
+func NewRouter(handlers OrderHandlers, authenticator auth.Authenticator, spec *openapi.Document, metrics http.Handler) *Router {
 	return &Router{
 		handlers: handlers,
+		guard:    auth.NewGuard(authenticator, responses.AuthErrorRenderer(appcontext.RequestID)),
+		spec:     spec,
+		metrics:  metrics,
 	}
 }
 
 func (rr *Router) Build() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(requestIDMiddleware)
+	router.Get("/openapi.json", rr.spec.Handler())
 	router.Route("/v1", func(r chi.Router) {
 		r.Get("/health", rr.handlers.Health)
-		r.Get("/orders", rr.handlers.ListAll)
-		r.Get("/orders/{id}", rr.handlers.GetByID)
+		ordersRead := r.With(rr.guard.Require(ScopeOrdersRead), rr.spec.Validator(responses.ValidationErrorRenderer(appcontext.RequestID)))
+		ordersRead.Get("/orders", rr.handlers.ListAll)
+		ordersRead.Get("/orders/{id}", rr.handlers.GetByID)
+		r.With(rr.guard.Require(ScopeMetricsRead)).Get("/metrics", rr.metrics.ServeHTTP)
 	})
 	return router
 }
@@ -2,7 +2,6 @@ package usecases
 
 import (
 	"context"
-	"errors"
 
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/repositories"
 	"github.com/google/uuid"
@@ -36,7 +35,7 @@ func (co ApproveOrder) Execute(ctx context.Context, request ApproveOrderRequest)
 	}
 	if order == nil {
 		lggr.Errorf("Order [%s] not found", request.GlobalID)
-		return errors.New("order not found")
+		return ErrOrderNotFound
 	}
 
 	order.Approve()
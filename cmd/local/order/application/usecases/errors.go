@@ -0,0 +1,8 @@
+package usecases
+
+import "errors"
+
+var (
+	// ErrOrderNotFound is returned when the order of a saga execution doesn't exist.
+	ErrOrderNotFound = errors.New("order not found")
+)
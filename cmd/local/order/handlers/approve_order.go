@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/usecases"
-	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	goval "github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -15,38 +16,38 @@ type ApproveOrder struct {
 	approveOrderUseCase usecases.ApproveOrderUseCasePort
 }
 
-var (
-	_ application.MessageHandler = (*ApproveOrder)(nil)
-)
-
-func NewApproveOrder(logger *zap.SugaredLogger, approveOrderUseCase usecases.ApproveOrderUseCasePort) *ApproveOrder {
-	return &ApproveOrder{
+func NewApproveOrder(
+	logger *zap.SugaredLogger,
+	validate *goval.Validate,
+	approveOrderUseCase usecases.ApproveOrderUseCasePort,
+) *participant.TypedHandler[struct{}, struct{}] {
+	h := &ApproveOrder{
 		logger:              logger,
 		approveOrderUseCase: approveOrderUseCase,
 	}
+	return participant.NewTypedHandler(validate, h.Handle)
 }
 
-// TODO: add enum
-// Request:      "approve_order",
-// Success:      "order_approved",
-
-func (h *ApproveOrder) Handle(ctx context.Context, event *events.Event) (*events.Event, error) {
+func (h *ApproveOrder) Handle(ctx context.Context, cmd participant.Command[struct{}]) (struct{}, error) {
 	lggr := h.logger
-	lggr.Infof("Handling message [%s]", event.Type)
+	lggr.Infof("Handling approve order command [%s]", cmd.CorrelationID)
 
-	globalID, err := uuid.Parse(event.CorrelationID)
+	globalID, err := uuid.Parse(cmd.CorrelationID)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error parsing correlation ID")
-		return nil, err
+		return struct{}{}, err
 	}
 
 	err = h.approveOrderUseCase.Execute(ctx, usecases.ApproveOrderRequest{
 		GlobalID: globalID,
 	})
+	if errors.Is(err, usecases.ErrOrderNotFound) {
+		lggr.With(zap.Error(err)).Error("Order not found. Command will not be retried")
+		return struct{}{}, err
+	}
 	if err != nil {
-		lggr.Info("Got error approving order. Because this is a retryable operation, after a go-non-go decision, the event should be sent to retry my any mechanism")
-		return nil, nil
+		lggr.With(zap.Error(err)).Error("Got error approving order. Command will be retried")
+		return struct{}{}, participant.Retryable(err)
 	}
-	successEvt := events.NewEvent("order_approved", "orders", map[string]interface{}{}).WithCorrelationID(event.CorrelationID)
-	return successEvt, nil
+	return struct{}{}, nil
 }
@@ -2,11 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/usecases"
-	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	goval "github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -16,70 +15,48 @@ type CreateOrderHandler struct {
 	createOrderUseCase usecases.CreateOrderUseCasePort
 }
 
-var (
-	_ application.MessageHandler = (*CreateOrderHandler)(nil)
-)
+type CreateOrderRequest struct {
+	CustomerID   uuid.UUID `json:"customer_id" validate:"required"`
+	Amount       *int64    `json:"amount" validate:"required,gt=0"`
+	CurrencyCode string    `json:"currency_code" validate:"required,len=3"`
+}
 
-type request struct {
-	CustomerID   uuid.UUID `json:"customer_id"`
-	Amount       *int64    `json:"amount"`
-	CurrencyCode string    `json:"currency_code"`
+type CreateOrderResponse struct {
+	ID uuid.UUID `json:"id"`
 }
 
-func NewCreateOrderHandler(logger *zap.SugaredLogger, createOrderUseCase usecases.CreateOrderUseCasePort) *CreateOrderHandler {
-	return &CreateOrderHandler{
+func NewCreateOrderHandler(
+	logger *zap.SugaredLogger,
+	validate *goval.Validate,
+	createOrderUseCase usecases.CreateOrderUseCasePort,
+) *participant.TypedHandler[CreateOrderRequest, CreateOrderResponse] {
+	h := &CreateOrderHandler{
 		logger:             logger,
 		createOrderUseCase: createOrderUseCase,
 	}
+	return participant.NewTypedHandler(validate, h.Handle)
 }
 
-func parseInput(data map[string]interface{}, dest interface{}) error {
-	raw, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(raw, dest)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-// TODO: add enum
-// Request:      "create_order",
-// Success:      "order_created",
-// Failure:      "order_creation_failed",
-// Compensation: "order_creation_compensated",
-
-func (h *CreateOrderHandler) Handle(ctx context.Context, event *events.Event) (*events.Event, error) {
+func (h *CreateOrderHandler) Handle(ctx context.Context, cmd participant.Command[CreateOrderRequest]) (CreateOrderResponse, error) {
 	lggr := h.logger
-	lggr.Infof("Handling message [%s]", event.Type)
+	lggr.Infof("Handling create order command [%s]", cmd.CorrelationID)
 
-	globalID, err := uuid.Parse(event.CorrelationID)
+	globalID, err := uuid.Parse(cmd.CorrelationID)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error parsing correlation ID")
-		return nil, err
-	}
-	var req request
-	err = parseInput(event.Data, &req)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error reading input")
-		return nil, err
+		return CreateOrderResponse{}, err
 	}
 	createRes, err := h.createOrderUseCase.Execute(ctx, usecases.CreateOrderRequest{
 		GlobalID:     globalID,
-		CustomerID:   req.CustomerID,
-		Amount:       *req.Amount,
-		CurrencyCode: req.CurrencyCode,
+		CustomerID:   cmd.Data.CustomerID,
+		Amount:       *cmd.Data.Amount,
+		CurrencyCode: cmd.Data.CurrencyCode,
 	})
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error creating order")
-		errEvent := events.NewEvent("order_creation_failed", "orders", map[string]interface{}{}).WithCorrelationID(event.CorrelationID)
-		return errEvent, nil
+		return CreateOrderResponse{}, err
 	}
 
 	lggr.Infof("Successfully created order [%s]", createRes)
-	res := map[string]interface{}{"id": createRes.ID}
-	successEvt := events.NewEvent("order_created", "orders", res).WithCorrelationID(event.CorrelationID)
-	return successEvt, nil
+	return CreateOrderResponse{ID: createRes.ID}, nil
 }
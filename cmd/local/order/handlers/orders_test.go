@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/usecases"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type approveOrderUseCaseStub struct {
+	err error
+}
+
+func (s approveOrderUseCaseStub) Execute(context.Context, usecases.ApproveOrderRequest) error {
+	return s.err
+}
+
+type rejectOrderUseCaseStub struct {
+	err error
+}
+
+func (s rejectOrderUseCaseStub) Execute(context.Context, usecases.RejectOrderRequest) error {
+	return s.err
+}
+
+func TestOrderStatusHandlers(t *testing.T) {
+	var (
+		lggr          = zap.NewNop().Sugar()
+		errDB         = errors.New("connection refused")
+		correlationID = uuid.NewString()
+	)
+	handlers := map[string]func(err error) participant.TypedHandlerFunc[struct{}, struct{}]{
+		"approve": func(err error) participant.TypedHandlerFunc[struct{}, struct{}] {
+			return (&ApproveOrder{logger: lggr, approveOrderUseCase: approveOrderUseCaseStub{err: err}}).Handle
+		},
+		"reject": func(err error) participant.TypedHandlerFunc[struct{}, struct{}] {
+			return (&RejectOrder{logger: lggr, rejectOrderUseCase: rejectOrderUseCaseStub{err: err}}).Handle
+		},
+	}
+	tests := []struct {
+		name          string
+		correlationID string
+		err           error
+		wantErr       bool
+		wantRetryable bool
+	}{
+		{name: "should succeed when the use case succeeds", correlationID: correlationID},
+		{name: "should retry the errors of the infrastructure", correlationID: correlationID, err: errDB, wantErr: true, wantRetryable: true},
+		{name: "should not retry when the order doesn't exist", correlationID: correlationID, err: fmt.Errorf("finding: %w", usecases.ErrOrderNotFound), wantErr: true},
+		{name: "should not retry an invalid correlation ID", correlationID: "123", wantErr: true},
+	}
+	for action, newHandler := range handlers {
+		for _, tt := range tests {
+			t.Run(action+" "+tt.name, func(t *testing.T) {
+				handle := newHandler(tt.err)
+
+				_, err := handle(context.Background(), participant.Command[struct{}]{CorrelationID: tt.correlationID})
+
+				if !tt.wantErr {
+					assert.NoError(t, err)
+					return
+				}
+				assert.Error(t, err)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				}
+				assert.Equal(t, tt.wantRetryable, participant.IsRetryable(err))
+			})
+		}
+	}
+}
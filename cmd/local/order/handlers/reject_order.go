@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/usecases"
-	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	goval "github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -15,38 +16,38 @@ type RejectOrder struct {
 	rejectOrderUseCase usecases.RejectOrderUseCasePort
 }
 
-var (
-	_ application.MessageHandler = (*ApproveOrder)(nil)
-)
-
-func NewRejectOrder(logger *zap.SugaredLogger, rejectOrderUseCase usecases.RejectOrderUseCasePort) *RejectOrder {
-	return &RejectOrder{
+func NewRejectOrder(
+	logger *zap.SugaredLogger,
+	validate *goval.Validate,
+	rejectOrderUseCase usecases.RejectOrderUseCasePort,
+) *participant.TypedHandler[struct{}, struct{}] {
+	h := &RejectOrder{
 		logger:             logger,
 		rejectOrderUseCase: rejectOrderUseCase,
 	}
+	return participant.NewTypedHandler(validate, h.Handle)
 }
 
-// TODO: add enum
-// reject_order
-// order_rejected
-
-func (h *RejectOrder) Handle(ctx context.Context, event *events.Event) (*events.Event, error) {
+func (h *RejectOrder) Handle(ctx context.Context, cmd participant.Command[struct{}]) (struct{}, error) {
 	lggr := h.logger
-	lggr.Infof("Handling message [%s]", event.Type)
+	lggr.Infof("Handling reject order command [%s]", cmd.CorrelationID)
 
-	globalID, err := uuid.Parse(event.CorrelationID)
+	globalID, err := uuid.Parse(cmd.CorrelationID)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error parsing correlation ID")
-		return nil, err
+		return struct{}{}, err
 	}
 
 	err = h.rejectOrderUseCase.Execute(ctx, usecases.RejectOrderRequest{
 		GlobalID: globalID,
 	})
+	if errors.Is(err, usecases.ErrOrderNotFound) {
+		lggr.With(zap.Error(err)).Error("Order not found. Command will not be retried")
+		return struct{}{}, err
+	}
 	if err != nil {
-		lggr.Info("Got error rejecting order. Because this is a compesation operation, after a go-non-go decision, the event should be sent to retry my any mechanism")
-		return nil, nil
+		lggr.With(zap.Error(err)).Error("Got error rejecting order. Command will be retried")
+		return struct{}{}, participant.Retryable(err)
 	}
-	successEvt := events.NewEvent("order_rejected", "orders", map[string]interface{}{}).WithCorrelationID(event.CorrelationID)
-	return successEvt, nil
+	return struct{}{}, nil
 }
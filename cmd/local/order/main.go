@@ -6,18 +6,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
+	"github.com/bmviniciuss/sagas-golang/cmd/local/contracts"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/adapters/repositores/order" // TODO: fix typo
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/api"
-	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/application/usecases"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/config/env"
 	"github.com/bmviniciuss/sagas-golang/cmd/local/order/handlers"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kafkaadmin"
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kv"
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
 	"github.com/bmviniciuss/sagas-golang/internal/config/logger"
+	"github.com/bmviniciuss/sagas-golang/internal/lifecycle"
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
 	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/internal/topics"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -25,15 +32,8 @@ import (
 )
 
 func main() {
-	var (
-		ctx, cancel = context.WithCancel(context.Background())
-		sigCh       = make(chan os.Signal, 1)
-		errCh       = make(chan error, 1)
-	)
-	defer cancel()
-	defer close(sigCh)
-	defer close(errCh)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	cfg, err := env.Load()
 	if err != nil {
@@ -44,12 +44,17 @@ func main() {
 	defer lggr.Sync()
 
 	lggr.Info("Starting Order Service")
+	lc := lifecycle.New(lggr, cfg.ShutdownTimeout)
+
 	dbpool, err := pgxpool.New(context.Background(), cfg.DBConnectionString)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to create connection pool: %v\n", err)
 		os.Exit(1)
 	}
-	defer dbpool.Close()
+	lc.OnStop("database", func(context.Context) error {
+		dbpool.Close()
+		return nil
+	})
 	lggr.Info("Connected to database")
 
 	redisConn := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
@@ -57,78 +62,121 @@ func main() {
 	if err != nil {
 		lggr.With(zap.Error(err)).Fatal("Got error connecting to Redis")
 	}
+	lc.OnStop("redis", func(context.Context) error {
+		return redisConn.Close()
+	})
 
 	var (
 		bootstrapServers = cfg.KafkaBootstrapServers
-		topics           = strings.Split(cfg.KafkaTopics, ",")
 		group            = cfg.KafkaGroupID
 	)
 
 	publisher := streaming.NewPublisher(lggr, &kafka.ConfigMap{
 		"bootstrap.servers": bootstrapServers,
 	})
+	lc.OnStop("publisher", publisher.Close)
 
 	var (
-		ordersRepository = order.NewRepositoryAdapter(lggr, dbpool)
-		usecasesMap      = map[string]application.MessageHandler{
-			"create_order":  handlers.NewCreateOrderHandler(lggr, usecases.NewCreateOrder(lggr, ordersRepository)),
-			"approve_order": handlers.NewApproveOrder(lggr, usecases.NewApproveOrder(lggr, ordersRepository)),
-			"reject_order":  handlers.NewRejectOrder(lggr, usecases.NewRejectOrder(lggr, ordersRepository)),
-		}
+		ordersRepository   = order.NewRepositoryAdapter(lggr, dbpool)
+		idempotenceService = kv.NewAdapter(lggr, redisConn)
+		val                = validator.New()
 	)
 
-	handler := NewOrderMessageHandler(lggr, *publisher, usecasesMap)
-	consumer, err := streaming.NewConsumer(lggr, topics, &kafka.ConfigMap{
-		"bootstrap.servers":        bootstrapServers,
-		"broker.address.family":    "v4",
-		"group.id":                 group,
-		"session.timeout.ms":       6000,
-		"auto.offset.reset":        "earliest",
-		"enable.auto.offset.store": false,
-		"enable.auto.commit":       true,
-	}, handler)
+	handler, err := participant.NewRuntime(lggr, publisher, idempotenceService,
+		participant.RequestRoute(contracts.CreateOrder, handlers.NewCreateOrderHandler(lggr, val, usecases.NewCreateOrder(lggr, ordersRepository))),
+		participant.CompensationRoute(contracts.CreateOrder, handlers.NewRejectOrder(lggr, val, usecases.NewRejectOrder(lggr, ordersRepository))),
+		participant.RequestRoute(contracts.ApproveOrder, handlers.NewApproveOrder(lggr, val, usecases.NewApproveOrder(lggr, ordersRepository))),
+	)
 	if err != nil {
-		lggr.With(zap.Error(err)).Fatal("Got error creating consumer")
+		lggr.With(zap.Error(err)).Fatal("Got error creating participant runtime")
+	}
+	retryPolicy := participant.RetryPolicy{Delays: cfg.KafkaRetryDelays}
+	if err := retryPolicy.Validate(maxPollInterval); err != nil {
+		lggr.With(zap.Error(err)).Fatal("KAFKA_RETRY_DELAYS is invalid")
+	}
+	handler.WithRetryPolicy(retryPolicy)
+	if err := checkRetryTopics(ctx, lggr, bootstrapServers, handler.Topics(), retryPolicy); err != nil {
+		lggr.With(zap.Error(err)).Fatal("KAFKA_RETRY_DELAYS does not match the retry topics, see KAFKA_TOPIC_RETRIES of the orchestrator")
 	}
 
-	go func() {
-		if err := consumer.Start(ctx); err != nil {
-			lggr.With(zap.Error(err)).Fatal("Got in consumer")
-			errCh <- err
+	consumer, err := streaming.NewConsumer(lggr, handler.Topics(), newConsumerConfig(bootstrapServers, group), handler)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error creating consumer")
+	}
+	lc.Go("consumer", consumer.Start)
+	// Each retry topic holds messages for a fixed delay, so it gets its own consumer
+	// to keep a long delay from blocking the messages of a shorter one.
+	for _, topic := range handler.RetryTopics() {
+		retryConsumer, err := streaming.NewConsumer(lggr.With("topic", topic), []string{topic}, newConsumerConfig(bootstrapServers, fmt.Sprintf("%s.%s", group, topic)), handler.Retries())
+		if err != nil {
+			lggr.With(zap.Error(err)).Fatalf("Got error creating retry consumer for topic [%s]", topic)
 		}
-	}()
+		lc.Go(fmt.Sprintf("retry consumer of %s", topic), retryConsumer.Start)
+	}
+
+	authenticator, err := auth.NewAuthenticator(auth.Config{
+		APIKeysFile: cfg.AuthAPIKeysFile,
+		JWKSFile:    cfg.AuthJWKSFile,
+		JWT: auth.JWTConfig{
+			Issuer:   cfg.AuthJWTIssuer,
+			Audience: cfg.AuthJWTAudience,
+			Leeway:   cfg.AuthJWTLeeway,
+		},
+		Disabled: cfg.AuthDisabled,
+	})
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error loading API credentials")
+	}
+	if authenticator == nil {
+		lggr.Warn("AUTH_DISABLED is set. API authentication is disabled")
+	}
 
 	var (
 		listUseCase  = usecases.NewListOrders(lggr, ordersRepository)
 		getOrderByID = usecases.NewGetOrderByID(lggr, ordersRepository)
 		apiHandlers  = api.NewHandlers(lggr, listUseCase, getOrderByID)
-		httpServer   = newApiServer(":3000", apiHandlers)
+		httpServer   = newApiServer(":3000", apiHandlers, authenticator, api.NewOpenAPI(), handler.Metrics().Handler())
 	)
 
-	go func() {
-		lggr.Info("Starting Orders Service API go routine")
-		if err := httpServer.ListenAndServe(); err != nil {
-			lggr.With(zap.Error(err)).Fatal("Got error in Orders Service API server")
-			errCh <- err
-		}
-	}()
-
-	lggr.Info("Running Order Service. Waiting for signal to stop...")
-	select {
-	case <-sigCh:
-		lggr.Info("Got signal, stopping consumer")
-		cancel()
-	case err := <-errCh:
-		lggr.With(zap.Error(err)).Fatal("Got error in consumer")
-		cancel()
-		os.Exit(1)
+	err = lc.Server("Orders Service API", httpServer).Run(ctx)
+	if err != nil {
+		lggr.With(zap.Error(err)).Fatal("Got error running Order Service")
 	}
 
 	lggr.Info("Exiting")
 }
 
-func newApiServer(addr string, handlers api.OrderHandlers) *http.Server {
-	mux := api.NewRouter(handlers).Build()
+// checkRetryTopics fails unless the cluster has the retry and dead letter topics of the retry policy, and no other retry topic.
+// The topics are provisioned by the orchestrator from its KAFKA_TOPIC_RETRIES, which must match the policy.
+func checkRetryTopics(ctx context.Context, lggr *zap.SugaredLogger, bootstrapServers string, requestTopics []string, policy participant.RetryPolicy) error {
+	client, err := kafka.NewAdminClient(&kafka.ConfigMap{"bootstrap.servers": bootstrapServers})
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error creating Kafka admin client")
+		return err
+	}
+	defer client.Close()
+	return topics.CheckRetryTopics(ctx, kafkaadmin.NewAdapter(lggr, client), requestTopics, policy.MaxRetries())
+}
+
+// maxPollInterval is the longest time the consumers can go without polling before leaving their group.
+// Retry consumers hold each message until its retry delay elapses, so every delay must be shorter.
+const maxPollInterval = 5 * time.Minute
+
+func newConsumerConfig(bootstrapServers, group string) *kafka.ConfigMap {
+	return &kafka.ConfigMap{
+		"bootstrap.servers":        bootstrapServers,
+		"broker.address.family":    "v4",
+		"group.id":                 group,
+		"session.timeout.ms":       6000,
+		"max.poll.interval.ms":     int(maxPollInterval.Milliseconds()),
+		"auto.offset.reset":        "earliest",
+		"enable.auto.offset.store": false,
+		"enable.auto.commit":       true,
+	}
+}
+
+func newApiServer(addr string, handlers api.OrderHandlers, authenticator auth.Authenticator, spec *openapi.Document, metrics http.Handler) *http.Server {
+	mux := api.NewRouter(handlers, authenticator, spec, metrics).Build()
 	return &http.Server{
 		Addr:              addr,
 		Handler:           mux,
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/responses"
+)
+
+// Client calls the orchestrator API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// credentials are the authentication headers sent with every request.
+	credentials http.Header
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		http:        httpClient,
+		credentials: http.Header{},
+	}
+}
+
+// WithAPIKey authenticates the requests with an API key.
+func (c *Client) WithAPIKey(key string) *Client {
+	if key != "" {
+		c.credentials.Set("X-API-Key", key)
+	}
+	return c
+}
+
+// WithToken authenticates the requests with a bearer token.
+func (c *Client) WithToken(token string) *Client {
+	if token != "" {
+		c.credentials.Set("Authorization", "Bearer "+token)
+	}
+	return c
+}
+
+// APIError is returned for the responses with a status other than 2xx.
+type APIError struct {
+	Status int
+	Body   []byte
+}
+
+// Error describes the problem details of the response, or its raw body if it is not a problem.
+func (e *APIError) Error() string {
+	var problem responses.Problem
+	if err := json.Unmarshal(e.Body, &problem); err != nil || problem.Title == "" {
+		return fmt.Sprintf("orchestrator responded with status %d: %s", e.Status, strings.TrimSpace(string(e.Body)))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "orchestrator responded with status %d: %s", e.Status, problem.Title)
+	if problem.Detail != "" {
+		fmt.Fprintf(&b, ". %s", problem.Detail)
+	}
+	for _, field := range problem.Errors {
+		fmt.Fprintf(&b, "\n  %s: %s", field.Field, field.Message)
+	}
+	return b.String()
+}
+
+// Do sends a request to the orchestrator API and returns the response body.
+func (c *Client) Do(ctx context.Context, method string, path string, query url.Values, headers http.Header, body []byte) ([]byte, error) {
+	res, err := c.send(ctx, method, path, query, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &APIError{Status: res.StatusCode, Body: data}
+	}
+	return data, nil
+}
+
+// Stream reads the Server-Sent Events of path, calling fn with the name and data of each event until the stream ends.
+func (c *Client) Stream(ctx context.Context, path string, fn func(event string, data string) error) error {
+	headers := http.Header{}
+	headers.Set("Accept", "text/event-stream")
+	res, err := c.send(ctx, http.MethodGet, path, nil, headers, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(res.Body)
+		return &APIError{Status: res.StatusCode, Body: data}
+	}
+
+	var (
+		scanner = bufio.NewScanner(res.Body)
+		event   string
+		data    []string
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if err := fn(event, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// keep-alive comment
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, query url.Values, headers http.Header, body []byte) (*http.Response, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.credentials {
+		req.Header[key] = values
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.http.Do(req)
+}
+
+// printJSON writes data indented, or as is when it is not JSON.
+func printJSON(out io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		_, err = out.Write(data)
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(out)
+	return err
+}
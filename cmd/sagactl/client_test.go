@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  APIError
+		want string
+	}{
+		{
+			name: "should describe the problem details",
+			err:  APIError{Status: 409, Body: []byte(`{"title":"Conflict","status":409,"detail":"execution is not running"}`)},
+			want: "orchestrator responded with status 409: Conflict. execution is not running",
+		},
+		{
+			name: "should list the invalid fields",
+			err:  APIError{Status: 400, Body: []byte(`{"title":"Bad Request","status":400,"errors":[{"field":"step","message":"is required"}]}`)},
+			want: "orchestrator responded with status 400: Bad Request\n  step: is required",
+		},
+		{
+			name: "should return the raw body when it is not a problem",
+			err:  APIError{Status: 502, Body: []byte("bad gateway\n")},
+			want: "orchestrator responded with status 502: bad gateway",
+		},
+		{
+			name: "should return the raw body when the problem has no title",
+			err:  APIError{Status: 500, Body: []byte(`{"message":"boom"}`)},
+			want: `orchestrator responded with status 500: {"message":"boom"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Error())
+		})
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+	}{
+		{name: "should return the body of a 2xx response", status: http.StatusOK, body: `{"id":"abc"}`},
+		{name: "should return the body of an accepted response", status: http.StatusAccepted, body: `{"id":"abc"}`},
+		{name: "should return an APIError for a client error", status: http.StatusNotFound, body: `{"title":"Not Found","status":404}`, wantStatus: http.StatusNotFound},
+		{name: "should return an APIError for a server error", status: http.StatusInternalServerError, body: "boom", wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			data, err := NewClient(server.URL+"/", server.Client()).Do(context.Background(), http.MethodGet, "/v1/executions/abc", nil, nil, nil)
+
+			if tt.wantStatus != 0 {
+				var apiErr *APIError
+				require.True(t, errors.As(err, &apiErr))
+				assert.Equal(t, tt.wantStatus, apiErr.Status)
+				assert.Equal(t, tt.body, string(apiErr.Body))
+				assert.Nil(t, data)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(data))
+		})
+	}
+
+	t.Run("should return the error of an unreachable orchestrator", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewClient(server.URL, server.Client()).Do(context.Background(), http.MethodGet, "/v1/workflows", nil, nil, nil)
+
+		var apiErr *APIError
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &apiErr))
+	})
+}
+
+func TestClient_Stream(t *testing.T) {
+	t.Run("should call fn with each event", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(": keep-alive\n\nevent: requested\ndata: {\"step\":\"create_order\"}\n\nevent: finished\ndata: first\ndata: second\n\n"))
+		}))
+		defer server.Close()
+		var got [][2]string
+
+		err := NewClient(server.URL, server.Client()).Stream(context.Background(), "/v1/executions/abc/events", func(event string, data string) error {
+			got = append(got, [2]string{event, data})
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][2]string{{"requested", `{"step":"create_order"}`}, {"finished", "first\nsecond"}}, got)
+	})
+
+	t.Run("should return an APIError when the stream is not opened", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"title":"Not Found","status":404}`))
+		}))
+		defer server.Close()
+
+		err := NewClient(server.URL, server.Client()).Stream(context.Background(), "/v1/executions/abc/events", func(string, string) error {
+			return nil
+		})
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	})
+
+	t.Run("should stop with the error of fn", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("event: requested\ndata: {}\n\nevent: succeeded\ndata: {}\n\n"))
+		}))
+		defer server.Close()
+		calls := 0
+		boom := errors.New("boom")
+
+		err := NewClient(server.URL, server.Client()).Stream(context.Background(), "/v1/executions/abc/events", func(string, string) error {
+			calls++
+			return boom
+		})
+
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+func runWorkflows(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("workflows", flag.ContinueOnError)
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	return a.get(ctx, "/v1/workflows", nil)
+}
+
+func runWorkflow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("workflow <name>", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	return a.get(ctx, "/v1/workflows/"+url.PathEscape(positional[0]), nil)
+}
+
+func runStart(ctx context.Context, a *app, args []string) error {
+	var (
+		fs             = flag.NewFlagSet("start <workflow>", flag.ContinueOnError)
+		file           = fs.String("f", "", "JSON file with the execution input, - reads it from stdin")
+		idempotencyKey = fs.String("idempotency-key", "", "deduplicate starts with the same key")
+		callbackURL    = fs.String("callback-url", "", "URL receiving the webhook notifications of the execution")
+		wait           = fs.Duration("wait", 0, "wait up to this duration for the execution to finish")
+	)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	var input []byte
+	if *file == "-" {
+		input, err = io.ReadAll(os.Stdin)
+	} else {
+		input, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+	if !json.Valid(input) {
+		return fmt.Errorf("%s is not valid JSON", *file)
+	}
+
+	headers := http.Header{}
+	if *idempotencyKey != "" {
+		headers.Set("Idempotency-Key", *idempotencyKey)
+	}
+	if *callbackURL != "" {
+		headers.Set("Callback-URL", *callbackURL)
+	}
+	query := url.Values{}
+	if *wait > 0 {
+		query.Set("wait", wait.String())
+	}
+	return a.print(a.client.Do(ctx, http.MethodPost, "/v1/workflows/"+url.PathEscape(positional[0])+"/executions", query, headers, input))
+}
+
+func runExecutions(ctx context.Context, a *app, args []string) error {
+	var (
+		fs    = flag.NewFlagSet("executions", flag.ContinueOnError)
+		query = url.Values{}
+	)
+	for _, name := range []string{"workflow", "status", "business_key", "correlation_id", "created_from", "created_to", "cursor", "limit"} {
+		name := name
+		fs.Func(name, fmt.Sprintf("filter by %s", name), func(value string) error {
+			query.Set(name, value)
+			return nil
+		})
+	}
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	return a.get(ctx, "/v1/executions", query)
+}
+
+func runGet(ctx context.Context, a *app, args []string) error {
+	id, err := executionID("get", args)
+	if err != nil {
+		return err
+	}
+	return a.get(ctx, "/v1/executions/"+id, nil)
+}
+
+func runEvents(ctx context.Context, a *app, args []string) error {
+	id, err := executionID("events", args)
+	if err != nil {
+		return err
+	}
+	return a.client.Stream(ctx, "/v1/executions/"+id+"/events", func(event string, data string) error {
+		_, err := fmt.Fprintf(a.out, "%s %-24s %s\n", time.Now().Format(time.TimeOnly), event, data)
+		return err
+	})
+}
+
+func runCancel(ctx context.Context, a *app, args []string) error {
+	return a.executionAction(ctx, "cancel", args)
+}
+
+func runRetry(ctx context.Context, a *app, args []string) error {
+	return a.executionAction(ctx, "retry", args)
+}
+
+func runResume(ctx context.Context, a *app, args []string) error {
+	var (
+		fs   = flag.NewFlagSet("resume <execution-id>", flag.ContinueOnError)
+		step = fs.String("step", "", "name of the step to resume from")
+	)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *step == "" {
+		fs.Usage()
+		return errUsage
+	}
+	body, err := json.Marshal(map[string]string{"step": *step})
+	if err != nil {
+		return err
+	}
+	return a.print(a.client.Do(ctx, http.MethodPost, "/v1/executions/"+url.PathEscape(positional[0])+"/resume", nil, nil, body))
+}
+
+func (a *app) executionAction(ctx context.Context, action string, args []string) error {
+	id, err := executionID(action, args)
+	if err != nil {
+		return err
+	}
+	return a.print(a.client.Do(ctx, http.MethodPost, "/v1/executions/"+id+"/"+action, nil, nil, nil))
+}
+
+func (a *app) get(ctx context.Context, path string, query url.Values) error {
+	return a.print(a.client.Do(ctx, http.MethodGet, path, query, nil, nil))
+}
+
+func (a *app) print(data []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	return printJSON(a.out, data)
+}
+
+func executionID(name string, args []string) (string, error) {
+	fs := flag.NewFlagSet(name+" <execution-id>", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return "", err
+	}
+	return url.PathEscape(positional[0]), nil
+}
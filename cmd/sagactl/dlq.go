@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// errNotAwaited is returned when the execution of a dead lettered command no longer waits for its reply.
+var errNotAwaited = errors.New("execution is not waiting for the command")
+
+// dlqFilter selects the dead lettered commands to list or replay.
+type dlqFilter struct {
+	correlationID string
+	eventType     string
+}
+
+func (f dlqFilter) IsEmpty() bool {
+	return f.correlationID == "" && f.eventType == ""
+}
+
+func (f dlqFilter) Matches(msg participant.RetryMessage) bool {
+	return (f.correlationID == "" || msg.Event.CorrelationID == f.correlationID) &&
+		(f.eventType == "" || msg.Event.Type == f.eventType)
+}
+
+func runDLQ(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "replay") {
+		return fmt.Errorf("%w: dlq list|replay --topic <topic>", errUsage)
+	}
+	var (
+		action = args[0]
+		fs     = flag.NewFlagSet("dlq "+action, flag.ContinueOnError)
+		topic  = fs.String("topic", "", "request topic of the participant, e.g. service.orders.request, or its dead letter topic")
+		idle   = fs.Duration("idle", 10*time.Second, "stop reading once no message is received for this duration")
+		all    = fs.Bool("all", false, "replay every dead lettered command")
+		force  = fs.Bool("force", false, "replay the commands even if their execution is no longer waiting for them")
+		filter dlqFilter
+	)
+	fs.StringVar(&filter.correlationID, "correlation-id", "", "only the commands of this execution")
+	fs.StringVar(&filter.eventType, "event-type", "", "only the commands of this type")
+	if _, err := parseArgs(fs, args[1:], 0); err != nil {
+		return err
+	}
+	if *topic == "" {
+		fs.Usage()
+		return errUsage
+	}
+	if action == "replay" && filter.IsEmpty() && !*all {
+		return fmt.Errorf("%w: replay needs --correlation-id, --event-type or --all", errUsage)
+	}
+
+	dlqTopic := *topic
+	if !strings.HasSuffix(dlqTopic, ".dlq") {
+		dlqTopic = participant.DeadLetterTopic(dlqTopic)
+	}
+
+	lggr := zap.NewNop().Sugar()
+	if a.verbose {
+		l, err := zap.NewDevelopment()
+		if err != nil {
+			return err
+		}
+		lggr = l.Sugar()
+	}
+	publisher := streaming.NewPublisher(lggr, &kafka.ConfigMap{"bootstrap.servers": a.bootstrapServers})
+	defer publisher.Close(ctx)
+
+	var (
+		matched int
+		guard   = newReplayGuard(a.client)
+	)
+	err := a.readDeadLetters(ctx, dlqTopic, *idle, func(offset kafka.TopicPartition, msg participant.RetryMessage) error {
+		if !filter.Matches(msg) {
+			return nil
+		}
+		matched++
+		fmt.Fprintf(a.out, "%d/%s correlation_id=%s event_type=%s attempts=%d error=%q\n",
+			offset.Partition, offset.Offset, msg.Event.CorrelationID, msg.Event.Type, msg.Attempt, msg.Error)
+		if action == "list" {
+			return nil
+		}
+		if !*force {
+			err := guard.check(ctx, msg.Event)
+			if errors.Is(err, errNotAwaited) {
+				fmt.Fprintf(a.out, "  skipped: %v, --force replays it anyway\n", err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		replay := msg.Replay()
+		data, err := replay.ToJSON()
+		if err != nil {
+			return err
+		}
+		if err := publisher.Publish(ctx, msg.Topic, data); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "  replayed to %s as %s\n", msg.Topic, replay.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%d dead lettered commands matched in %s\n", matched, dlqTopic)
+	return nil
+}
+
+// replayGuard checks with the orchestrator API that the execution of a dead lettered command still waits for it.
+// Once the retries of a command are exhausted the participant replies with a failure, so the execution has usually
+// moved on and replaying the command would run its side effect again for a finished step.
+type replayGuard struct {
+	client *Client
+	// steps are the steps of the workflows described so far, by workflow name.
+	steps map[string][]workflowStep
+}
+
+type workflowStep struct {
+	Name       string `json:"name"`
+	EventTypes struct {
+		Request             string `json:"request"`
+		CompensationRequest string `json:"compensation_request"`
+	} `json:"event_types"`
+}
+
+type executionStatus struct {
+	Workflow string `json:"workflow"`
+	Status   string `json:"status"`
+	Steps    []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"steps"`
+}
+
+func newReplayGuard(client *Client) *replayGuard {
+	return &replayGuard{client: client, steps: make(map[string][]workflowStep)}
+}
+
+// check returns errNotAwaited unless the execution of command is active and the last transition of the step
+// of command is the request of command.
+func (g *replayGuard) check(ctx context.Context, command events.Event) error {
+	data, err := g.client.Do(ctx, http.MethodGet, "/v1/executions/"+url.PathEscape(command.CorrelationID), nil, nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+		return fmt.Errorf("%w: execution %s was not found", errNotAwaited, command.CorrelationID)
+	}
+	if err != nil {
+		return err
+	}
+	var execution executionStatus
+	if err := json.Unmarshal(data, &execution); err != nil {
+		return err
+	}
+	steps, err := g.workflowSteps(ctx, execution.Workflow)
+	if err != nil {
+		return err
+	}
+
+	var stepName, awaited string
+	for _, step := range steps {
+		switch command.Type {
+		case step.EventTypes.Request:
+			stepName, awaited = step.Name, "requested"
+		case step.EventTypes.CompensationRequest:
+			stepName, awaited = step.Name, "compensation_requested"
+		}
+	}
+	if stepName == "" {
+		return fmt.Errorf("%w: workflow %s has no step requested with %s", errNotAwaited, execution.Workflow, command.Type)
+	}
+	if execution.Status != "running" && execution.Status != "compensating" && execution.Status != "cancelling" {
+		return fmt.Errorf("%w: execution %s is %s", errNotAwaited, command.CorrelationID, execution.Status)
+	}
+	for _, step := range execution.Steps {
+		if step.Name == stepName && step.Status != awaited {
+			return fmt.Errorf("%w: step %s of execution %s is %s", errNotAwaited, stepName, command.CorrelationID, step.Status)
+		}
+	}
+	return nil
+}
+
+func (g *replayGuard) workflowSteps(ctx context.Context, name string) ([]workflowStep, error) {
+	if steps, ok := g.steps[name]; ok {
+		return steps, nil
+	}
+	data, err := g.client.Do(ctx, http.MethodGet, "/v1/workflows/"+url.PathEscape(name), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var workflow struct {
+		Steps []workflowStep `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return nil, err
+	}
+	g.steps[name] = workflow.Steps
+	return workflow.Steps, nil
+}
+
+// readDeadLetters reads every message of topic from the beginning, without committing offsets,
+// until each partition is read to its end or no message arrives for idle.
+func (a *app) readDeadLetters(ctx context.Context, topic string, idle time.Duration, fn func(kafka.TopicPartition, participant.RetryMessage) error) error {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":    a.bootstrapServers,
+		"group.id":             "sagactl-" + uuid.NewString(),
+		"auto.offset.reset":    "earliest",
+		"enable.auto.commit":   false,
+		"enable.partition.eof": true,
+	})
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	metadata, err := consumer.GetMetadata(&topic, false, 5000)
+	if err != nil {
+		return err
+	}
+	partitions := len(metadata.Topics[topic].Partitions)
+	if partitions == 0 {
+		return fmt.Errorf("topic %s does not exist", topic)
+	}
+	if err := consumer.Subscribe(topic, nil); err != nil {
+		return err
+	}
+
+	var (
+		finished = make(map[int32]bool)
+		lastSeen = time.Now()
+	)
+	for len(finished) < partitions && time.Since(lastSeen) < idle {
+		if ctx.Err() != nil {
+			return nil
+		}
+		switch e := consumer.Poll(100).(type) {
+		case *kafka.Message:
+			lastSeen = time.Now()
+			var msg participant.RetryMessage
+			if err := json.Unmarshal(e.Value, &msg); err != nil {
+				fmt.Fprintf(a.out, "%d/%s is not a dead lettered command: %v\n", e.TopicPartition.Partition, e.TopicPartition.Offset, err)
+				continue
+			}
+			if err := fn(e.TopicPartition, msg); err != nil {
+				return err
+			}
+		case kafka.PartitionEOF:
+			lastSeen = time.Now()
+			finished[e.Partition] = true
+		case kafka.Error:
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayGuard_Check(t *testing.T) {
+	const workflow = `{"name":"create_order_v1","steps":[
+		{"name":"create_order","event_types":{"request":"create_order","compensation_request":"reject_order"}},
+		{"name":"verify_customer","event_types":{"request":"verify_customer"}}
+	]}`
+	command := func(eventType string) events.Event {
+		return events.Event{ID: "id", Type: eventType, CorrelationID: "execution-id"}
+	}
+
+	tests := []struct {
+		name      string
+		execution string
+		command   events.Event
+		wantErr   error
+	}{
+		{
+			name:      "should allow the request of the step a running execution waits for",
+			execution: `{"workflow":"create_order_v1","status":"running","steps":[{"name":"create_order","status":"requested"},{"name":"verify_customer","status":"pending"}]}`,
+			command:   command("create_order"),
+		},
+		{
+			name:      "should allow the compensation of the step a compensating execution waits for",
+			execution: `{"workflow":"create_order_v1","status":"compensating","steps":[{"name":"create_order","status":"compensation_requested"},{"name":"verify_customer","status":"failed"}]}`,
+			command:   command("reject_order"),
+		},
+		{
+			name:      "should refuse the command of a step that replied",
+			execution: `{"workflow":"create_order_v1","status":"compensating","steps":[{"name":"create_order","status":"failed"},{"name":"verify_customer","status":"pending"}]}`,
+			command:   command("create_order"),
+			wantErr:   errNotAwaited,
+		},
+		{
+			name:      "should refuse the commands of a finished execution",
+			execution: `{"workflow":"create_order_v1","status":"compensated","steps":[{"name":"create_order","status":"requested"}]}`,
+			command:   command("create_order"),
+			wantErr:   errNotAwaited,
+		},
+		{
+			name:      "should refuse commands the workflow doesn't send",
+			execution: `{"workflow":"create_order_v1","status":"running","steps":[{"name":"create_order","status":"requested"}]}`,
+			command:   command("approve_order"),
+			wantErr:   errNotAwaited,
+		},
+		{
+			name:    "should refuse the commands of an unknown execution",
+			command: command("create_order"),
+			wantErr: errNotAwaited,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/workflows/create_order_v1":
+					_, _ = w.Write([]byte(workflow))
+				case r.URL.Path == "/v1/executions/execution-id" && tt.execution != "":
+					_, _ = w.Write([]byte(tt.execution))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			guard := newReplayGuard(NewClient(server.URL, server.Client()))
+
+			err := guard.check(context.Background(), tt.command)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("should return the errors of the orchestrator", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		guard := newReplayGuard(NewClient(server.URL, server.Client()))
+
+		err := guard.check(context.Background(), command("create_order"))
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+		assert.NotErrorIs(t, err, errNotAwaited)
+	})
+}
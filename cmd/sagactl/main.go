@@ -0,0 +1,149 @@
+// Command sagactl operates the orchestrator from the command line: it lists workflows, starts and inspects
+// executions, tails their events, cancels, retries or resumes them and replays the dead lettered commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const usage = `Usage: sagactl [flags] <command> [args]
+
+Commands:
+%s
+Flags:
+`
+
+var errUsage = errors.New("invalid usage")
+
+// app holds the dependencies shared by the commands.
+type app struct {
+	client           *Client
+	out              io.Writer
+	bootstrapServers string
+	verbose          bool
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"workflows":  {"workflows                           list the workflows", runWorkflows},
+	"workflow":   {"workflow <name>                     describe a workflow and its steps", runWorkflow},
+	"start":      {"start <workflow> -f <input.json>    start an execution", runStart},
+	"executions": {"executions [--status ...]           search executions", runExecutions},
+	"get":        {"get <execution-id>                  inspect an execution", runGet},
+	"events":     {"events <execution-id>               tail the progress of an execution", runEvents},
+	"cancel":     {"cancel <execution-id>               cancel a running execution", runCancel},
+	"retry":      {"retry <execution-id>                publish the command of the current step again", runRetry},
+	"resume":     {"resume <execution-id> --step <name> resume an execution from a step", runResume},
+	"dlq":        {"dlq list|replay --topic <topic>     inspect or replay dead lettered commands", runDLQ},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sagactl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	var (
+		fs               = flag.NewFlagSet("sagactl", flag.ContinueOnError)
+		baseURL          = fs.String("url", envOrDefault("SAGACTL_URL", "http://localhost:3000"), "orchestrator API URL")
+		timeout          = fs.Duration("timeout", 30*time.Second, "timeout of each API request, streams are not limited")
+		bootstrapServers = fs.String("bootstrap-servers", envOrDefault("KAFKA_BOOTSTRAP_SERVERS", "localhost:9092"), "Kafka bootstrap servers, used by dlq")
+		apiKey           = fs.String("api-key", os.Getenv("SAGACTL_API_KEY"), "API key sent in the X-API-Key header")
+		token            = fs.String("token", os.Getenv("SAGACTL_TOKEN"), "bearer token sent in the Authorization header")
+		verbose          = fs.Bool("verbose", false, "log Kafka operations")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), usage, commandsUsage())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+	httpClient := &http.Client{Timeout: *timeout}
+	if name == "events" {
+		httpClient = &http.Client{}
+	}
+	a := &app{
+		client:           NewClient(*baseURL, httpClient).WithAPIKey(*apiKey).WithToken(*token),
+		out:              out,
+		bootstrapServers: *bootstrapServers,
+		verbose:          *verbose,
+	}
+	return cmd.run(ctx, a, fs.Args()[1:])
+}
+
+func commandsUsage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s\n", commands[name].usage)
+	}
+	return b.String()
+}
+
+// parseArgs parses the flags of fs found anywhere in args and returns the positional arguments.
+// It fails unless there are exactly want positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	positional := make([]string, 0, want)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != want {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
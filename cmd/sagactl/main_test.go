@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		want           int
+		wantPositional []string
+		wantStep       string
+		wantErr        error
+	}{
+		{name: "should parse flags before the positional arguments", args: []string{"--step", "create_order", "id"}, want: 1, wantPositional: []string{"id"}, wantStep: "create_order"},
+		{name: "should parse flags after the positional arguments", args: []string{"id", "--step", "create_order"}, want: 1, wantPositional: []string{"id"}, wantStep: "create_order"},
+		{name: "should parse flags between the positional arguments", args: []string{"a", "--step=create_order", "b"}, want: 2, wantPositional: []string{"a", "b"}, wantStep: "create_order"},
+		{name: "should accept no positional arguments when none is wanted", args: []string{}, want: 0, wantPositional: []string{}},
+		{name: "should reject missing positional arguments", args: []string{"--step", "create_order"}, want: 1, wantErr: errUsage},
+		{name: "should reject extra positional arguments", args: []string{"a", "b"}, want: 1, wantErr: errUsage},
+		{name: "should return help when it is asked", args: []string{"-h"}, want: 1, wantErr: flag.ErrHelp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			step := fs.String("step", "", "")
+
+			positional, err := parseArgs(fs, tt.args, tt.want)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPositional, positional)
+			assert.Equal(t, tt.wantStep, *step)
+		})
+	}
+
+	t.Run("should reject unknown flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+
+		_, err := parseArgs(fs, []string{"id", "--unknown"}, 1)
+
+		assert.ErrorContains(t, err, "flag provided but not defined")
+	})
+}
+
+func TestRun(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		query  url.Values
+		header http.Header
+		body   string
+	}
+	var got *request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = &request{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.Query(), header: r.Header, body: string(body)}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantMethod string
+		wantPath   string
+		wantQuery  url.Values
+		wantBody   string
+	}{
+		{name: "should list workflows", args: []string{"workflows"}, wantMethod: http.MethodGet, wantPath: "/v1/workflows"},
+		{name: "should describe a workflow", args: []string{"workflow", "create_order_v1"}, wantMethod: http.MethodGet, wantPath: "/v1/workflows/create_order_v1"},
+		{name: "should get an execution", args: []string{"get", "abc"}, wantMethod: http.MethodGet, wantPath: "/v1/executions/abc"},
+		{name: "should escape the execution id", args: []string{"get", "a/b"}, wantMethod: http.MethodGet, wantPath: "/v1/executions/a%2Fb"},
+		{
+			name:       "should search executions",
+			args:       []string{"executions", "--status", "running", "--workflow", "create_order_v1"},
+			wantMethod: http.MethodGet,
+			wantPath:   "/v1/executions",
+			wantQuery:  url.Values{"status": {"running"}, "workflow": {"create_order_v1"}},
+		},
+		{name: "should cancel an execution", args: []string{"cancel", "abc"}, wantMethod: http.MethodPost, wantPath: "/v1/executions/abc/cancel"},
+		{name: "should retry an execution", args: []string{"retry", "abc"}, wantMethod: http.MethodPost, wantPath: "/v1/executions/abc/retry"},
+		{
+			name:       "should resume an execution from a step",
+			args:       []string{"resume", "abc", "--step", "verify_customer"},
+			wantMethod: http.MethodPost,
+			wantPath:   "/v1/executions/abc/resume",
+			wantBody:   `{"step":"verify_customer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			var out bytes.Buffer
+
+			err := run(context.Background(), append([]string{"--url", server.URL}, tt.args...), &out)
+
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantMethod, got.method)
+			assert.Equal(t, tt.wantPath, got.path)
+			if tt.wantQuery != nil {
+				assert.Equal(t, tt.wantQuery, got.query)
+			}
+			assert.Equal(t, tt.wantBody, got.body)
+			assert.Equal(t, "{\n  \"status\": \"ok\"\n}\n", out.String())
+		})
+	}
+
+	t.Run("should send the credentials", func(t *testing.T) {
+		got = nil
+
+		err := run(context.Background(), []string{"--url", server.URL, "--api-key", "key", "--token", "token", "get", "abc"}, io.Discard)
+
+		require.NoError(t, err)
+		assert.Equal(t, "key", got.header.Get("X-API-Key"))
+		assert.Equal(t, "Bearer token", got.header.Get("Authorization"))
+	})
+
+	usageErrors := []struct {
+		name string
+		args []string
+	}{
+		{name: "should require a command", args: []string{}},
+		{name: "should require the execution id", args: []string{"get"}},
+		{name: "should reject extra arguments", args: []string{"cancel", "abc", "def"}},
+		{name: "should require the step to resume from", args: []string{"resume", "abc"}},
+		{name: "should require the input to start an execution", args: []string{"start", "create_order_v1"}},
+		{name: "should require the dlq action", args: []string{"dlq", "--topic", "service.orders.request"}},
+		{name: "should require the dlq topic", args: []string{"dlq", "list"}},
+		{name: "should require a filter to replay dead lettered commands", args: []string{"dlq", "replay", "--topic", "service.orders.request"}},
+	}
+	for _, tt := range usageErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+
+			err := run(context.Background(), append([]string{"--url", server.URL}, tt.args...), io.Discard)
+
+			assert.ErrorIs(t, err, errUsage)
+			assert.Nil(t, got)
+		})
+	}
+
+	t.Run("should reject unknown commands", func(t *testing.T) {
+		err := run(context.Background(), []string{"--url", server.URL, "unknown"}, io.Discard)
+
+		assert.ErrorContains(t, err, `unknown command "unknown"`)
+		assert.False(t, errors.Is(err, errUsage))
+	})
+}
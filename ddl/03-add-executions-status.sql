@@ -0,0 +1,6 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS status varchar(64) NOT NULL DEFAULT 'running',
+  ADD COLUMN IF NOT EXISTS intervention jsonb,
+  ADD COLUMN IF NOT EXISTS compensation_attempts jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_executions_status ON sagas.executions (status, updated_at);
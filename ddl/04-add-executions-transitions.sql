@@ -0,0 +1,3 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS current_step varchar(255) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS transitions jsonb NOT NULL DEFAULT '[]';
@@ -0,0 +1,12 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS business_key varchar(255) NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS sagas.idx_executions_status;
+
+CREATE INDEX IF NOT EXISTS idx_executions_created_at ON sagas.executions (created_at DESC, uuid DESC);
+
+CREATE INDEX IF NOT EXISTS idx_executions_workflow_name ON sagas.executions (workflow_name, created_at DESC, uuid DESC);
+
+CREATE INDEX IF NOT EXISTS idx_executions_status ON sagas.executions (status, created_at DESC, uuid DESC);
+
+CREATE INDEX IF NOT EXISTS idx_executions_business_key ON sagas.executions (business_key, created_at DESC, uuid DESC) WHERE business_key <> '';
@@ -0,0 +1,5 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS idempotency_key varchar(255),
+  ADD COLUMN IF NOT EXISTS idempotency_expires_at timestamptz;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_executions_idempotency_key ON sagas.executions (workflow_name, idempotency_key) WHERE idempotency_key IS NOT NULL;
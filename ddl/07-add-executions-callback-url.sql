@@ -0,0 +1,2 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS callback_url varchar(2048) NOT NULL DEFAULT '';
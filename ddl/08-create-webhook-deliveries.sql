@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS sagas.webhook_deliveries (
+  id serial PRIMARY KEY,
+  uuid uuid NOT NULL UNIQUE,
+  execution_uuid uuid NOT NULL,
+  url varchar(2048) NOT NULL,
+  event_type varchar(255) NOT NULL,
+  payload jsonb NOT NULL,
+  status varchar(64) NOT NULL DEFAULT 'pending',
+  attempts integer NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT now(),
+  last_error text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON sagas.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_execution ON sagas.webhook_deliveries (execution_uuid);
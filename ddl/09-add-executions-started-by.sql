@@ -0,0 +1,2 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS started_by varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_executions_active ON sagas.executions (workflow_name) WHERE status IN ('running', 'compensating', 'cancelling');
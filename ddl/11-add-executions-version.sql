@@ -0,0 +1,2 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
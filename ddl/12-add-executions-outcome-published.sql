@@ -0,0 +1,7 @@
+ALTER TABLE sagas.executions
+  ADD COLUMN IF NOT EXISTS outcome_published boolean NOT NULL DEFAULT false;
+
+-- the outcome of the executions finished before the column was added was already published
+UPDATE sagas.executions
+SET outcome_published = true
+WHERE status IN ('completed', 'compensated', 'force_completed', 'cancelled');
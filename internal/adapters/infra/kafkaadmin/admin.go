@@ -0,0 +1,80 @@
+package kafkaadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/topics"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+const metadataTimeout = 10 * time.Second
+
+// Adapter lists and creates topics with the Kafka admin client.
+type Adapter struct {
+	logger *zap.SugaredLogger
+	client *kafka.AdminClient
+}
+
+func NewAdapter(logger *zap.SugaredLogger, client *kafka.AdminClient) *Adapter {
+	return &Adapter{
+		logger: logger,
+		client: client,
+	}
+}
+
+var (
+	_ topics.Admin = (*Adapter)(nil)
+)
+
+func (a *Adapter) List(_ context.Context) ([]topics.Topic, error) {
+	metadata, err := a.client.GetMetadata(nil, true, int(metadataTimeout.Milliseconds()))
+	if err != nil {
+		a.logger.With(zap.Error(err)).Error("Got error getting cluster metadata")
+		return nil, err
+	}
+	list := make([]topics.Topic, 0, len(metadata.Topics))
+	for name, topic := range metadata.Topics {
+		if topic.Error.Code() != kafka.ErrNoError {
+			continue
+		}
+		replicationFactor := 0
+		if len(topic.Partitions) > 0 {
+			replicationFactor = len(topic.Partitions[0].Replicas)
+		}
+		list = append(list, topics.Topic{
+			Name:              name,
+			Partitions:        len(topic.Partitions),
+			ReplicationFactor: replicationFactor,
+		})
+	}
+	return list, nil
+}
+
+func (a *Adapter) Create(ctx context.Context, list []topics.Topic) error {
+	specs := make([]kafka.TopicSpecification, 0, len(list))
+	for _, topic := range list {
+		specs = append(specs, kafka.TopicSpecification{
+			Topic:             topic.Name,
+			NumPartitions:     topic.Partitions,
+			ReplicationFactor: topic.ReplicationFactor,
+		})
+	}
+	results, err := a.client.CreateTopics(ctx, specs, kafka.SetAdminOperationTimeout(metadataTimeout))
+	if err != nil {
+		a.logger.With(zap.Error(err)).Error("Got error creating topics")
+		return err
+	}
+	var errs []error
+	for _, result := range results {
+		code := result.Error.Code()
+		if code == kafka.ErrNoError || code == kafka.ErrTopicAlreadyExists {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("creating topic %s: %w", result.Topic, result.Error))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package kv
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/ratelimit"
+	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+)
+
+// InmemAdapter is an in-memory idempotence and rate limit store for services that run without Redis.
+type InmemAdapter struct {
+	mu       *sync.Mutex
+	data     map[string]time.Time
+	counters map[string]*counter
+}
+
+type counter struct {
+	hits      int64
+	expiresAt time.Time
+}
+
+func NewInmemAdapter() *InmemAdapter {
+	return &InmemAdapter{
+		mu:       &sync.Mutex{},
+		data:     make(map[string]time.Time),
+		counters: make(map[string]*counter),
+	}
+}
+
+var (
+	_ streaming.IdempotenceService = (*InmemAdapter)(nil)
+	_ ratelimit.Store              = (*InmemAdapter)(nil)
+)
+
+func (a *InmemAdapter) Has(_ context.Context, key string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expiresAt, ok := a.data[key]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(a.data, key)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (a *InmemAdapter) Set(_ context.Context, key string, ttl time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.data[key] = time.Now().Add(ttl)
+	return nil
+}
+
+func (a *InmemAdapter) Increment(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	for k, c := range a.counters {
+		if !now.Before(c.expiresAt) {
+			delete(a.counters, k)
+		}
+	}
+	c, ok := a.counters[key]
+	if !ok {
+		c = &counter{expiresAt: now.Add(window)}
+		a.counters[key] = c
+	}
+	c.hits++
+	return c.hits, c.expiresAt.Sub(now), nil
+}
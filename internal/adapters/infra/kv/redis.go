@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/bmviniciuss/sagas-golang/internal/ratelimit"
 	"github.com/bmviniciuss/sagas-golang/internal/streaming"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -26,8 +27,18 @@ func NewAdapter(
 
 var (
 	_ streaming.IdempotenceService = (*Adapter)(nil)
+	_ ratelimit.Store              = (*Adapter)(nil)
 )
 
+// incrementScript increments a counter, setting its expiration when it is created, and returns its value and TTL.
+var incrementScript = redis.NewScript(`
+local hits = redis.call('INCR', KEYS[1])
+if hits == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {hits, redis.call('PTTL', KEYS[1])}
+`)
+
 func (a *Adapter) Has(ctx context.Context, key string) (bool, error) {
 	l := a.logger
 	l.Infof("Checking if key [%s] exists", key)
@@ -53,3 +64,13 @@ func (a *Adapter) Set(ctx context.Context, key string, ttl time.Duration) error
 	}
 	return nil
 }
+
+func (a *Adapter) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	l := a.logger
+	values, err := incrementScript.Run(ctx, a.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error incrementing counter")
+		return 0, 0, err
+	}
+	return values[0], time.Duration(values[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,72 @@
+package progress
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/google/uuid"
+)
+
+const (
+	// subscriberBufferSize is the number of events a subscriber can fall behind before events are dropped.
+	subscriberBufferSize = 64
+)
+
+// InmemBroker fans out the progress events to the subscribers of the same process.
+type InmemBroker struct {
+	mu          *sync.Mutex
+	subscribers map[uuid.UUID]map[chan saga.ProgressEvent]struct{}
+}
+
+func NewInmemBroker() *InmemBroker {
+	return &InmemBroker{
+		mu:          &sync.Mutex{},
+		subscribers: make(map[uuid.UUID]map[chan saga.ProgressEvent]struct{}),
+	}
+}
+
+var (
+	_ saga.ProgressBroker = (*InmemBroker)(nil)
+)
+
+func (b *InmemBroker) Publish(_ context.Context, event saga.ProgressEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers[event.ExecutionID] {
+		select {
+		case ch <- event:
+		default:
+			// a slow subscriber must not block the saga processing
+		}
+	}
+	return nil
+}
+
+func (b *InmemBroker) Subscribe(ctx context.Context, executionID uuid.UUID) (<-chan saga.ProgressEvent, func(), error) {
+	ch := make(chan saga.ProgressEvent, subscriberBufferSize)
+	b.mu.Lock()
+	if _, ok := b.subscribers[executionID]; !ok {
+		b.subscribers[executionID] = make(map[chan saga.ProgressEvent]struct{})
+	}
+	b.subscribers[executionID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[executionID], ch)
+			if len(b.subscribers[executionID]) == 0 {
+				delete(b.subscribers, executionID)
+			}
+			close(ch)
+		})
+	}
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	return ch, unsubscribe, nil
+}
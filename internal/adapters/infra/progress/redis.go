@@ -0,0 +1,99 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RedisBroker fans out the progress events through Redis Pub/Sub,
+// so clients connected to any orchestrator replica receive the events published by the others.
+type RedisBroker struct {
+	logger *zap.SugaredLogger
+	client *redis.Client
+}
+
+func NewRedisBroker(
+	logger *zap.SugaredLogger,
+	client *redis.Client,
+) *RedisBroker {
+	return &RedisBroker{
+		logger: logger,
+		client: client,
+	}
+}
+
+var (
+	_ saga.ProgressBroker = (*RedisBroker)(nil)
+)
+
+func channel(executionID uuid.UUID) string {
+	return fmt.Sprintf("sagas:executions:%s:progress", executionID)
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, event saga.ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	err = b.client.Publish(ctx, channel(event.ExecutionID), data).Err()
+	if err != nil {
+		b.logger.With(zap.Error(err)).Error("Got error publishing progress event")
+		return err
+	}
+	return nil
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, executionID uuid.UUID) (<-chan saga.ProgressEvent, func(), error) {
+	l := b.logger.With("execution_id", executionID.String())
+	pubsub := b.client.Subscribe(ctx, channel(executionID))
+	// waits for the subscription to be confirmed, so no event published after Subscribe returns is lost
+	if _, err := pubsub.Receive(ctx); err != nil {
+		l.With(zap.Error(err)).Error("Got error subscribing to progress events")
+		pubsub.Close()
+		return nil, nil, err
+	}
+
+	ch := make(chan saga.ProgressEvent, subscriberBufferSize)
+	done := make(chan struct{})
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+
+	go func() {
+		defer close(ch)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event saga.ProgressEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					l.With(zap.Error(err)).Error("Got error unmarshalling progress event. Event will be ignored")
+					continue
+				}
+				select {
+				case ch <- event:
+				default:
+				}
+			}
+		}
+	}()
+	return ch, unsubscribe, nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKey grants scopes to the holder of the key whose SHA-256 hex digest is KeySHA256.
+// Only digests are stored, so the keys file does not hold the keys themselves.
+type APIKey struct {
+	Subject   string   `json:"subject"`
+	KeySHA256 string   `json:"key_sha256"`
+	Scopes    []string `json:"scopes"`
+}
+
+// APIKeys authenticates the requests sending one of its keys in the X-API-Key header.
+type APIKeys struct {
+	byDigest map[string]APIKey
+}
+
+var (
+	_ Authenticator = (*APIKeys)(nil)
+)
+
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	byDigest := make(map[string]APIKey, len(keys))
+	for _, key := range keys {
+		digest := strings.ToLower(key.KeySHA256)
+		if key.Subject == "" || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("api key of subject [%s] must have a subject and a hex SHA-256 digest", key.Subject)
+		}
+		if _, ok := byDigest[digest]; ok {
+			return nil, fmt.Errorf("api key of subject [%s] is duplicated", key.Subject)
+		}
+		byDigest[digest] = key
+	}
+	return &APIKeys{byDigest: byDigest}, nil
+}
+
+// LoadAPIKeys reads the JSON array of API keys in path.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parsing api keys file: %w", err)
+	}
+	return NewAPIKeys(keys)
+}
+
+func (a *APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	value := r.Header.Get(APIKeyHeader)
+	if value == "" {
+		return Principal{}, ErrMissingCredentials
+	}
+	digest := sha256.Sum256([]byte(value))
+	key, ok := a.byDigest[hex.EncodeToString(digest[:])]
+	if !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{
+		Subject: key.Subject,
+		Scopes:  key.Scopes,
+		Method:  MethodAPIKey,
+	}, nil
+}
@@ -0,0 +1,119 @@
+// Package auth authenticates the requests of the HTTP APIs with static API keys or JWT bearer tokens
+// and authorizes them by the scopes granted to the authenticated principal.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+var (
+	// ErrMissingCredentials is returned when the request has no credentials of the authenticator kind.
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrNoCredentials is returned when no credentials are configured and authentication was not explicitly disabled.
+	ErrNoCredentials = errors.New("no API credentials are configured and authentication is not disabled")
+	// ErrDisabledWithCredentials is returned when authentication is disabled while credentials are configured.
+	ErrDisabledWithCredentials = errors.New("authentication is disabled but API credentials are configured")
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Scopes  []string
+	// Method is how the principal was authenticated: MethodAPIKey or MethodJWT.
+	Method string
+}
+
+// HasScope returns true if the principal was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator returns the principal of the credentials of a request.
+// It returns ErrMissingCredentials when the request has none of its kind, so the next authenticator can be tried.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+type chain []Authenticator
+
+// Chain returns an authenticator trying each of authenticators in order until one finds credentials in the request.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+func (c chain) Authenticate(r *http.Request) (Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrMissingCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return Principal{}, ErrMissingCredentials
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx holding principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal authenticated for the request of ctx.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Config locates the credentials accepted by an API. Unset files disable their authenticator.
+type Config struct {
+	APIKeysFile string
+	JWKSFile    string
+	JWT         JWTConfig
+	// Disabled serves every request unauthenticated. It must be set explicitly when no credentials are configured.
+	Disabled bool
+}
+
+// NewAuthenticator returns the authenticators enabled by config chained, or nil if authentication is disabled.
+// It returns ErrNoCredentials if no credentials are configured, so an API is never left open by a missing setting.
+func NewAuthenticator(config Config) (Authenticator, error) {
+	configured := config.APIKeysFile != "" || config.JWKSFile != ""
+	if config.Disabled {
+		if configured {
+			return nil, ErrDisabledWithCredentials
+		}
+		return nil, nil
+	}
+	if !configured {
+		return nil, ErrNoCredentials
+	}
+	authenticators := make([]Authenticator, 0, 2)
+	if config.APIKeysFile != "" {
+		keys, err := LoadAPIKeys(config.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, keys)
+	}
+	if config.JWKSFile != "" {
+		keySet, err := LoadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, NewJWT(keySet, config.JWT))
+	}
+	return Chain(authenticators...), nil
+}
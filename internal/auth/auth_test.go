@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAPIKeys(t *testing.T) {
+	keys, err := NewAPIKeys([]APIKey{
+		{Subject: "ci", KeySHA256: digest("secret"), Scopes: []string{"executions:read"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("should authenticate known keys", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(APIKeyHeader, "secret")
+
+		principal, err := keys.Authenticate(r)
+		require.NoError(t, err)
+		assert.Equal(t, Principal{Subject: "ci", Scopes: []string{"executions:read"}, Method: MethodAPIKey}, principal)
+	})
+
+	t.Run("should reject unknown keys and report missing ones", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		_, err := keys.Authenticate(r)
+		assert.True(t, errors.Is(err, ErrMissingCredentials))
+
+		r.Header.Set(APIKeyHeader, "other")
+		_, err = keys.Authenticate(r)
+		assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	})
+
+	t.Run("should reject invalid digests", func(t *testing.T) {
+		_, err := NewAPIKeys([]APIKey{{Subject: "ci", KeySHA256: "secret"}})
+		assert.Error(t, err)
+	})
+}
+
+type signer struct {
+	key *rsa.PrivateKey
+	kid string
+}
+
+func newSigner(t *testing.T) signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return signer{key: key, kid: "test"}
+}
+
+func (s signer) jwks() []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+	return data
+}
+
+func (s signer) sign(t *testing.T, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": s.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWT(t *testing.T) {
+	s := newSigner(t)
+	keySet, err := ParseJWKS(s.jwks())
+	require.NoError(t, err)
+	authenticator := NewJWT(keySet, JWTConfig{Issuer: "https://idp.example.com", Audience: "sagas"})
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":   "partner",
+			"iss":   "https://idp.example.com",
+			"aud":   []string{"sagas", "other"},
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"scope": "executions:read executions:write",
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+	request := func(token string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		return r
+	}
+
+	t.Run("should authenticate valid tokens", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(request(s.sign(t, "RS256", claims(nil))))
+		require.NoError(t, err)
+		assert.Equal(t, Principal{Subject: "partner", Scopes: []string{"executions:read", "executions:write"}, Method: MethodJWT}, principal)
+	})
+
+	t.Run("should read scopes from the scp claim", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(request(s.sign(t, "RS256", claims(map[string]interface{}{
+			"scope": "",
+			"scp":   []string{"orders:read"},
+		}))))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orders:read"}, principal.Scopes)
+	})
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		other := newSigner(t)
+		for name, token := range map[string]string{
+			"expired":        s.sign(t, "RS256", claims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})),
+			"not yet valid":  s.sign(t, "RS256", claims(map[string]interface{}{"nbf": time.Now().Add(time.Minute).Unix()})),
+			"wrong issuer":   s.sign(t, "RS256", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			"wrong audience": s.sign(t, "RS256", claims(map[string]interface{}{"aud": "other"})),
+			"wrong key":      other.sign(t, "RS256", claims(nil)),
+			"wrong alg":      s.sign(t, "HS256", claims(nil)),
+			"malformed":      "not.a-token",
+		} {
+			_, err := authenticator.Authenticate(request(token))
+			assert.True(t, errors.Is(err, ErrInvalidCredentials), fmt.Sprintf("%s: %v", name, err))
+		}
+	})
+
+	t.Run("should report missing bearer tokens", func(t *testing.T) {
+		_, err := authenticator.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, errors.Is(err, ErrMissingCredentials))
+	})
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr error
+	}{
+		{name: "should require credentials unless authentication is disabled", config: Config{}, wantErr: ErrNoCredentials},
+		{name: "should return no authenticator when authentication is disabled", config: Config{Disabled: true}},
+		{name: "should reject credentials while authentication is disabled", config: Config{Disabled: true, APIKeysFile: "keys.json"}, wantErr: ErrDisabledWithCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := NewAuthenticator(tt.config)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, authenticator)
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	keys, err := NewAPIKeys([]APIKey{
+		{Subject: "reader", KeySHA256: digest("reader-key"), Scopes: []string{"executions:read"}},
+	})
+	require.NoError(t, err)
+	onError := func(w http.ResponseWriter, _ *http.Request, status int) {
+		w.WriteHeader(status)
+	}
+	serve := func(guard *Guard, key string, scope string) (*httptest.ResponseRecorder, Principal) {
+		var principal Principal
+		handler := guard.Require(scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ = PrincipalFrom(r.Context())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if key != "" {
+			r.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w, principal
+	}
+	guard := NewGuard(Chain(keys), onError)
+
+	t.Run("should let principals with the scope through", func(t *testing.T) {
+		w, principal := serve(guard, "reader-key", "executions:read")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "reader", principal.Subject)
+	})
+
+	t.Run("should respond 401 without valid credentials", func(t *testing.T) {
+		w, _ := serve(guard, "", "executions:read")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+		w, _ = serve(guard, "wrong", "executions:read")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should respond 403 without the scope", func(t *testing.T) {
+		w, _ := serve(guard, "reader-key", "executions:write")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should let every request through when disabled", func(t *testing.T) {
+		w, _ := serve(NewGuard(nil, onError), "", "executions:write")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const bearerPrefix = "Bearer "
+
+// JWTConfig are the claims a token must have besides a valid signature and expiration.
+// Empty values are not checked.
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew when checking the expiration and not before claims.
+	Leeway time.Duration
+}
+
+// KeySet are the RSA public keys verifying tokens, by key ID.
+type KeySet map[string]*rsa.PublicKey
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ParseJWKS returns the RSA signing keys of a JSON Web Key Set.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("parsing jwks: %w", err)
+	}
+	keySet := make(KeySet, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") || (key.Alg != "" && key.Alg != "RS256") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding modulus of key [%s]: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding exponent of key [%s]: %w", key.Kid, err)
+		}
+		keySet[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keySet) == 0 {
+		return nil, errors.New("jwks has no RS256 signing key")
+	}
+	return keySet, nil
+}
+
+// LoadJWKS reads the JSON Web Key Set in path.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// JWT authenticates the requests with an RS256 signed bearer token.
+// The principal subject is the sub claim and its scopes the scope claim (space separated) or the scp claim.
+type JWT struct {
+	keys   KeySet
+	config JWTConfig
+	now    func() time.Time
+}
+
+var (
+	_ Authenticator = (*JWT)(nil)
+)
+
+func NewJWT(keys KeySet, config JWTConfig) *JWT {
+	return &JWT{
+		keys:   keys,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string     `json:"sub"`
+	Issuer    string     `json:"iss"`
+	Audience  stringList `json:"aud"`
+	ExpiresAt *float64   `json:"exp"`
+	NotBefore *float64   `json:"nbf"`
+	Scope     string     `json:"scope"`
+	Scp       stringList `json:"scp"`
+}
+
+// stringList is a claim that is either a string or an array of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+func (l stringList) contains(value string) bool {
+	for _, s := range l {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *JWT) Authenticate(r *http.Request) (Principal, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return Principal{}, ErrMissingCredentials
+	}
+	claims, err := a.verify(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %s", ErrInvalidCredentials, err)
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	for _, scp := range claims.Scp {
+		scopes = append(scopes, strings.Fields(scp)...)
+	}
+	return Principal{
+		Subject: claims.Subject,
+		Scopes:  scopes,
+		Method:  MethodJWT,
+	}, nil
+}
+
+// verify checks the signature and the registered claims of token and returns its claims.
+func (a *JWT) verify(token string) (jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtClaims{}, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return jwtClaims{}, fmt.Errorf("decoding header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return jwtClaims{}, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	key, ok := a.keys[header.Kid]
+	if !ok {
+		return jwtClaims{}, fmt.Errorf("unknown key %q", header.Kid)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return jwtClaims{}, fmt.Errorf("decoding signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return jwtClaims{}, errors.New("invalid signature")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return jwtClaims{}, fmt.Errorf("decoding claims: %w", err)
+	}
+	now := a.now()
+	switch {
+	case claims.Subject == "":
+		return jwtClaims{}, errors.New("missing subject")
+	case claims.ExpiresAt == nil:
+		return jwtClaims{}, errors.New("missing expiration")
+	case now.After(unixTime(*claims.ExpiresAt).Add(a.config.Leeway)):
+		return jwtClaims{}, errors.New("token expired")
+	case claims.NotBefore != nil && now.Add(a.config.Leeway).Before(unixTime(*claims.NotBefore)):
+		return jwtClaims{}, errors.New("token not valid yet")
+	case a.config.Issuer != "" && claims.Issuer != a.config.Issuer:
+		return jwtClaims{}, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	case a.config.Audience != "" && !claims.Audience.contains(a.config.Audience):
+		return jwtClaims{}, errors.New("unexpected audience")
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, dest interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
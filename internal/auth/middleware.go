@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorHandler writes the response of a request rejected with status 401 or 403.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, status int)
+
+// Guard is the middleware protecting the routes of an API.
+type Guard struct {
+	authenticator Authenticator
+	onError       ErrorHandler
+}
+
+// NewGuard returns a guard authenticating the requests with authenticator.
+// A nil authenticator disables authentication: every request is let through without a principal.
+func NewGuard(authenticator Authenticator, onError ErrorHandler) *Guard {
+	return &Guard{
+		authenticator: authenticator,
+		onError:       onError,
+	}
+}
+
+// Enabled returns true if the guard authenticates requests.
+func (g *Guard) Enabled() bool {
+	return g != nil && g.authenticator != nil
+}
+
+// Require returns a middleware that authenticates the request, responding 401 when it fails,
+// and responds 403 unless the principal was granted every scope.
+// The principal is added to the request context, see PrincipalFrom.
+func (g *Guard) Require(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !g.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			principal, ok := PrincipalFrom(r.Context())
+			if !ok {
+				var err error
+				principal, err = g.authenticator.Authenticate(r)
+				if errors.Is(err, ErrMissingCredentials) || errors.Is(err, ErrInvalidCredentials) {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="sagas"`)
+					g.onError(w, r, http.StatusUnauthorized)
+					return
+				}
+				if err != nil {
+					g.onError(w, r, http.StatusInternalServerError)
+					return
+				}
+			}
+			for _, scope := range scopes {
+				if !principal.HasScope(scope) {
+					g.onError(w, r, http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package diagram renders workflow definitions as Mermaid and Graphviz DOT diagrams,
+// so the documentation of a saga is generated from the code that runs it,
+// and the timeline of an execution as a Mermaid Gantt chart or an HTML page.
+package diagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+)
+
+const (
+	FormatMermaidSequence Format = "mermaid-sequence"
+	FormatMermaidState    Format = "mermaid-state"
+	FormatDOT             Format = "dot"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown diagram format")
+)
+
+// Format is the language a workflow diagram is rendered in.
+type Format string
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// Formats returns every supported format.
+func Formats() []Format {
+	return []Format{FormatMermaidSequence, FormatMermaidState, FormatDOT}
+}
+
+// Render renders workflow in the given format.
+func Render(workflow *saga.Workflow, format Format) (string, error) {
+	switch format {
+	case FormatMermaidSequence:
+		return MermaidSequence(workflow), nil
+	case FormatMermaidState:
+		return MermaidState(workflow)
+	case FormatDOT:
+		return DOT(workflow)
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+const (
+	nodeStart             = "start"
+	nodeCompleted         = "completed"
+	nodeCompensated       = "compensated"
+	nodeNeedsIntervention = "needs_intervention"
+)
+
+// node is a state of the workflow: waiting for the reply of a step command or finished.
+type node struct {
+	ID    string
+	Label string
+	// Description tells the command sent when the state is entered.
+	Description string
+	// Compensation is true for the states waiting for the reply of a compensation.
+	Compensation bool
+	Final        bool
+}
+
+// edge is a transition between two states of the workflow, labeled with the event type causing it.
+type edge struct {
+	From         string
+	To           string
+	Label        string
+	Compensation bool
+}
+
+type graph struct {
+	Nodes []node
+	Edges []edge
+}
+
+func stepNodeID(step *saga.Step) string {
+	return step.Name
+}
+
+func compensationNodeID(step *saga.Step) string {
+	return "compensate_" + step.Name
+}
+
+func nextNodeID(next saga.NextStep, final string) string {
+	if next.Step == nil {
+		return final
+	}
+	if next.ActionType.IsCompensationRequest() {
+		return compensationNodeID(next.Step)
+	}
+	return stepNodeID(next.Step)
+}
+
+// newGraph returns the states and transitions of workflow, following the same rules as Workflow.GetNextStep.
+func newGraph(workflow *saga.Workflow) (graph, error) {
+	ctx := context.Background()
+	g := graph{}
+	head, ok := workflow.Steps.Head()
+	if !ok {
+		g.Edges = append(g.Edges, edge{From: nodeStart, To: nodeCompleted})
+		g.addFinalNodes()
+		return g, nil
+	}
+	g.Edges = append(g.Edges, edge{From: nodeStart, To: stepNodeID(head)})
+
+	for current := head; current != nil; current, _ = current.Next() {
+		g.Nodes = append(g.Nodes, node{
+			ID:          stepNodeID(current),
+			Label:       current.Name,
+			Description: fmt.Sprintf("%s to %s", current.EventTypes.Request, current.ServiceName),
+		})
+
+		success, err := workflow.GetNextStep(ctx, current, current.EventTypes.Success)
+		if err != nil {
+			return graph{}, err
+		}
+		g.Edges = append(g.Edges, edge{From: stepNodeID(current), To: nextNodeID(success, nodeCompleted), Label: current.EventTypes.Success})
+
+		if current.EventTypes.Failure != "" {
+			failure, err := workflow.GetNextStep(ctx, current, current.EventTypes.Failure)
+			if err != nil {
+				return graph{}, err
+			}
+			g.Edges = append(g.Edges, edge{From: stepNodeID(current), To: nextNodeID(failure, nodeCompensated), Label: current.EventTypes.Failure, Compensation: true})
+		}
+	}
+
+	for current := head; current != nil; current, _ = current.Next() {
+		if !current.Compensable {
+			continue
+		}
+		g.Nodes = append(g.Nodes, node{
+			ID:           compensationNodeID(current),
+			Label:        "compensate " + current.Name,
+			Description:  fmt.Sprintf("%s to %s", current.EventTypes.CompesationRequest, current.ServiceName),
+			Compensation: true,
+		})
+		compensated, err := workflow.GetNextStep(ctx, current, current.EventTypes.Compensation)
+		if err != nil {
+			return graph{}, err
+		}
+		g.Edges = append(g.Edges, edge{From: compensationNodeID(current), To: nextNodeID(compensated, nodeCompensated), Label: current.EventTypes.Compensation, Compensation: true})
+		if current.EventTypes.CompensationFailure != "" {
+			g.Edges = append(g.Edges, edge{From: compensationNodeID(current), To: nodeNeedsIntervention, Label: current.EventTypes.CompensationFailure, Compensation: true})
+		}
+	}
+
+	g.addFinalNodes()
+	return g, nil
+}
+
+// addFinalNodes adds the final states reached by any transition.
+func (g *graph) addFinalNodes() {
+	reached := make(map[string]bool)
+	for _, e := range g.Edges {
+		reached[e.To] = true
+	}
+	for _, id := range []string{nodeCompleted, nodeCompensated, nodeNeedsIntervention} {
+		if reached[id] {
+			g.Nodes = append(g.Nodes, node{ID: id, Label: id, Final: true})
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package diagram
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkflow() *saga.Workflow {
+	return &saga.Workflow{
+		Name: "create_order_v1",
+		Steps: saga.NewStepList(
+			&saga.StepData{
+				Name:        "create_order",
+				Compensable: true,
+				StepContract: saga.StepContract{
+					ServiceName: "orders",
+					EventTypes: saga.EventTypes{
+						Request:             "create_order",
+						Success:             "order_created",
+						Failure:             "order_creation_failed",
+						CompesationRequest:  "reject_order",
+						Compensation:        "order_rejected",
+						CompensationFailure: "order_rejection_failed",
+					},
+				},
+			},
+			&saga.StepData{
+				Name: "verify_customer",
+				StepContract: saga.StepContract{
+					ServiceName: "customers",
+					EventTypes: saga.EventTypes{
+						Request: "verify_customer",
+						Success: "customer_verified",
+						Failure: "customer_verification_failed",
+					},
+				},
+			},
+			&saga.StepData{
+				Name: "approve_order",
+				StepContract: saga.StepContract{
+					ServiceName: "orders",
+					EventTypes: saga.EventTypes{
+						Request: "approve_order",
+						Success: "order_approved",
+					},
+				},
+			},
+		),
+	}
+}
+
+func TestMermaidState(t *testing.T) {
+	t.Run("should render forward and compensation transitions", func(t *testing.T) {
+		diagram, err := MermaidState(newWorkflow())
+		require.NoError(t, err)
+
+		assert.Contains(t, diagram, "stateDiagram-v2\n")
+		assert.Contains(t, diagram, "    [*] --> create_order\n")
+		assert.Contains(t, diagram, "    create_order : create_order to orders\n")
+		assert.Contains(t, diagram, "    create_order --> verify_customer : order_created\n")
+		assert.Contains(t, diagram, "    approve_order --> completed : order_approved\n")
+		assert.Contains(t, diagram, "    create_order --> compensated : order_creation_failed\n")
+		assert.Contains(t, diagram, "    verify_customer --> compensate_create_order : customer_verification_failed\n")
+		assert.Contains(t, diagram, "    compensate_create_order --> compensated : order_rejected\n")
+		assert.Contains(t, diagram, "    compensate_create_order --> needs_intervention : order_rejection_failed\n")
+		assert.Contains(t, diagram, "    class compensate_create_order compensation\n")
+	})
+
+	t.Run("should render an empty workflow as completed", func(t *testing.T) {
+		diagram, err := MermaidState(&saga.Workflow{Name: "empty", Steps: saga.NewStepList()})
+		require.NoError(t, err)
+
+		assert.Contains(t, diagram, "    [*] --> completed\n")
+		assert.NotContains(t, diagram, "compensated")
+	})
+}
+
+func TestMermaidSequence(t *testing.T) {
+	t.Run("should render the messages of each step and the compensations", func(t *testing.T) {
+		diagram := MermaidSequence(newWorkflow())
+
+		assert.Equal(t, `sequenceDiagram
+    participant orchestrator
+    participant orders
+    participant customers
+    orchestrator->>orders: create_order
+    alt order_created
+        orders-->>orchestrator: order_created
+    else order_creation_failed
+        orders-->>orchestrator: order_creation_failed
+        Note over orchestrator: compensate the previous steps
+    end
+    orchestrator->>customers: verify_customer
+    alt customer_verified
+        customers-->>orchestrator: customer_verified
+    else customer_verification_failed
+        customers-->>orchestrator: customer_verification_failed
+        Note over orchestrator: compensate the previous steps
+    end
+    orchestrator->>orders: approve_order
+    orders-->>orchestrator: order_approved
+    opt compensation
+        orchestrator->>orders: reject_order
+        alt order_rejected
+            orders-->>orchestrator: order_rejected
+        else order_rejection_failed
+            orders-->>orchestrator: order_rejection_failed
+            Note over orchestrator: needs intervention
+        end
+    end
+`, diagram)
+	})
+}
+
+func TestDOT(t *testing.T) {
+	t.Run("should render compensation transitions dashed", func(t *testing.T) {
+		diagram, err := DOT(newWorkflow())
+		require.NoError(t, err)
+
+		assert.Contains(t, diagram, "digraph \"create_order_v1\" {\n")
+		assert.Contains(t, diagram, "    \"start\" -> \"create_order\";\n")
+		assert.Contains(t, diagram, "    \"create_order\" -> \"verify_customer\" [label=\"order_created\"];\n")
+		assert.Contains(t, diagram, "    \"verify_customer\" -> \"compensate_create_order\" [label=\"customer_verification_failed\", style=dashed, color=red];\n")
+		assert.Contains(t, diagram, "    \"completed\" [shape=doublecircle, label=\"completed\"];\n")
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("should render every supported format", func(t *testing.T) {
+		for _, format := range Formats() {
+			diagram, err := Render(newWorkflow(), format)
+			require.NoError(t, err)
+			assert.NotEmpty(t, diagram)
+		}
+	})
+
+	t.Run("should return error for unknown formats", func(t *testing.T) {
+		_, err := Render(newWorkflow(), Format("plantuml"))
+		assert.True(t, errors.Is(err, ErrUnknownFormat))
+	})
+}
@@ -0,0 +1,49 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+)
+
+// DOT renders workflow as a Graphviz digraph. Compensation states and transitions are drawn dashed in red.
+func DOT(workflow *saga.Workflow) (string, error) {
+	g, err := newGraph(workflow)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", workflow.Name)
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+	fmt.Fprintf(&b, "    %q [shape=circle, label=\"\", width=0.25, style=filled, fillcolor=black];\n", nodeStart)
+	for _, n := range g.Nodes {
+		switch {
+		case n.Final:
+			fmt.Fprintf(&b, "    %q [shape=doublecircle, label=%q];\n", n.ID, n.Label)
+		case n.Compensation:
+			fmt.Fprintf(&b, "    %q [label=%q, style=\"rounded,dashed\", color=red];\n", n.ID, n.Label+"\n"+n.Description)
+		default:
+			fmt.Fprintf(&b, "    %q [label=%q];\n", n.ID, n.Label+"\n"+n.Description)
+		}
+	}
+	for _, e := range g.Edges {
+		attrs := make([]string, 0, 3)
+		if e.Label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.Label))
+		}
+		if e.Compensation {
+			attrs = append(attrs, "style=dashed", "color=red")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "    %q -> %q;\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(&b, "    %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
@@ -0,0 +1,120 @@
+package diagram
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+var timelineTemplate = template.Must(template.New("timeline").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Workflow}} {{.ExecutionID}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px 8px; text-align: left; font-size: 14px; vertical-align: top; }
+.track { position: relative; height: 16px; min-width: 300px; background: #f4f4f4; }
+.bar { position: absolute; top: 0; height: 16px; min-width: 2px; background: #27ae60; }
+.compensation .bar { background: #e67e22; }
+.failed .bar { background: #c0392b; }
+.awaiting_reply .bar { background: #2980b9; }
+.failed td.status { color: #c0392b; font-weight: bold; }
+.error { color: #c0392b; font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Workflow}}</h1>
+<p>Execution <code>{{.ExecutionID}}</code> is <strong>{{.Status}}</strong>. Started at {{.Start}} and lasted {{.Duration}}.</p>
+<table>
+<thead><tr><th>Step</th><th>Phase</th><th>Status</th><th>Event</th><th>Started at</th><th>Duration</th><th>Timeline</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr class="{{.Class}}">
+<td>{{.Step}}</td><td>{{.Phase}}</td><td class="status">{{.Status}}</td><td>{{.EventType}}</td><td>{{.Start}}</td><td>{{.Duration}}</td>
+<td><div class="track"><div class="bar" style="left: {{.Offset}}%; width: {{.Width}}%"></div></div>{{if .Error}}<div class="error">{{.Error}}</div>{{end}}</td>
+</tr>
+{{- else}}
+<tr><td colspan="7">No step was requested yet.</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+type timelineRow struct {
+	Step      string
+	Phase     string
+	Status    string
+	EventType string
+	Error     string
+	Start     string
+	Duration  time.Duration
+	Class     string
+	// Offset and Width position the span bar as percentages of the execution duration.
+	Offset string
+	Width  string
+}
+
+// HTML renders timeline as a standalone HTML page with a row per span.
+func HTML(timeline Timeline) (string, error) {
+	total := timeline.End.Sub(timeline.Start)
+	percent := func(d time.Duration) string {
+		if total <= 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.2f", float64(d)/float64(total)*100)
+	}
+
+	rows := make([]timelineRow, 0, len(timeline.Spans))
+	for _, span := range timeline.Spans {
+		row := timelineRow{
+			Step:      span.Step,
+			Phase:     "step",
+			Status:    span.Status,
+			EventType: span.EventType,
+			Error:     span.Error,
+			Start:     span.Start.Format(time.RFC3339Nano),
+			Duration:  span.Duration().Round(time.Millisecond),
+			Offset:    percent(span.Start.Sub(timeline.Start)),
+			Width:     percent(span.Duration()),
+		}
+		classes := make([]string, 0, 2)
+		if span.Compensation {
+			row.Phase = "compensation"
+			classes = append(classes, "compensation")
+		}
+		switch {
+		case span.Failed():
+			classes = append(classes, "failed")
+		case span.Status == SpanStatusAwaitingReply:
+			classes = append(classes, SpanStatusAwaitingReply)
+		}
+		row.Class = strings.Join(classes, " ")
+		rows = append(rows, row)
+	}
+
+	var b strings.Builder
+	err := timelineTemplate.Execute(&b, struct {
+		Workflow    string
+		ExecutionID string
+		Status      string
+		Start       string
+		Duration    time.Duration
+		Rows        []timelineRow
+	}{
+		Workflow:    timeline.Workflow,
+		ExecutionID: timeline.ExecutionID.String(),
+		Status:      timeline.Status.String(),
+		Start:       timeline.Start.Format(time.RFC3339Nano),
+		Duration:    total.Round(time.Millisecond),
+		Rows:        rows,
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,156 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+)
+
+const orchestratorParticipant = "orchestrator"
+
+// MermaidState renders workflow as a Mermaid state diagram with a state per step command and compensation.
+func MermaidState(workflow *saga.Workflow) (string, error) {
+	g, err := newGraph(workflow)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	b.WriteString("    direction LR\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "    state %q as %s\n", n.Label, n.ID)
+		if n.Description != "" {
+			fmt.Fprintf(&b, "    %s : %s\n", n.ID, n.Description)
+		}
+	}
+	for _, e := range g.Edges {
+		from := e.From
+		if from == nodeStart {
+			from = "[*]"
+		}
+		if e.Label == "" {
+			fmt.Fprintf(&b, "    %s --> %s\n", from, e.To)
+			continue
+		}
+		fmt.Fprintf(&b, "    %s --> %s : %s\n", from, e.To, e.Label)
+	}
+	var compensations []string
+	for _, n := range g.Nodes {
+		if n.Final {
+			fmt.Fprintf(&b, "    %s --> [*]\n", n.ID)
+		}
+		if n.Compensation {
+			compensations = append(compensations, n.ID)
+		}
+	}
+	if len(compensations) > 0 {
+		b.WriteString("    classDef compensation fill:#fde2e2,stroke:#c0392b\n")
+		fmt.Fprintf(&b, "    class %s compensation\n", strings.Join(compensations, ","))
+	}
+	return b.String(), nil
+}
+
+// MermaidSequence renders workflow as a Mermaid sequence diagram of the messages exchanged between
+// the orchestrator and the services, followed by the compensations in the order they run.
+func MermaidSequence(workflow *saga.Workflow) string {
+	var (
+		b     strings.Builder
+		steps = workflow.Steps.ToList()
+		seen  = map[string]bool{orchestratorParticipant: true}
+	)
+	b.WriteString("sequenceDiagram\n")
+	fmt.Fprintf(&b, "    participant %s\n", orchestratorParticipant)
+	for _, step := range steps {
+		if seen[step.ServiceName] {
+			continue
+		}
+		seen[step.ServiceName] = true
+		fmt.Fprintf(&b, "    participant %s\n", step.ServiceName)
+	}
+
+	for _, step := range steps {
+		fmt.Fprintf(&b, "    %s->>%s: %s\n", orchestratorParticipant, step.ServiceName, step.EventTypes.Request)
+		if step.EventTypes.Failure == "" {
+			fmt.Fprintf(&b, "    %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.Success)
+			continue
+		}
+		fmt.Fprintf(&b, "    alt %s\n", step.EventTypes.Success)
+		fmt.Fprintf(&b, "        %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.Success)
+		fmt.Fprintf(&b, "    else %s\n", step.EventTypes.Failure)
+		fmt.Fprintf(&b, "        %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.Failure)
+		fmt.Fprintf(&b, "        Note over %s: compensate the previous steps\n", orchestratorParticipant)
+		b.WriteString("    end\n")
+	}
+
+	var compensable []saga.Step
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].Compensable {
+			compensable = append(compensable, steps[i])
+		}
+	}
+	if len(compensable) == 0 {
+		return b.String()
+	}
+	b.WriteString("    opt compensation\n")
+	for _, step := range compensable {
+		fmt.Fprintf(&b, "        %s->>%s: %s\n", orchestratorParticipant, step.ServiceName, step.EventTypes.CompesationRequest)
+		if step.EventTypes.CompensationFailure == "" {
+			fmt.Fprintf(&b, "        %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.Compensation)
+			continue
+		}
+		fmt.Fprintf(&b, "        alt %s\n", step.EventTypes.Compensation)
+		fmt.Fprintf(&b, "            %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.Compensation)
+		fmt.Fprintf(&b, "        else %s\n", step.EventTypes.CompensationFailure)
+		fmt.Fprintf(&b, "            %s-->>%s: %s\n", step.ServiceName, orchestratorParticipant, step.EventTypes.CompensationFailure)
+		fmt.Fprintf(&b, "            Note over %s: needs intervention\n", orchestratorParticipant)
+		b.WriteString("        end\n")
+	}
+	b.WriteString("    end\n")
+	return b.String()
+}
+
+// MermaidGantt renders timeline as a Mermaid Gantt chart with a section for the commands and another for the compensations.
+// Failed and cancelled spans are marked critical and the spans awaiting a reply active.
+func MermaidGantt(timeline Timeline) string {
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	fmt.Fprintf(&b, "    title %s %s (%s)\n", timeline.Workflow, timeline.ExecutionID, timeline.Status)
+	b.WriteString("    dateFormat x\n")
+	b.WriteString("    axisFormat %H:%M:%S\n")
+	for _, section := range []struct {
+		name         string
+		compensation bool
+	}{{"Steps", false}, {"Compensations", true}} {
+		written := false
+		for i, span := range timeline.Spans {
+			if span.Compensation != section.compensation {
+				continue
+			}
+			if !written {
+				fmt.Fprintf(&b, "    section %s\n", section.name)
+				written = true
+			}
+			end := span.End
+			if !end.After(span.Start) {
+				// Mermaid does not draw empty tasks.
+				end = span.Start.Add(time.Millisecond)
+			}
+			fmt.Fprintf(&b, "    %s %s in %s :%sspan%d, %d, %d\n",
+				span.Step, span.Status, span.Duration().Round(time.Millisecond), ganttTag(span), i, span.Start.UnixMilli(), end.UnixMilli())
+		}
+	}
+	return b.String()
+}
+
+func ganttTag(span Span) string {
+	switch {
+	case span.Failed():
+		return "crit, "
+	case span.Status == SpanStatusAwaitingReply:
+		return "active, "
+	}
+	return "done, "
+}
@@ -0,0 +1,145 @@
+package diagram
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/google/uuid"
+)
+
+const (
+	TimelineFormatMermaidGantt Format = "mermaid-gantt"
+	TimelineFormatHTML         Format = "html"
+)
+
+// SpanStatusAwaitingReply is the status of a span whose command was not replied yet.
+const SpanStatusAwaitingReply = "awaiting_reply"
+
+// TimelineFormats returns every supported format of execution timelines.
+func TimelineFormats() []Format {
+	return []Format{TimelineFormatHTML, TimelineFormatMermaidGantt}
+}
+
+// Span is the time between a command sent to a step and its reply.
+type Span struct {
+	Step string
+	// Compensation is true for the spans of compensation commands.
+	Compensation bool
+	// Status is the type of the transition closing the span or SpanStatusAwaitingReply.
+	Status string
+	// EventType is the type of the reply or, while awaiting it, of the command.
+	EventType string
+	Error     string
+	Start     time.Time
+	End       time.Time
+}
+
+// Duration returns how long the step took to reply.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Failed returns true if the span ended with a failure reply or a cancellation.
+func (s Span) Failed() bool {
+	switch saga.TransitionType(s.Status) {
+	case saga.TransitionTypeFailed, saga.TransitionTypeCompensationFailed, saga.TransitionTypeCancelled:
+		return true
+	}
+	return false
+}
+
+// Timeline is the history of an execution as the spans of its step commands, in the order they were sent.
+type Timeline struct {
+	ExecutionID uuid.UUID
+	Workflow    string
+	Status      saga.ExecutionStatus
+	Start       time.Time
+	End         time.Time
+	Spans       []Span
+}
+
+type spanKey struct {
+	step         string
+	compensation bool
+}
+
+// RenderTimeline renders timeline in the given format.
+func RenderTimeline(timeline Timeline, format Format) (string, error) {
+	switch format {
+	case TimelineFormatMermaidGantt:
+		return MermaidGantt(timeline), nil
+	case TimelineFormatHTML:
+		return HTML(timeline)
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// NewTimeline builds the timeline of execution from its transitions.
+// Spans still awaiting a reply end at the last update of a finished execution, or at now.
+func NewTimeline(execution *saga.Execution, now time.Time) Timeline {
+	end := now.UTC()
+	if execution.Status.IsFinished() {
+		end = execution.UpdatedAt
+	}
+	timeline := Timeline{
+		ExecutionID: execution.ID,
+		Workflow:    execution.Workflow.Name,
+		Status:      execution.Status,
+		Start:       execution.CreatedAt,
+		End:         end,
+		Spans:       make([]Span, 0, len(execution.Transitions)),
+	}
+
+	open := make(map[spanKey]int)
+	closeSpan := func(key spanKey, transition saga.Transition) {
+		i, ok := open[key]
+		if !ok {
+			timeline.Spans = append(timeline.Spans, Span{Step: key.step, Compensation: key.compensation, Start: transition.At})
+			i = len(timeline.Spans) - 1
+		}
+		delete(open, key)
+		span := &timeline.Spans[i]
+		span.Status = transition.Type.String()
+		if transition.EventType != "" {
+			span.EventType = transition.EventType
+		}
+		span.Error = transition.Error
+		span.End = transition.At
+	}
+
+	for _, transition := range execution.Transitions {
+		switch transition.Type {
+		case saga.TransitionTypeRequested, saga.TransitionTypeCompensationRequested:
+			key := spanKey{step: transition.Step, compensation: transition.Type == saga.TransitionTypeCompensationRequested}
+			timeline.Spans = append(timeline.Spans, Span{
+				Step:         key.step,
+				Compensation: key.compensation,
+				Status:       SpanStatusAwaitingReply,
+				EventType:    transition.EventType,
+				Start:        transition.At,
+			})
+			open[key] = len(timeline.Spans) - 1
+		case saga.TransitionTypeSucceeded, saga.TransitionTypeFailed, saga.TransitionTypeCancelled:
+			closeSpan(spanKey{step: transition.Step}, transition)
+		case saga.TransitionTypeCompensated, saga.TransitionTypeCompensationFailed:
+			closeSpan(spanKey{step: transition.Step, compensation: true}, transition)
+		}
+	}
+	for _, i := range open {
+		if timeline.Spans[i].Start.Before(end) {
+			timeline.Spans[i].End = end
+		} else {
+			timeline.Spans[i].End = timeline.Spans[i].Start
+		}
+	}
+	if len(timeline.Spans) > 0 && timeline.Spans[0].Start.Before(timeline.Start) {
+		timeline.Start = timeline.Spans[0].Start
+	}
+	for _, span := range timeline.Spans {
+		if span.End.After(timeline.End) {
+			timeline.End = span.End
+		}
+	}
+	return timeline
+}
@@ -0,0 +1,93 @@
+package diagram
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompensatedExecution(start time.Time) *saga.Execution {
+	execution := saga.NewExecution(newWorkflow())
+	execution.CreatedAt = start
+	execution.Status = saga.ExecutionStatusCompensated
+	execution.UpdatedAt = start.Add(500 * time.Millisecond)
+	execution.Transitions = []saga.Transition{
+		{Step: "create_order", Type: saga.TransitionTypeRequested, EventType: "create_order", At: start},
+		{Step: "create_order", Type: saga.TransitionTypeSucceeded, EventType: "order_created", At: start.Add(100 * time.Millisecond)},
+		{Step: "verify_customer", Type: saga.TransitionTypeRequested, EventType: "verify_customer", At: start.Add(100 * time.Millisecond)},
+		{Step: "verify_customer", Type: saga.TransitionTypeFailed, EventType: "customer_verification_failed", Error: "customer <blocked>", At: start.Add(300 * time.Millisecond)},
+		{Step: "create_order", Type: saga.TransitionTypeCompensationRequested, EventType: "reject_order", At: start.Add(300 * time.Millisecond)},
+		{Step: "create_order", Type: saga.TransitionTypeCompensated, EventType: "order_rejected", At: start.Add(500 * time.Millisecond)},
+	}
+	return execution
+}
+
+func TestNewTimeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should pair the commands with their replies", func(t *testing.T) {
+		timeline := NewTimeline(newCompensatedExecution(start), start.Add(time.Hour))
+
+		require.Len(t, timeline.Spans, 3)
+		assert.Equal(t, Span{
+			Step:      "verify_customer",
+			Status:    "failed",
+			EventType: "customer_verification_failed",
+			Error:     "customer <blocked>",
+			Start:     start.Add(100 * time.Millisecond),
+			End:       start.Add(300 * time.Millisecond),
+		}, timeline.Spans[1])
+		assert.True(t, timeline.Spans[2].Compensation)
+		assert.Equal(t, "compensated", timeline.Spans[2].Status)
+		assert.Equal(t, 200*time.Millisecond, timeline.Spans[2].Duration())
+		assert.Equal(t, start.Add(500*time.Millisecond), timeline.End)
+	})
+
+	t.Run("should end the spans awaiting a reply at now for running executions", func(t *testing.T) {
+		execution := saga.NewExecution(newWorkflow())
+		execution.CreatedAt = start
+		execution.Transitions = []saga.Transition{
+			{Step: "create_order", Type: saga.TransitionTypeRequested, EventType: "create_order", At: start},
+		}
+
+		timeline := NewTimeline(execution, start.Add(time.Second))
+
+		require.Len(t, timeline.Spans, 1)
+		assert.Equal(t, SpanStatusAwaitingReply, timeline.Spans[0].Status)
+		assert.Equal(t, time.Second, timeline.Spans[0].Duration())
+	})
+}
+
+func TestRenderTimeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	timeline := NewTimeline(newCompensatedExecution(start), start)
+
+	t.Run("should render a Mermaid Gantt chart", func(t *testing.T) {
+		gantt, err := RenderTimeline(timeline, TimelineFormatMermaidGantt)
+		require.NoError(t, err)
+
+		assert.Contains(t, gantt, "gantt\n    title create_order_v1 ")
+		assert.Contains(t, gantt, "    section Steps\n    create_order succeeded in 100ms :done, span0, 1714557600000, 1714557600100\n")
+		assert.Contains(t, gantt, "    verify_customer failed in 200ms :crit, span1, 1714557600100, 1714557600300\n")
+		assert.Contains(t, gantt, "    section Compensations\n    create_order compensated in 200ms :done, span2, 1714557600300, 1714557600500\n")
+	})
+
+	t.Run("should render an HTML page escaping the errors", func(t *testing.T) {
+		page, err := RenderTimeline(timeline, TimelineFormatHTML)
+		require.NoError(t, err)
+
+		assert.Contains(t, page, "<h1>create_order_v1</h1>")
+		assert.Contains(t, page, `<tr class="failed">`)
+		assert.Contains(t, page, `style="left: 20.00%; width: 40.00%"`)
+		assert.Contains(t, page, "customer &lt;blocked&gt;")
+	})
+
+	t.Run("should return error for unknown formats", func(t *testing.T) {
+		_, err := RenderTimeline(timeline, FormatDOT)
+		assert.True(t, errors.Is(err, ErrUnknownFormat))
+	})
+}
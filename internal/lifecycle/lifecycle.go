@@ -0,0 +1,193 @@
+// Package lifecycle runs the components of a service until it is asked to stop and shuts them down in order.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDrainTimeout = errors.New("timed out draining in-flight work")
+)
+
+// RunFunc runs a component until ctx is cancelled. It must finish the work in flight before returning.
+type RunFunc func(ctx context.Context) error
+
+// StopFunc releases a resource. ctx expires at the shutdown deadline.
+type StopFunc func(ctx context.Context) error
+
+type component struct {
+	name string
+	run  RunFunc
+	// stop is called before the context of the components is cancelled, if set.
+	stop StopFunc
+}
+
+type closer struct {
+	name  string
+	close StopFunc
+}
+
+// Lifecycle starts the HTTP servers, consumers and other components of a service and, when the service stops,
+// drains their in-flight work within the shutdown timeout and closes the resources they used.
+type Lifecycle struct {
+	logger     *zap.SugaredLogger
+	timeout    time.Duration
+	components []component
+	closers    []closer
+}
+
+// New returns a lifecycle giving the components timeout to drain their in-flight work and the resources to close.
+func New(logger *zap.SugaredLogger, timeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+// Go adds a component run in its own goroutine, such as a consumer.
+func (l *Lifecycle) Go(name string, run RunFunc) *Lifecycle {
+	l.components = append(l.components, component{name: name, run: run})
+	return l
+}
+
+// Server adds an HTTP server. On shutdown it stops accepting connections and waits for the in-flight requests
+// before the context of the other components is cancelled, so requests waiting on them still complete.
+// Long-lived requests should end once Stopping is closed.
+func (l *Lifecycle) Server(name string, server *http.Server) *Lifecycle {
+	var (
+		stopping = make(chan struct{})
+		once     sync.Once
+		base     = server.BaseContext
+	)
+	server.BaseContext = func(listener net.Listener) context.Context {
+		ctx := context.Background()
+		if base != nil {
+			ctx = base(listener)
+		}
+		return context.WithValue(ctx, stoppingKey{}, stopping)
+	}
+	server.RegisterOnShutdown(func() {
+		once.Do(func() { close(stopping) })
+	})
+	l.components = append(l.components, component{
+		name: name,
+		run: func(_ context.Context) error {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		stop: func(ctx context.Context) error {
+			if err := server.Shutdown(ctx); err != nil {
+				_ = server.Close()
+				return err
+			}
+			return nil
+		},
+	})
+	return l
+}
+
+type stoppingKey struct{}
+
+// Stopping returns a channel closed when the server handling the request of ctx starts shutting down,
+// so long-lived requests such as event streams can end and let the server drain.
+// The channel is nil, and never closed, for requests not served by a Server of a lifecycle.
+func Stopping(ctx context.Context) <-chan struct{} {
+	stopping, _ := ctx.Value(stoppingKey{}).(chan struct{})
+	return stopping
+}
+
+// OnStop adds a resource closed after the components stopped. Resources are closed in the reverse order they were added,
+// so a resource should be added right after the resources it depends on.
+func (l *Lifecycle) OnStop(name string, closeFn StopFunc) *Lifecycle {
+	l.closers = append(l.closers, closer{name: name, close: closeFn})
+	return l
+}
+
+// Run starts the components and blocks until ctx is done or any of them returns, then shuts the service down.
+// It returns the errors of the components and of the shutdown.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	lggr := l.logger
+	// the components are cancelled after the servers drained, not as soon as ctx is done
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []error
+		returned = make(chan struct{}, len(l.components))
+	)
+	for _, c := range l.components {
+		wg.Add(1)
+		go func(c component) {
+			defer wg.Done()
+			lggr.Infof("Starting %s", c.name)
+			if err := c.run(runCtx); err != nil {
+				lggr.With(zap.Error(err)).Errorf("Got error in %s", c.name)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+				mu.Unlock()
+			}
+			lggr.Infof("Stopped %s", c.name)
+			returned <- struct{}{}
+		}(c)
+	}
+
+	lggr.Info("Running. Waiting for signal to stop...")
+	select {
+	case <-ctx.Done():
+		lggr.Info("Got signal, shutting down")
+	case <-returned:
+		lggr.Info("A component stopped, shutting down")
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.WithoutCancel(ctx), l.timeout)
+	defer cancelShutdown()
+	var shutdownErrs []error
+	for _, c := range l.components {
+		if c.stop == nil {
+			continue
+		}
+		if err := c.stop(shutdownCtx); err != nil {
+			lggr.With(zap.Error(err)).Errorf("Got error stopping %s", c.name)
+			shutdownErrs = append(shutdownErrs, fmt.Errorf("stopping %s: %w", c.name, err))
+		}
+	}
+	cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		lggr.Info("Drained in-flight work")
+	case <-shutdownCtx.Done():
+		lggr.Errorf("Components did not stop within %s", l.timeout)
+		shutdownErrs = append(shutdownErrs, ErrDrainTimeout)
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		c := l.closers[i]
+		lggr.Infof("Closing %s", c.name)
+		if err := c.close(shutdownCtx); err != nil {
+			lggr.With(zap.Error(err)).Errorf("Got error closing %s", c.name)
+			shutdownErrs = append(shutdownErrs, fmt.Errorf("closing %s: %w", c.name, err))
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return errors.Join(append(errs, shutdownErrs...)...)
+}
@@ -0,0 +1,215 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recorder records the order in which the components stopped and the resources were closed.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) closer(name string) StopFunc {
+	return func(context.Context) error {
+		r.add("close " + name)
+		return nil
+	}
+}
+
+func TestLifecycle_Run(t *testing.T) {
+	lggr := zap.NewNop().Sugar()
+
+	t.Run("should drain the components before closing the resources in reverse order", func(t *testing.T) {
+		var (
+			rec         = &recorder{}
+			ctx, cancel = context.WithCancel(context.Background())
+			started     = make(chan struct{})
+		)
+		lc := New(lggr, time.Second).
+			Go("consumer", func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond) // in-flight message
+				rec.add("stop consumer")
+				return nil
+			}).
+			OnStop("database", rec.closer("database")).
+			OnStop("publisher", rec.closer("publisher"))
+
+		go func() {
+			<-started
+			cancel()
+		}()
+		err := lc.Run(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stop consumer", "close publisher", "close database"}, rec.events)
+	})
+
+	t.Run("should stop the service and return the error of a component", func(t *testing.T) {
+		var (
+			rec    = &recorder{}
+			failed = errors.New("failed")
+		)
+		lc := New(lggr, time.Second).
+			Go("failing", func(context.Context) error {
+				return failed
+			}).
+			Go("consumer", func(ctx context.Context) error {
+				<-ctx.Done()
+				rec.add("stop consumer")
+				return nil
+			}).
+			OnStop("database", rec.closer("database"))
+
+		err := lc.Run(context.Background())
+
+		assert.ErrorIs(t, err, failed)
+		assert.Equal(t, []string{"stop consumer", "close database"}, rec.events)
+	})
+
+	t.Run("should close the resources when the components do not drain in time", func(t *testing.T) {
+		var (
+			rec         = &recorder{}
+			ctx, cancel = context.WithCancel(context.Background())
+			release     = make(chan struct{})
+		)
+		defer close(release)
+		cancel()
+		lc := New(lggr, 10*time.Millisecond).
+			Go("stuck", func(context.Context) error {
+				<-release
+				return nil
+			}).
+			OnStop("database", rec.closer("database"))
+
+		err := lc.Run(ctx)
+
+		assert.ErrorIs(t, err, ErrDrainTimeout)
+		assert.Equal(t, []string{"close database"}, rec.events)
+	})
+
+	t.Run("should let in-flight requests complete before cancelling the other components", func(t *testing.T) {
+		addr := freeAddr(t)
+		var (
+			rec         = &recorder{}
+			ctx, cancel = context.WithCancel(context.Background())
+			received    = make(chan struct{})
+			consumerCtx = make(chan context.Context, 1)
+		)
+		server := &http.Server{
+			Addr: addr,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				close(received)
+				time.Sleep(20 * time.Millisecond)
+				// the request waits on work done by the consumer, which must still be running
+				assert.NoError(t, (<-consumerCtx).Err())
+				rec.add("respond")
+				w.WriteHeader(http.StatusNoContent)
+			}),
+		}
+		lc := New(lggr, time.Second).
+			Server("api", server).
+			Go("consumer", func(ctx context.Context) error {
+				consumerCtx <- ctx
+				<-ctx.Done()
+				rec.add("stop consumer")
+				return nil
+			})
+
+		done := make(chan error, 1)
+		go func() {
+			done <- lc.Run(ctx)
+		}()
+		waitListening(t, addr)
+
+		responded := make(chan int, 1)
+		go func() {
+			res, err := http.Get("http://" + addr)
+			if !assert.NoError(t, err) {
+				responded <- 0
+				return
+			}
+			_ = res.Body.Close()
+			responded <- res.StatusCode
+		}()
+		<-received
+		cancel()
+
+		assert.Equal(t, http.StatusNoContent, <-responded)
+		require.NoError(t, <-done)
+		assert.Equal(t, []string{"respond", "stop consumer"}, rec.events)
+	})
+
+	t.Run("should end long-lived requests once the server is stopping", func(t *testing.T) {
+		addr := freeAddr(t)
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			received    = make(chan struct{})
+		)
+		defer cancel()
+		server := &http.Server{
+			Addr: addr,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				close(received)
+				<-Stopping(r.Context())
+			}),
+		}
+		lc := New(lggr, time.Second).Server("api", server)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- lc.Run(ctx)
+		}()
+		waitListening(t, addr)
+		go func() {
+			if res, err := http.Get("http://" + addr); err == nil {
+				_ = res.Body.Close()
+			}
+		}()
+		<-received
+		cancel()
+
+		require.NoError(t, <-done)
+	})
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
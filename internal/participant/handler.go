@@ -0,0 +1,102 @@
+package participant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+)
+
+var (
+	ErrNilHandler     = errors.New("route handler is nil")
+	ErrNoRoutes       = errors.New("participant has no routes")
+	ErrRouteMismatch  = errors.New("route does not match participant")
+	ErrDuplicateRoute = errors.New("command is routed more than once")
+	// ErrInvalidRetryDelay is returned for a retry delay that is not positive or outlasts the consumer max poll interval.
+	ErrInvalidRetryDelay = errors.New("invalid retry delay")
+)
+
+// Handler processes a command received by a saga participant.
+type Handler interface {
+	// Handle executes the command and returns the data of the success reply.
+	// A returned error is turned into the failure reply of the command route.
+	Handle(ctx context.Context, event *events.Event) (map[string]interface{}, error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc func(ctx context.Context, event *events.Event) (map[string]interface{}, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, event *events.Event) (map[string]interface{}, error) {
+	return f(ctx, event)
+}
+
+// Route binds a command declared by a step contract to the handler executing it.
+// The command and reply event types are taken from the contract according to the route action.
+type Route struct {
+	Contract saga.StepContract
+	Action   saga.ActionType
+	Handler  Handler
+}
+
+// RequestRoute returns the route handling the request command of contract.
+// It replies with the contract success event or, on error, with the contract failure event.
+func RequestRoute(contract saga.StepContract, handler Handler) Route {
+	return Route{
+		Contract: contract,
+		Action:   saga.REQUEST_ACTION_TYPE,
+		Handler:  handler,
+	}
+}
+
+// CompensationRoute returns the route handling the compensation request command of contract.
+// It replies with the contract compensation event or, on error, with the contract compensation failure event.
+func CompensationRoute(contract saga.StepContract, handler Handler) Route {
+	return Route{
+		Contract: contract,
+		Action:   saga.COMPESATION_REQUEST_ACTION_TYPE,
+		Handler:  handler,
+	}
+}
+
+// Command returns the event type of the command handled by the route.
+func (r Route) Command() string {
+	if r.Action.IsCompensationRequest() {
+		return r.Contract.EventTypes.CompesationRequest
+	}
+	return r.Contract.EventTypes.Request
+}
+
+// SuccessEvent returns the reply event type published when the handler succeeds.
+func (r Route) SuccessEvent() string {
+	if r.Action.IsCompensationRequest() {
+		return r.Contract.EventTypes.Compensation
+	}
+	return r.Contract.EventTypes.Success
+}
+
+// FailureEvent returns the reply event type published when the handler fails.
+// It is empty when the contract declares no failure reply for the route action.
+func (r Route) FailureEvent() string {
+	if r.Action.IsCompensationRequest() {
+		return r.Contract.EventTypes.CompensationFailure
+	}
+	return r.Contract.EventTypes.Failure
+}
+
+func (r Route) validate() error {
+	if r.Handler == nil {
+		return ErrNilHandler
+	}
+	if err := r.Contract.Validate(); err != nil {
+		return err
+	}
+	if !r.Action.IsRequest() && !r.Action.IsCompensationRequest() {
+		return fmt.Errorf("%w: %s", saga.ErrUnknownActionType, r.Action.String())
+	}
+	if r.Action.IsCompensationRequest() && !r.Contract.IsCompensable() {
+		return fmt.Errorf("%w: [%s] declares no compensation", saga.ErrInvalidContract, r.Contract.EventTypes.Request)
+	}
+	return nil
+}
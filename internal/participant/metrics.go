@@ -0,0 +1,89 @@
+package participant
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Counters holds the outcome counts of the messages of a single event type.
+type Counters struct {
+	Received   int64 `json:"received"`
+	Succeeded  int64 `json:"succeeded"`
+	Failed     int64 `json:"failed"`
+	Duplicated int64 `json:"duplicated"`
+	Ignored    int64 `json:"ignored"`
+	Retried    int64 `json:"retried"`
+	DeadLetter int64 `json:"dead_letter"`
+}
+
+// Metrics counts the messages handled by a Runtime grouped by event type.
+type Metrics struct {
+	mu       sync.Mutex
+	counters map[string]*Counters
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		counters: make(map[string]*Counters),
+	}
+}
+
+func (m *Metrics) inc(eventType string, fn func(c *Counters)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.counters[eventType]
+	if !ok {
+		c = &Counters{}
+		m.counters[eventType] = c
+	}
+	fn(c)
+}
+
+func (m *Metrics) received(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Received++ })
+}
+
+func (m *Metrics) succeeded(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Succeeded++ })
+}
+
+func (m *Metrics) failed(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Failed++ })
+}
+
+func (m *Metrics) duplicated(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Duplicated++ })
+}
+
+func (m *Metrics) ignored(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Ignored++ })
+}
+
+func (m *Metrics) retried(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.Retried++ })
+}
+
+func (m *Metrics) deadLettered(eventType string) {
+	m.inc(eventType, func(c *Counters) { c.DeadLetter++ })
+}
+
+// Snapshot returns a copy of the current counters by event type.
+func (m *Metrics) Snapshot() map[string]Counters {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]Counters, len(m.counters))
+	for eventType, c := range m.counters {
+		snapshot[eventType] = *c
+	}
+	return snapshot
+}
+
+// Handler serves the snapshot of the counters as JSON, by event type.
+func (m *Metrics) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(m.Snapshot())
+	}
+}
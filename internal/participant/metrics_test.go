@@ -0,0 +1,33 @@
+package participant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Handler(t *testing.T) {
+	t.Run("should serve the counters by event type", func(t *testing.T) {
+		metrics := NewMetrics()
+		metrics.received("create_order")
+		metrics.succeeded("create_order")
+		metrics.received("approve_order")
+		metrics.retried("approve_order")
+		res := httptest.NewRecorder()
+
+		metrics.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/v1/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+		var got map[string]Counters
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+		assert.Equal(t, map[string]Counters{
+			"create_order":  {Received: 1, Succeeded: 1},
+			"approve_order": {Received: 1, Retried: 1},
+		}, got)
+	})
+}
@@ -0,0 +1,216 @@
+package participant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy defines the delays between the attempts of a command whose handler returned a retryable error.
+// The n-th retry is published to the n-th retry topic of the request topic and handled after Delays[n-1].
+// Once every retry is exhausted, the command is published to the dead letter topic of the request topic.
+type RetryPolicy struct {
+	Delays []time.Duration
+}
+
+// Validate returns an error if any delay is not shorter than maxPollInterval, the longest time a consumer can go without polling.
+// Retry consumers hold each message until its delay elapses, so a longer delay would evict them from their group
+// and the message would be redelivered in a loop.
+func (p RetryPolicy) Validate(maxPollInterval time.Duration) error {
+	for i, delay := range p.Delays {
+		if delay <= 0 {
+			return fmt.Errorf("%w: delay of retry %d is %s", ErrInvalidRetryDelay, i+1, delay)
+		}
+		if delay >= maxPollInterval {
+			return fmt.Errorf("%w: delay of retry %d is %s, but the consumer max poll interval is %s", ErrInvalidRetryDelay, i+1, delay, maxPollInterval)
+		}
+	}
+	return nil
+}
+
+// MaxRetries returns the number of retries of a command after its first attempt.
+func (p RetryPolicy) MaxRetries() int {
+	return len(p.Delays)
+}
+
+// RetryTopic returns the topic of the given retry attempt of the commands consumed from topic.
+func RetryTopic(topic string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, attempt)
+}
+
+// DeadLetterTopic returns the topic of the commands consumed from topic that exhausted their retries.
+func DeadLetterTopic(topic string) string {
+	return fmt.Sprintf("%s.dlq", topic)
+}
+
+type retryableError struct {
+	err error
+}
+
+// Retryable marks err as a transient failure.
+// When the runtime has a retry policy, the command is retried instead of being replied with a failure.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+// IsRetryable reports whether any error in err's chain was marked with Retryable.
+func IsRetryable(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// RetryMessage is the message published to the retry and dead letter topics.
+// It wraps the original command with the attempt that must handle it next.
+type RetryMessage struct {
+	Attempt   int          `json:"attempt"`
+	NotBefore time.Time    `json:"not_before"`
+	Topic     string       `json:"topic"`
+	Error     string       `json:"error"`
+	Event     events.Event `json:"event"`
+}
+
+// replayNamespace derives the IDs of the replayed commands from the IDs of the dead lettered ones.
+var replayNamespace = uuid.MustParse("6f1c3f4e-5a0b-4c57-9a43-3a2d2c1b7e10")
+
+// Replay returns the command to publish again to the request topic of a dead lettered message.
+// The runtime already processed the ID of the dead lettered command, so the replayed command gets an ID derived from it:
+// the runtime handles the first replay of a message and ignores the following ones.
+// Callers should check the execution still waits for the command, as its failure was replied once it was dead lettered.
+func (m RetryMessage) Replay() events.Event {
+	event := m.Event
+	event.ID = uuid.NewSHA1(replayNamespace, []byte(m.Event.ID)).String()
+	return event
+}
+
+// RetryHandler consumes the retry topics of a Runtime.
+// It holds each message until its scheduled time and hands the wrapped command back to the runtime.
+// Delays are fixed per retry topic, so each retry topic should be consumed by its own consumer.
+type RetryHandler struct {
+	runtime *Runtime
+}
+
+var (
+	_ streaming.Handler = (*RetryHandler)(nil)
+)
+
+// WithRetryPolicy enables delayed retries of the commands whose handler returned a retryable error.
+func (rt *Runtime) WithRetryPolicy(policy RetryPolicy) *Runtime {
+	rt.retryPolicy = policy
+	return rt
+}
+
+// RetryTopics returns the retry topics the runtime must consume from, ordered by request topic and attempt.
+func (rt *Runtime) RetryTopics() []string {
+	topics := make([]string, 0)
+	for _, topic := range rt.Topics() {
+		for attempt := 1; attempt <= rt.retryPolicy.MaxRetries(); attempt++ {
+			topics = append(topics, RetryTopic(topic, attempt))
+		}
+	}
+	return topics
+}
+
+// DeadLetterTopics returns the dead letter topics the runtime publishes to.
+func (rt *Runtime) DeadLetterTopics() []string {
+	if rt.retryPolicy.MaxRetries() == 0 {
+		return []string{}
+	}
+	topics := make([]string, 0)
+	for _, topic := range rt.Topics() {
+		topics = append(topics, DeadLetterTopic(topic))
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Retries returns the handler of the messages published to the runtime retry topics.
+func (rt *Runtime) Retries() *RetryHandler {
+	return &RetryHandler{runtime: rt}
+}
+
+// retry schedules the next attempt of event or, when its retries are exhausted, publishes it to the dead letter topic.
+// It reports whether the command was dead lettered.
+func (rt *Runtime) retry(ctx context.Context, route Route, event *events.Event, attempt int, cause error) (bool, error) {
+	topic := route.Contract.Topics.Request
+	msg := RetryMessage{
+		Attempt: attempt + 1,
+		Topic:   topic,
+		Error:   cause.Error(),
+		Event:   *event,
+	}
+	destination := DeadLetterTopic(topic)
+	deadLettered := attempt >= rt.retryPolicy.MaxRetries()
+	if !deadLettered {
+		msg.NotBefore = time.Now().UTC().Add(rt.retryPolicy.Delays[attempt])
+		destination = RetryTopic(topic, msg.Attempt)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+	err = rt.publisher.Publish(ctx, destination, data)
+	if err != nil {
+		return false, err
+	}
+	return deadLettered, nil
+}
+
+func (h *RetryHandler) Handle(ctx context.Context, msg *kafka.Message, commitFn func() error) error {
+	rt := h.runtime
+	l := rt.logger
+
+	var retryMsg RetryMessage
+	if err := json.Unmarshal(msg.Value, &retryMsg); err != nil {
+		l.With(zap.Error(err)).Error("Got error unmarshalling retry message. Message will be ignored")
+		rt.metrics.ignored("")
+		return rt.commit(commitFn)
+	}
+	l = l.With("event_type", retryMsg.Event.Type, "correlation_id", retryMsg.Event.CorrelationID, "attempt", retryMsg.Attempt)
+
+	if wait := time.Until(retryMsg.NotBefore); wait > 0 {
+		l.Infof("Waiting %s before retrying command", wait)
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-streaming.Stopping(ctx):
+			l.Info("Consumer stopping before retrying command. Message will be redelivered")
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	route, ok := rt.routes[retryMsg.Event.Type]
+	if !ok {
+		l.Info("No route for event type. Message will be ignored")
+		rt.metrics.ignored(retryMsg.Event.Type)
+		return rt.commit(commitFn)
+	}
+
+	key, err := idempotenceKey(&retryMsg.Event)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error creating idempotence key")
+		return err
+	}
+	return rt.process(ctx, l, route, &retryMsg.Event, fmt.Sprintf("%s:retry:%d", key, retryMsg.Attempt), retryMsg.Attempt, commitFn)
+}
@@ -0,0 +1,249 @@
+package participant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kv"
+	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type rawPublisherMock struct {
+	destinations []string
+	messages     [][]byte
+}
+
+func (p *rawPublisherMock) Publish(_ context.Context, destination string, data []byte) error {
+	p.destinations = append(p.destinations, destination)
+	p.messages = append(p.messages, data)
+	return nil
+}
+
+func (p *rawPublisherMock) retryMessage(t *testing.T, i int) RetryMessage {
+	var msg RetryMessage
+	require.NoError(t, json.Unmarshal(p.messages[i], &msg))
+	return msg
+}
+
+func TestRetryable(t *testing.T) {
+	t.Run("should mark wrapped errors as retryable", func(t *testing.T) {
+		cause := errors.New("boom")
+		err := Retryable(cause)
+		assert.True(t, IsRetryable(err))
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, "boom", err.Error())
+	})
+
+	t.Run("should not mark plain errors as retryable", func(t *testing.T) {
+		assert.False(t, IsRetryable(errors.New("boom")))
+		assert.NoError(t, Retryable(nil))
+	})
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	maxPollInterval := 5 * time.Minute
+	tests := []struct {
+		name    string
+		delays  []time.Duration
+		wantErr bool
+	}{
+		{name: "should accept no delays", delays: nil},
+		{name: "should accept delays shorter than the max poll interval", delays: []time.Duration{5 * time.Second, 2 * time.Minute}},
+		{name: "should reject delays as long as the max poll interval", delays: []time.Duration{5 * time.Second, 5 * time.Minute}, wantErr: true},
+		{name: "should reject delays longer than the max poll interval", delays: []time.Duration{time.Hour}, wantErr: true},
+		{name: "should reject delays that are not positive", delays: []time.Duration{0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RetryPolicy{Delays: tt.delays}.Validate(maxPollInterval)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRetryDelay)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRetryMessage_Replay(t *testing.T) {
+	t.Run("should return the command with a new ID", func(t *testing.T) {
+		event := events.NewEvent("reject_order", "orchestrator", map[string]interface{}{"order_id": "1"})
+		msg := RetryMessage{Attempt: 3, Topic: "service.orders.request", Event: *event}
+
+		replayed := msg.Replay()
+
+		assert.NotEqual(t, event.ID, replayed.ID)
+		assert.Equal(t, event.CorrelationID, replayed.CorrelationID)
+		assert.Equal(t, event.Type, replayed.Type)
+		assert.Equal(t, event.Data, replayed.Data)
+	})
+
+	t.Run("should return the same ID every time the message is replayed", func(t *testing.T) {
+		msg := RetryMessage{Topic: "service.orders.request", Event: *events.NewEvent("reject_order", "orchestrator", nil)}
+		other := RetryMessage{Topic: "service.orders.request", Event: *events.NewEvent("reject_order", "orchestrator", nil)}
+
+		assert.Equal(t, msg.Replay().ID, msg.Replay().ID)
+		assert.NotEqual(t, msg.Replay().ID, other.Replay().ID)
+	})
+}
+
+func TestRuntime_Retry(t *testing.T) {
+	noCommit := func() error { return nil }
+	failing := func(calls *int, err error) Handler {
+		return HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			*calls++
+			return nil, err
+		})
+	}
+	newRetryRuntime := func(t *testing.T, publisher *rawPublisherMock, route Route) *Runtime {
+		rt, err := NewRuntime(zap.NewNop().Sugar(), publisher, kv.NewInmemAdapter(), route)
+		require.NoError(t, err)
+		return rt.WithRetryPolicy(RetryPolicy{Delays: []time.Duration{time.Millisecond, time.Millisecond}})
+	}
+
+	t.Run("should return the retry and dead letter topics", func(t *testing.T) {
+		rt := newRetryRuntime(t, &rawPublisherMock{}, RequestRoute(approveOrderContract, noopHandler()))
+		assert.Equal(t, []string{"service.orders.request.retry.1", "service.orders.request.retry.2"}, rt.RetryTopics())
+		assert.Equal(t, []string{"service.orders.request.dlq"}, rt.DeadLetterTopics())
+	})
+
+	t.Run("should schedule retryable failures on the first retry topic", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(approveOrderContract, failing(&calls, Retryable(errors.New("boom")))))
+		event := events.NewEvent("approve_order", "orchestrator", nil)
+		data, err := event.ToJSON()
+		require.NoError(t, err)
+
+		err = rt.Handle(context.Background(), &kafka.Message{Value: data}, noCommit)
+
+		assert.NoError(t, err)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "service.orders.request.retry.1", publisher.destinations[0])
+		msg := publisher.retryMessage(t, 0)
+		assert.Equal(t, 1, msg.Attempt)
+		assert.Equal(t, "boom", msg.Error)
+		assert.Equal(t, event.ID, msg.Event.ID)
+		assert.Equal(t, int64(1), rt.Metrics().Snapshot()["approve_order"].Retried)
+	})
+
+	t.Run("should not retry failures that are not retryable", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(approveOrderContract, failing(&calls, errors.New("boom"))))
+		data, err := events.NewEvent("approve_order", "orchestrator", nil).ToJSON()
+		require.NoError(t, err)
+
+		err = rt.Handle(context.Background(), &kafka.Message{Value: data}, noCommit)
+
+		assert.NoError(t, err)
+		assert.Empty(t, publisher.messages)
+	})
+
+	t.Run("should retry commands until they are sent to the dead letter topic", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(createOrderContract, failing(&calls, Retryable(errors.New("boom")))))
+		data, err := events.NewEvent("create_order", "orchestrator", nil).ToJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, rt.Handle(context.Background(), &kafka.Message{Value: data}, noCommit))
+		require.NoError(t, rt.Retries().Handle(context.Background(), &kafka.Message{Value: publisher.messages[0]}, noCommit))
+		require.NoError(t, rt.Retries().Handle(context.Background(), &kafka.Message{Value: publisher.messages[1]}, noCommit))
+
+		assert.Equal(t, 3, calls)
+		require.Len(t, publisher.messages, 4)
+		assert.Equal(t, []string{
+			"service.orders.request.retry.1",
+			"service.orders.request.retry.2",
+			"service.orders.request.dlq",
+			"service.orders.events",
+		}, publisher.destinations)
+		assert.Equal(t, 3, publisher.retryMessage(t, 2).Attempt)
+		var reply events.Event
+		require.NoError(t, json.Unmarshal(publisher.messages[3], &reply))
+		assert.Equal(t, "order_creation_failed", reply.Type)
+		counters := rt.Metrics().Snapshot()["create_order"]
+		assert.Equal(t, int64(2), counters.Retried)
+		assert.Equal(t, int64(1), counters.DeadLetter)
+		assert.Equal(t, int64(1), counters.Failed)
+	})
+
+	t.Run("should reply with success when a retry succeeds", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(approveOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, Retryable(errors.New("boom"))
+			}
+			return map[string]interface{}{}, nil
+		})))
+		data, err := events.NewEvent("approve_order", "orchestrator", nil).ToJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, rt.Handle(context.Background(), &kafka.Message{Value: data}, noCommit))
+		require.NoError(t, rt.Retries().Handle(context.Background(), &kafka.Message{Value: publisher.messages[0]}, noCommit))
+
+		require.Len(t, publisher.messages, 2)
+		assert.Equal(t, "service.orders.events", publisher.destinations[1])
+		var reply events.Event
+		require.NoError(t, json.Unmarshal(publisher.messages[1], &reply))
+		assert.Equal(t, "order_approved", reply.Type)
+	})
+
+	t.Run("should handle a dead lettered command replayed several times once", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(approveOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			calls++
+			return map[string]interface{}{}, nil
+		})))
+		event := events.NewEvent("approve_order", "orchestrator", nil)
+		data, err := event.ToJSON()
+		require.NoError(t, err)
+		require.NoError(t, rt.Handle(context.Background(), &kafka.Message{Value: data}, noCommit))
+		replay := RetryMessage{Attempt: 3, Topic: "service.orders.request", Event: *event}.Replay()
+		replayData, err := replay.ToJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, rt.Handle(context.Background(), &kafka.Message{Value: replayData}, noCommit))
+		require.NoError(t, rt.Handle(context.Background(), &kafka.Message{Value: replayData}, noCommit))
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not commit retries when the consumer stops before the scheduled time", func(t *testing.T) {
+		publisher := &rawPublisherMock{}
+		calls := 0
+		rt := newRetryRuntime(t, publisher, RequestRoute(approveOrderContract, failing(&calls, Retryable(errors.New("boom")))))
+		data, err := json.Marshal(RetryMessage{
+			Attempt:   1,
+			NotBefore: time.Now().Add(time.Hour),
+			Event:     *events.NewEvent("approve_order", "orchestrator", nil),
+		})
+		require.NoError(t, err)
+		// the consumer doesn't cancel the context of the message in flight, it only signals it is stopping
+		stopping := make(chan struct{})
+		close(stopping)
+		ctx := streaming.WithStopping(context.Background(), stopping)
+		commits := 0
+
+		err = rt.Retries().Handle(ctx, &kafka.Message{Value: data}, func() error {
+			commits++
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, commits)
+		assert.Equal(t, 0, calls)
+	})
+}
@@ -0,0 +1,247 @@
+package participant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/streaming"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotenceTTL = time.Hour * 24 * 30
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, destination string, data []byte) error
+}
+
+// Runtime is the streaming handler shared by saga participants.
+// It dispatches commands to their routes by event type, deduplicates redelivered commands,
+// maps handler errors to failure replies and publishes the replies to the participant reply topic.
+type Runtime struct {
+	logger             *zap.SugaredLogger
+	publisher          Publisher
+	idempotenceService streaming.IdempotenceService
+	origin             string
+	replyTopic         string
+	routes             map[string]Route
+	retryPolicy        RetryPolicy
+	metrics            *Metrics
+}
+
+var (
+	_ streaming.Handler = (*Runtime)(nil)
+)
+
+// NewRuntime creates a participant runtime for the given routes.
+// The runtime replies as the service declared by the routes contracts to their response topic.
+// It returns an error if the routes are invalid, route a command twice or belong to different services or reply topics.
+func NewRuntime(
+	logger *zap.SugaredLogger,
+	publisher Publisher,
+	idempotenceService streaming.IdempotenceService,
+	routes ...Route,
+) (*Runtime, error) {
+	if len(routes) == 0 {
+		return nil, ErrNoRoutes
+	}
+	var (
+		origin     = routes[0].Contract.ServiceName
+		replyTopic = routes[0].Contract.Topics.Response
+		routesMap  = make(map[string]Route, len(routes))
+	)
+	for _, route := range routes {
+		command := route.Command()
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("invalid route for command [%s]: %w", command, err)
+		}
+		if route.Contract.ServiceName != origin {
+			return nil, fmt.Errorf("%w: command [%s] belongs to service [%s] instead of [%s]", ErrRouteMismatch, command, route.Contract.ServiceName, origin)
+		}
+		if route.Contract.Topics.Response != replyTopic {
+			return nil, fmt.Errorf("%w: command [%s] replies to topic [%s] instead of [%s]", ErrRouteMismatch, command, route.Contract.Topics.Response, replyTopic)
+		}
+		if _, ok := routesMap[command]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateRoute, command)
+		}
+		routesMap[command] = route
+	}
+	return &Runtime{
+		logger:             logger,
+		publisher:          publisher,
+		idempotenceService: idempotenceService,
+		origin:             origin,
+		replyTopic:         replyTopic,
+		routes:             routesMap,
+		metrics:            NewMetrics(),
+	}, nil
+}
+
+// Topics returns the request topics the runtime must consume from.
+func (rt *Runtime) Topics() []string {
+	seen := make(map[string]bool)
+	topics := make([]string, 0)
+	for _, route := range rt.routes {
+		topic := route.Contract.Topics.Request
+		if seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Metrics returns the counters of the messages handled by the runtime.
+func (rt *Runtime) Metrics() *Metrics {
+	return rt.metrics
+}
+
+func (rt *Runtime) Handle(ctx context.Context, msg *kafka.Message, commitFn func() error) error {
+	l := rt.logger
+	l.Infof("Participant [%s] received message [%s]", rt.origin, string(msg.Value))
+
+	var event events.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		l.With(zap.Error(err)).Error("Got error unmarshalling message. Message will be ignored")
+		rt.metrics.ignored("")
+		return rt.commit(commitFn)
+	}
+	rt.metrics.received(event.Type)
+	l = l.With("event_type", event.Type, "correlation_id", event.CorrelationID)
+
+	route, ok := rt.routes[event.Type]
+	if !ok {
+		l.Info("No route for event type. Message will be ignored")
+		rt.metrics.ignored(event.Type)
+		return rt.commit(commitFn)
+	}
+
+	key, err := idempotenceKey(&event)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error creating idempotence key")
+		return err
+	}
+	return rt.process(ctx, l, route, &event, key, 0, commitFn)
+}
+
+// process handles the given attempt of a routed command, unless the idempotence key was already processed.
+// Retryable failures are scheduled for a later attempt when the runtime has a retry policy.
+func (rt *Runtime) process(
+	ctx context.Context,
+	l *zap.SugaredLogger,
+	route Route,
+	event *events.Event,
+	key string,
+	attempt int,
+	commitFn func() error,
+) error {
+	processed, err := rt.idempotenceService.Has(ctx, key)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error checking idempotence")
+	}
+	if processed {
+		l.Info("Message was already processed")
+		rt.metrics.duplicated(event.Type)
+		return rt.commit(commitFn)
+	}
+
+	reply, err := rt.dispatch(ctx, route, event)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error handling message")
+		if IsRetryable(err) && rt.retryPolicy.MaxRetries() > 0 {
+			deadLettered, rErr := rt.retry(ctx, route, event, attempt, err)
+			if rErr != nil {
+				l.With(zap.Error(rErr)).Error("Got error scheduling command retry")
+				return rErr
+			}
+			if !deadLettered {
+				l.Infof("Command scheduled for retry attempt [%d]", attempt+1)
+				rt.metrics.retried(event.Type)
+				return rt.setProcessedAndCommit(ctx, l, key, commitFn)
+			}
+			l.Info("Command exhausted its retries and was sent to the dead letter topic")
+			rt.metrics.deadLettered(event.Type)
+		}
+		rt.metrics.failed(event.Type)
+		if reply == nil {
+			l.Info("Route has no failure event. No reply will be published")
+			return rt.setProcessedAndCommit(ctx, l, key, commitFn)
+		}
+	} else {
+		rt.metrics.succeeded(event.Type)
+	}
+
+	err = rt.publish(ctx, reply)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error publishing reply message")
+		return err
+	}
+	return rt.setProcessedAndCommit(ctx, l, key, commitFn)
+}
+
+func (rt *Runtime) setProcessedAndCommit(ctx context.Context, l *zap.SugaredLogger, key string, commitFn func() error) error {
+	err := rt.idempotenceService.Set(ctx, key, idempotenceTTL)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error setting idempotence")
+	}
+	return rt.commit(commitFn)
+}
+
+// dispatch runs the route handler and builds the reply event.
+// When the handler fails, the returned reply is the route failure event or nil if the route has none.
+func (rt *Runtime) dispatch(ctx context.Context, route Route, event *events.Event) (*events.Event, error) {
+	data, err := route.Handler.Handle(ctx, event)
+	if err != nil {
+		if route.FailureEvent() == "" {
+			return nil, err
+		}
+		reply := events.NewEvent(route.FailureEvent(), rt.origin, failureData(err)).WithCorrelationID(event.CorrelationID)
+		return reply, err
+	}
+	return events.NewEvent(route.SuccessEvent(), rt.origin, data).WithCorrelationID(event.CorrelationID), nil
+}
+
+func (rt *Runtime) publish(ctx context.Context, reply *events.Event) error {
+	data, err := reply.ToJSON()
+	if err != nil {
+		return err
+	}
+	return rt.publisher.Publish(ctx, rt.replyTopic, data)
+}
+
+func (rt *Runtime) commit(commitFn func() error) error {
+	err := commitFn()
+	if err != nil {
+		rt.logger.With(zap.Error(err)).Error("Got error committing message")
+		return err
+	}
+	return nil
+}
+
+func failureData(err error) map[string]interface{} {
+	data := map[string]interface{}{
+		"error": err.Error(),
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		data["details"] = validationErr.Fields
+	}
+	return data
+}
+
+func idempotenceKey(event *events.Event) (string, error) {
+	hash, err := event.Hash()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s:%s", event.ID, event.CorrelationID, hash), nil
+}
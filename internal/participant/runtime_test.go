@@ -0,0 +1,276 @@
+package participant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/internal/adapters/infra/kv"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type publisherMock struct {
+	destinations []string
+	messages     []events.Event
+}
+
+func (p *publisherMock) Publish(_ context.Context, destination string, data []byte) error {
+	var event events.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	p.destinations = append(p.destinations, destination)
+	p.messages = append(p.messages, event)
+	return nil
+}
+
+func newMessage(t *testing.T, event *events.Event) *kafka.Message {
+	data, err := event.ToJSON()
+	require.NoError(t, err)
+	return &kafka.Message{Value: data}
+}
+
+var (
+	ordersTopics = saga.Topics{
+		Request:  "service.orders.request",
+		Response: "service.orders.events",
+	}
+	createOrderContract = saga.StepContract{
+		ServiceName: "orders",
+		EventTypes: saga.EventTypes{
+			Request:             "create_order",
+			Success:             "order_created",
+			Failure:             "order_creation_failed",
+			CompesationRequest:  "reject_order",
+			Compensation:        "order_rejected",
+			CompensationFailure: "order_rejection_failed",
+		},
+		Topics: ordersTopics,
+	}
+	approveOrderContract = saga.StepContract{
+		ServiceName: "orders",
+		EventTypes: saga.EventTypes{
+			Request: "approve_order",
+			Success: "order_approved",
+		},
+		Topics: ordersTopics,
+	}
+	authorizeCardContract = saga.StepContract{
+		ServiceName: "accounting",
+		EventTypes: saga.EventTypes{
+			Request: "authorize_card",
+			Success: "card_authorized",
+			Failure: "card_authorization_failed",
+		},
+		Topics: saga.Topics{
+			Request:  "service.accounting.request",
+			Response: "service.accounting.events",
+		},
+	}
+)
+
+func newTestRuntime(t *testing.T, publisher *publisherMock, routes ...Route) *Runtime {
+	rt, err := NewRuntime(zap.NewNop().Sugar(), publisher, kv.NewInmemAdapter(), routes...)
+	require.NoError(t, err)
+	return rt
+}
+
+func noopHandler() Handler {
+	return HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	})
+}
+
+func TestNewRuntime(t *testing.T) {
+	t.Run("should return error when there are no routes", func(t *testing.T) {
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter())
+		assert.ErrorIs(t, err, ErrNoRoutes)
+	})
+
+	t.Run("should return error when a route has no handler", func(t *testing.T) {
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(), RequestRoute(createOrderContract, nil))
+		assert.ErrorIs(t, err, ErrNilHandler)
+	})
+
+	t.Run("should return error when a route contract is invalid", func(t *testing.T) {
+		contract := createOrderContract
+		contract.EventTypes.Success = ""
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(), RequestRoute(contract, noopHandler()))
+		assert.ErrorIs(t, err, saga.ErrInvalidContract)
+	})
+
+	t.Run("should return error when compensating a contract without compensation", func(t *testing.T) {
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(), CompensationRoute(approveOrderContract, noopHandler()))
+		assert.ErrorIs(t, err, saga.ErrInvalidContract)
+	})
+
+	t.Run("should return error when routes belong to different services", func(t *testing.T) {
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(),
+			RequestRoute(createOrderContract, noopHandler()),
+			RequestRoute(authorizeCardContract, noopHandler()),
+		)
+		assert.ErrorIs(t, err, ErrRouteMismatch)
+	})
+
+	t.Run("should return error when a command is routed twice", func(t *testing.T) {
+		_, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(),
+			RequestRoute(createOrderContract, noopHandler()),
+			RequestRoute(createOrderContract, noopHandler()),
+		)
+		assert.ErrorIs(t, err, ErrDuplicateRoute)
+	})
+
+	t.Run("should return the request topics of the routes", func(t *testing.T) {
+		rt, err := NewRuntime(zap.NewNop().Sugar(), &publisherMock{}, kv.NewInmemAdapter(),
+			RequestRoute(createOrderContract, noopHandler()),
+			CompensationRoute(createOrderContract, noopHandler()),
+			RequestRoute(approveOrderContract, noopHandler()),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"service.orders.request"}, rt.Topics())
+	})
+}
+
+func TestRuntime_Handle(t *testing.T) {
+	commitFn := func(commits *int) func() error {
+		return func() error {
+			*commits++
+			return nil
+		}
+	}
+
+	t.Run("should publish success reply and commit", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, RequestRoute(createOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			return map[string]interface{}{"id": "123"}, nil
+		})))
+		event := events.NewEvent("create_order", "orchestrator", nil)
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, event), commitFn(&commits))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, commits)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "service.orders.events", publisher.destinations[0])
+		assert.Equal(t, "order_created", publisher.messages[0].Type)
+		assert.Equal(t, "orders", publisher.messages[0].Origin)
+		assert.Equal(t, event.CorrelationID, publisher.messages[0].CorrelationID)
+		assert.Equal(t, "123", publisher.messages[0].Data["id"])
+		assert.Equal(t, int64(1), rt.Metrics().Snapshot()["create_order"].Succeeded)
+	})
+
+	t.Run("should publish failure reply when handler returns error", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, RequestRoute(createOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			return nil, errors.New("boom")
+		})))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("create_order", "orchestrator", nil)), commitFn(&commits))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, commits)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "order_creation_failed", publisher.messages[0].Type)
+		assert.Equal(t, "boom", publisher.messages[0].Data["error"])
+		assert.Equal(t, int64(1), rt.Metrics().Snapshot()["create_order"].Failed)
+	})
+
+	t.Run("should publish validation details on failure reply", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, RequestRoute(authorizeCardContract, NewTypedHandler(validator.New(), func(context.Context, Command[typedRequest]) (typedResponse, error) {
+			return typedResponse{}, nil
+		})))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("authorize_card", "orchestrator", map[string]interface{}{"card": "1234"})), commitFn(&commits))
+
+		assert.NoError(t, err)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "card_authorization_failed", publisher.messages[0].Type)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"field": "amount", "message": "must satisfy the 'required' rule"},
+		}, publisher.messages[0].Data["details"])
+	})
+
+	t.Run("should not publish reply when route has no failure event", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, RequestRoute(approveOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			return nil, errors.New("boom")
+		})))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("approve_order", "orchestrator", nil)), commitFn(&commits))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, commits)
+		assert.Empty(t, publisher.messages)
+	})
+
+	t.Run("should publish compensation reply for compensation routes", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, CompensationRoute(createOrderContract, noopHandler()))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("reject_order", "orchestrator", nil)), commitFn(&commits))
+
+		assert.NoError(t, err)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "order_rejected", publisher.messages[0].Type)
+	})
+
+	t.Run("should publish compensation failure reply when compensation fails", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, CompensationRoute(createOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			return nil, errors.New("boom")
+		})))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("reject_order", "orchestrator", nil)), commitFn(&commits))
+
+		assert.NoError(t, err)
+		require.Len(t, publisher.messages, 1)
+		assert.Equal(t, "order_rejection_failed", publisher.messages[0].Type)
+		assert.Equal(t, "boom", publisher.messages[0].Data["error"])
+	})
+
+	t.Run("should ignore messages without route", func(t *testing.T) {
+		publisher := &publisherMock{}
+		rt := newTestRuntime(t, publisher, RequestRoute(createOrderContract, noopHandler()))
+		commits := 0
+
+		err := rt.Handle(context.Background(), newMessage(t, events.NewEvent("unknown", "orchestrator", nil)), commitFn(&commits))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, commits)
+		assert.Empty(t, publisher.messages)
+		assert.Equal(t, int64(1), rt.Metrics().Snapshot()["unknown"].Ignored)
+	})
+
+	t.Run("should not handle the same message twice", func(t *testing.T) {
+		publisher := &publisherMock{}
+		calls := 0
+		rt := newTestRuntime(t, publisher, RequestRoute(createOrderContract, HandlerFunc(func(context.Context, *events.Event) (map[string]interface{}, error) {
+			calls++
+			return map[string]interface{}{}, nil
+		})))
+		msg := newMessage(t, events.NewEvent("create_order", "orchestrator", nil))
+		commits := 0
+
+		assert.NoError(t, rt.Handle(context.Background(), msg, commitFn(&commits)))
+		assert.NoError(t, rt.Handle(context.Background(), msg, commitFn(&commits)))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, commits)
+		assert.Len(t, publisher.messages, 1)
+		assert.Equal(t, int64(1), rt.Metrics().Snapshot()["create_order"].Duplicated)
+	})
+}
@@ -0,0 +1,134 @@
+package participant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/pkg/structs"
+	goval "github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrInvalidPayload = errors.New("invalid command payload")
+)
+
+// Command is a command event whose data was decoded and validated into Req.
+type Command[Req any] struct {
+	CorrelationID string
+	Data          Req
+}
+
+// TypedHandlerFunc executes a typed command and returns the data of the success reply.
+type TypedHandlerFunc[Req any, Res any] func(ctx context.Context, cmd Command[Req]) (Res, error)
+
+// TypedHandler adapts a TypedHandlerFunc to the Handler interface.
+// It decodes the event data into Req, validates it using the `validate` struct tags
+// and encodes the returned Res as the reply data.
+type TypedHandler[Req any, Res any] struct {
+	validate *goval.Validate
+	fn       TypedHandlerFunc[Req, Res]
+}
+
+var (
+	_ Handler = (*TypedHandler[struct{}, struct{}])(nil)
+)
+
+func NewTypedHandler[Req any, Res any](validate *goval.Validate, fn TypedHandlerFunc[Req, Res]) *TypedHandler[Req, Res] {
+	return &TypedHandler[Req, Res]{
+		validate: validate,
+		fn:       fn,
+	}
+}
+
+func (h *TypedHandler[Req, Res]) Handle(ctx context.Context, event *events.Event) (map[string]interface{}, error) {
+	req, err := h.decode(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.fn(ctx, Command[Req]{
+		CorrelationID: event.CorrelationID,
+		Data:          req,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := structs.ToMap(res)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return data, nil
+}
+
+func (h *TypedHandler[Req, Res]) decode(ctx context.Context, event *events.Event) (Req, error) {
+	var req Req
+	raw, err := json.Marshal(event.Data)
+	if err != nil {
+		return req, fmt.Errorf("%w: %s", ErrInvalidPayload, err.Error())
+	}
+	err = json.Unmarshal(raw, &req)
+	if err != nil {
+		return req, fmt.Errorf("%w: %s", ErrInvalidPayload, err.Error())
+	}
+
+	err = h.validate.StructCtx(ctx, req)
+	if err != nil {
+		var validationErrs goval.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return req, newValidationError(validationErrs)
+		}
+		var invalidErr *goval.InvalidValidationError
+		if errors.As(err, &invalidErr) {
+			// Req is not a struct, so there is nothing to validate
+			return req, nil
+		}
+		return req, err
+	}
+	return req, nil
+}
+
+// FieldError describes a command payload field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by a TypedHandler when the command payload is invalid.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func newValidationError(errs goval.ValidationErrors) *ValidationError {
+	fields := make([]FieldError, len(errs))
+	for i, fe := range errs {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule = fmt.Sprintf("%s=%s", fe.Tag(), fe.Param())
+		}
+		fields[i] = FieldError{
+			Field:   fe.Namespace()[strings.Index(fe.Namespace(), ".")+1:],
+			Message: fmt.Sprintf("must satisfy the '%s' rule", rule),
+		}
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.Field
+	}
+	return fmt.Sprintf("%s: invalid fields [%s]", ErrInvalidPayload.Error(), strings.Join(fields, ", "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidPayload
+}
@@ -0,0 +1,82 @@
+package participant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedRequest struct {
+	Card   string `json:"card" validate:"required"`
+	Amount *int64 `json:"amount" validate:"required,gt=0"`
+}
+
+type typedResponse struct {
+	Authorized bool `json:"authorized"`
+}
+
+func TestTypedHandler_Handle(t *testing.T) {
+	t.Run("should decode request and encode response", func(t *testing.T) {
+		var got Command[typedRequest]
+		h := NewTypedHandler(validator.New(), func(_ context.Context, cmd Command[typedRequest]) (typedResponse, error) {
+			got = cmd
+			return typedResponse{Authorized: true}, nil
+		})
+		event := events.NewEvent("authorize_card", "orchestrator", map[string]interface{}{"card": "1234", "amount": 10})
+
+		data, err := h.Handle(context.Background(), event)
+
+		require.NoError(t, err)
+		assert.Equal(t, event.CorrelationID, got.CorrelationID)
+		assert.Equal(t, "1234", got.Data.Card)
+		assert.Equal(t, int64(10), *got.Data.Amount)
+		assert.Equal(t, map[string]interface{}{"authorized": true}, data)
+	})
+
+	t.Run("should return validation error without calling the handler", func(t *testing.T) {
+		called := false
+		h := NewTypedHandler(validator.New(), func(_ context.Context, cmd Command[typedRequest]) (typedResponse, error) {
+			called = true
+			return typedResponse{}, nil
+		})
+		event := events.NewEvent("authorize_card", "orchestrator", map[string]interface{}{"amount": 0})
+
+		_, err := h.Handle(context.Background(), event)
+
+		assert.False(t, called)
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.ElementsMatch(t, []FieldError{
+			{Field: "card", Message: "must satisfy the 'required' rule"},
+			{Field: "amount", Message: "must satisfy the 'gt=0' rule"},
+		}, validationErr.Fields)
+	})
+
+	t.Run("should return error when payload cannot be decoded", func(t *testing.T) {
+		h := NewTypedHandler(validator.New(), func(_ context.Context, cmd Command[typedRequest]) (typedResponse, error) {
+			return typedResponse{}, nil
+		})
+		event := events.NewEvent("authorize_card", "orchestrator", map[string]interface{}{"card": 10})
+
+		_, err := h.Handle(context.Background(), event)
+
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+
+	t.Run("should encode empty struct response as empty data", func(t *testing.T) {
+		h := NewTypedHandler(validator.New(), func(_ context.Context, cmd Command[struct{}]) (struct{}, error) {
+			return struct{}{}, nil
+		})
+
+		data, err := h.Handle(context.Background(), events.NewEvent("approve_order", "orchestrator", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{}, data)
+	})
+}
@@ -0,0 +1,127 @@
+// Package ratelimit limits how many requests each client makes in a period of time.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"go.uber.org/zap"
+)
+
+// Store counts the hits of fixed windows. The counters are kept in memory by a single replica or shared in Redis.
+type Store interface {
+	// Increment adds a hit to the counter of key, created to expire after window,
+	// and returns the hits and how long until the counter expires.
+	Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// Limit is the number of requests allowed in each period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// IsZero returns true if the limit allows every request.
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// Result is the decision of the limiter on a request.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the client can make requests again.
+	RetryAfter time.Duration
+}
+
+// Limiter allows each client at most Limit requests in every window of Limit.Period.
+type Limiter struct {
+	logger *zap.SugaredLogger
+	store  Store
+	limit  Limit
+}
+
+// NewLimiter returns a limiter counting requests in store. A zero limit allows every request.
+func NewLimiter(logger *zap.SugaredLogger, store Store, limit Limit) *Limiter {
+	return &Limiter{
+		logger: logger,
+		store:  store,
+		limit:  limit,
+	}
+}
+
+// Allow counts a request of the client identified by key.
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	if l.limit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+	hits, ttl, err := l.store.Increment(ctx, "ratelimit:"+key, l.limit.Period)
+	if err != nil {
+		return Result{}, err
+	}
+	if hits > int64(l.limit.Requests) {
+		return Result{RetryAfter: ttl}, nil
+	}
+	return Result{Allowed: true, Remaining: l.limit.Requests - int(hits)}, nil
+}
+
+// KeyFunc returns the key identifying the client that made the request.
+type KeyFunc func(r *http.Request) string
+
+// ClientKey identifies the client by the subject of its principal, or by its IP address when the request is not authenticated.
+func ClientKey(r *http.Request) string {
+	if principal, ok := auth.PrincipalFrom(r.Context()); ok && principal.Subject != "" {
+		return "principal:" + principal.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ErrorHandler writes the response of a request rejected with status 429.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request)
+
+// Middleware returns a middleware rejecting the requests over the limit of their client with 429 Too Many Requests
+// and a Retry-After header. Requests are let through when the store fails, so an outage does not take the API down.
+func (l *Limiter) Middleware(key KeyFunc, onLimited ErrorHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if l == nil || l.limit.IsZero() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			clientKey := key(r)
+			result, err := l.Allow(r.Context(), clientKey)
+			if err != nil {
+				l.logger.With(zap.Error(err)).Error("Got error counting request, letting it through")
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(l.limit.Requests))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				l.logger.Warnf("Client [%s] exceeded the rate limit", clientKey)
+				SetRetryAfter(w, result.RetryAfter)
+				onLimited(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetRetryAfter sets the Retry-After header to wait, rounded up to whole seconds.
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
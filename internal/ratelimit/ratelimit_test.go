@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type storeMock struct {
+	hits map[string]int64
+	err  error
+}
+
+func (s *storeMock) Increment(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	if s.hits == nil {
+		s.hits = make(map[string]int64)
+	}
+	s.hits[key]++
+	return s.hits[key], window, nil
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should allow the requests of each client up to the limit", func(t *testing.T) {
+		limiter := NewLimiter(zap.NewNop().Sugar(), &storeMock{}, Limit{Requests: 2, Period: time.Minute})
+
+		first, err := limiter.Allow(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, Result{Allowed: true, Remaining: 1}, first)
+		second, err := limiter.Allow(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, Result{Allowed: true, Remaining: 0}, second)
+		third, err := limiter.Allow(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, Result{RetryAfter: time.Minute}, third)
+
+		other, err := limiter.Allow(ctx, "b")
+		require.NoError(t, err)
+		assert.True(t, other.Allowed)
+	})
+
+	t.Run("should allow every request with a zero limit", func(t *testing.T) {
+		store := &storeMock{}
+		limiter := NewLimiter(zap.NewNop().Sugar(), store, Limit{})
+
+		result, err := limiter.Allow(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Empty(t, store.hits)
+	})
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	limited := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	serve := func(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("should reject requests over the limit with Retry-After", func(t *testing.T) {
+		limiter := NewLimiter(zap.NewNop().Sugar(), &storeMock{}, Limit{Requests: 1, Period: 1500 * time.Millisecond})
+		handler := limiter.Middleware(ClientKey, limited)(ok)
+
+		first := serve(handler, httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "0", first.Header().Get("X-RateLimit-Remaining"))
+
+		second := serve(handler, httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.Equal(t, http.StatusTooManyRequests, second.Code)
+		assert.Equal(t, "2", second.Header().Get("Retry-After"))
+	})
+
+	t.Run("should let requests through when the store fails", func(t *testing.T) {
+		limiter := NewLimiter(zap.NewNop().Sugar(), &storeMock{err: errors.New("down")}, Limit{Requests: 1, Period: time.Minute})
+		handler := limiter.Middleware(ClientKey, limited)(ok)
+
+		assert.Equal(t, http.StatusOK, serve(handler, httptest.NewRequest(http.MethodPost, "/", nil)).Code)
+		assert.Equal(t, http.StatusOK, serve(handler, httptest.NewRequest(http.MethodPost, "/", nil)).Code)
+	})
+
+	t.Run("should let every request through with a nil limiter", func(t *testing.T) {
+		var limiter *Limiter
+		handler := limiter.Middleware(ClientKey, limited)(ok)
+
+		assert.Equal(t, http.StatusOK, serve(handler, httptest.NewRequest(http.MethodPost, "/", nil)).Code)
+	})
+}
+
+func TestClientKey(t *testing.T) {
+	t.Run("should identify authenticated clients by subject", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{Subject: "checkout"}))
+
+		assert.Equal(t, "principal:checkout", ClientKey(r))
+	})
+
+	t.Run("should identify anonymous clients by IP address", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = "10.0.0.1:52000"
+
+		assert.Equal(t, "ip:10.0.0.1", ClientKey(r))
+	})
+}
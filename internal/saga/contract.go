@@ -0,0 +1,64 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidContract = errors.New("invalid step contract")
+)
+
+// StepContract declares the messages exchanged between the orchestrator and the participant service executing a step.
+// It is shared by the workflow definition and the participant handlers so both sides agree on event types and topics.
+type StepContract struct {
+	ServiceName string
+	EventTypes  EventTypes
+	Topics      Topics
+}
+
+// IsCompensable returns true if the contract declares the compensation messages of the step.
+func (c StepContract) IsCompensable() bool {
+	return c.EventTypes.CompesationRequest != "" && c.EventTypes.Compensation != ""
+}
+
+// Validate returns an error wrapping ErrInvalidContract if the contract is incomplete or ambiguous.
+func (c StepContract) Validate() error {
+	if c.ServiceName == "" {
+		return fmt.Errorf("%w: service name is empty", ErrInvalidContract)
+	}
+	if c.EventTypes.Request == "" {
+		return fmt.Errorf("%w: request event type of service [%s] is empty", ErrInvalidContract, c.ServiceName)
+	}
+	if c.EventTypes.Success == "" {
+		return fmt.Errorf("%w: success event type of [%s] is empty", ErrInvalidContract, c.EventTypes.Request)
+	}
+	if (c.EventTypes.CompesationRequest == "") != (c.EventTypes.Compensation == "") {
+		return fmt.Errorf("%w: compensation request and compensation event types of [%s] must be declared together", ErrInvalidContract, c.EventTypes.Request)
+	}
+	if c.EventTypes.CompensationFailure != "" && !c.IsCompensable() {
+		return fmt.Errorf("%w: compensation failure event type of [%s] requires the compensation event types", ErrInvalidContract, c.EventTypes.Request)
+	}
+	if c.Topics.Request == "" || c.Topics.Response == "" {
+		return fmt.Errorf("%w: request and response topics of [%s] must be declared", ErrInvalidContract, c.EventTypes.Request)
+	}
+
+	seen := make(map[string]bool)
+	for _, eventType := range []string{
+		c.EventTypes.Request,
+		c.EventTypes.Success,
+		c.EventTypes.Failure,
+		c.EventTypes.CompesationRequest,
+		c.EventTypes.Compensation,
+		c.EventTypes.CompensationFailure,
+	} {
+		if eventType == "" {
+			continue
+		}
+		if seen[eventType] {
+			return fmt.Errorf("%w: event type [%s] is declared more than once", ErrInvalidContract, eventType)
+		}
+		seen[eventType] = true
+	}
+	return nil
+}
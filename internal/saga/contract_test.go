@@ -0,0 +1,122 @@
+package saga
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type payloadBuilderMock struct{}
+
+func (pb *payloadBuilderMock) Build(ctx context.Context, exec *Execution, action ActionType) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func newContract() StepContract {
+	return StepContract{
+		ServiceName: "orders",
+		EventTypes: EventTypes{
+			Request:             "create_order",
+			Success:             "order_created",
+			Failure:             "order_creation_failed",
+			CompesationRequest:  "reject_order",
+			Compensation:        "order_rejected",
+			CompensationFailure: "order_rejection_failed",
+		},
+		Topics: Topics{
+			Request:  "service.orders.request",
+			Response: "service.orders.events",
+		},
+	}
+}
+
+func TestStepContract_Validate(t *testing.T) {
+	t.Run("should accept a complete contract", func(t *testing.T) {
+		assert.NoError(t, newContract().Validate())
+	})
+
+	t.Run("should reject contract without service name", func(t *testing.T) {
+		contract := newContract()
+		contract.ServiceName = ""
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject contract without success event", func(t *testing.T) {
+		contract := newContract()
+		contract.EventTypes.Success = ""
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject contract with compensation request but no compensation event", func(t *testing.T) {
+		contract := newContract()
+		contract.EventTypes.Compensation = ""
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject contract with compensation failure but no compensation", func(t *testing.T) {
+		contract := newContract()
+		contract.EventTypes.CompesationRequest = ""
+		contract.EventTypes.Compensation = ""
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject contract without topics", func(t *testing.T) {
+		contract := newContract()
+		contract.Topics.Response = ""
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject contract with repeated event types", func(t *testing.T) {
+		contract := newContract()
+		contract.EventTypes.Failure = contract.EventTypes.Success
+		assert.ErrorIs(t, contract.Validate(), ErrInvalidContract)
+	})
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	t.Run("should accept a valid workflow", func(t *testing.T) {
+		workflow := &Workflow{
+			Name:  "create_order_v1",
+			Steps: NewStepList(&StepData{Name: "create_order", Compensable: true, PayloadBuilder: &payloadBuilderMock{}, StepContract: newContract()}),
+		}
+		assert.NoError(t, workflow.Validate())
+	})
+
+	t.Run("should reject workflow without steps", func(t *testing.T) {
+		workflow := &Workflow{Name: "create_order_v1", Steps: NewStepList()}
+		assert.ErrorIs(t, workflow.Validate(), ErrInvalidWorkflow)
+	})
+
+	t.Run("should reject duplicated step names", func(t *testing.T) {
+		workflow := &Workflow{
+			Name: "create_order_v1",
+			Steps: NewStepList(
+				&StepData{Name: "create_order", PayloadBuilder: &payloadBuilderMock{}, StepContract: newContract()},
+				&StepData{Name: "create_order", PayloadBuilder: &payloadBuilderMock{}, StepContract: newContract()},
+			),
+		}
+		assert.ErrorIs(t, workflow.Validate(), ErrInvalidWorkflow)
+	})
+
+	t.Run("should reject compensable step without compensation events", func(t *testing.T) {
+		contract := newContract()
+		contract.EventTypes.CompesationRequest = ""
+		contract.EventTypes.Compensation = ""
+		workflow := &Workflow{
+			Name:  "create_order_v1",
+			Steps: NewStepList(&StepData{Name: "create_order", Compensable: true, PayloadBuilder: &payloadBuilderMock{}, StepContract: contract}),
+		}
+		assert.ErrorIs(t, workflow.Validate(), ErrInvalidContract)
+	})
+
+	t.Run("should reject step with invalid contract", func(t *testing.T) {
+		contract := newContract()
+		contract.Topics = Topics{}
+		workflow := &Workflow{
+			Name:  "create_order_v1",
+			Steps: NewStepList(&StepData{Name: "create_order", PayloadBuilder: &payloadBuilderMock{}, StepContract: contract}),
+		}
+		assert.ErrorIs(t, workflow.Validate(), ErrInvalidContract)
+	})
+}
@@ -2,27 +2,186 @@ package saga
 
 import (
 	"errors"
+	"maps"
 	"reflect"
+	"slices"
+	"time"
 
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/bmviniciuss/sagas-golang/pkg/structs"
 	"github.com/google/uuid"
 )
 
+const (
+	ExecutionStatusRunning           ExecutionStatus = "running"
+	ExecutionStatusCompensating      ExecutionStatus = "compensating"
+	ExecutionStatusCompleted         ExecutionStatus = "completed"
+	ExecutionStatusCompensated       ExecutionStatus = "compensated"
+	ExecutionStatusNeedsIntervention ExecutionStatus = "needs_intervention"
+	ExecutionStatusForceCompleted    ExecutionStatus = "force_completed"
+	ExecutionStatusCancelling        ExecutionStatus = "cancelling"
+	ExecutionStatusCancelled         ExecutionStatus = "cancelled"
+)
+
+// ExecutionStatus represents the lifecycle stage of a workflow execution.
+type ExecutionStatus string
+
+func (s ExecutionStatus) String() string {
+	return string(s)
+}
+
+// IsValid returns true if s is a known execution status.
+func (s ExecutionStatus) IsValid() bool {
+	switch s {
+	case ExecutionStatusRunning,
+		ExecutionStatusCompensating,
+		ExecutionStatusCompleted,
+		ExecutionStatusCompensated,
+		ExecutionStatusNeedsIntervention,
+		ExecutionStatusForceCompleted,
+		ExecutionStatusCancelling,
+		ExecutionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsFinished returns true if the execution will not process any more messages.
+func (s ExecutionStatus) IsFinished() bool {
+	return s == ExecutionStatusCompleted ||
+		s == ExecutionStatusCompensated ||
+		s == ExecutionStatusForceCompleted ||
+		s == ExecutionStatusCancelled
+}
+
+// IsActive returns true if the execution is processing its steps or compensations.
+func (s ExecutionStatus) IsActive() bool {
+	return s == ExecutionStatusRunning ||
+		s == ExecutionStatusCompensating ||
+		s == ExecutionStatusCancelling
+}
+
+const (
+	TransitionTypeRequested             TransitionType = "requested"
+	TransitionTypeSucceeded             TransitionType = "succeeded"
+	TransitionTypeFailed                TransitionType = "failed"
+	TransitionTypeCompensationRequested TransitionType = "compensation_requested"
+	TransitionTypeCompensated           TransitionType = "compensated"
+	TransitionTypeCompensationFailed    TransitionType = "compensation_failed"
+	TransitionTypeCancelled             TransitionType = "cancelled"
+)
+
+// TransitionType represents what happened to a step of an execution.
+type TransitionType string
+
+func (t TransitionType) String() string {
+	return string(t)
+}
+
+// Transition records a command sent to a step or a reply received from it.
+type Transition struct {
+	Step      string         `json:"step"`
+	Type      TransitionType `json:"type"`
+	EventType string         `json:"event_type"`
+	Error     string         `json:"error,omitempty"`
+	At        time.Time      `json:"at"`
+}
+
+const (
+	StepOutcomePending = "pending"
+)
+
+// StepOutcome is the result of the last transition of a step.
+type StepOutcome struct {
+	Step string
+	// Status is the type of the last transition of the step or StepOutcomePending if it has none.
+	Status    string
+	UpdatedAt *time.Time
+}
+
+// Intervention records a compensation that kept failing and is waiting for an operator action.
+type Intervention struct {
+	Step      string    `json:"step"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Execution struct {
 	ID       uuid.UUID
 	Workflow *Workflow
 	State    map[string]interface{}
+	Status   ExecutionStatus
+	// Intervention is set while the execution status is ExecutionStatusNeedsIntervention.
+	Intervention *Intervention
+	// CompensationAttempts counts the failed compensation attempts by step name.
+	CompensationAttempts map[string]int
+	// IdempotencyKey identifies the request that started the execution until IdempotencyExpiresAt.
+	IdempotencyKey       string
+	IdempotencyExpiresAt time.Time
+	// CallbackURL receives the webhook notifications of the execution, overriding the workflow CallbackURL.
+	CallbackURL string
+	// StartedBy is the subject of the authenticated principal that started the execution, if any.
+	StartedBy string
+	// BusinessKey identifies the business entity of the execution, see Workflow.BusinessKeyPath.
+	BusinessKey string
+	// CurrentStep is the name of the last step a command was sent to.
+	CurrentStep string
+	Transitions []Transition
+	// OutcomePublished is set once the outcome of the finished execution was published to the workflow ReplyChannel,
+	// or right away when the workflow has none.
+	OutcomePublished bool
+	// Version is incremented by every save, so concurrent updates of the execution are detected.
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// unsaved are the transitions recorded since the execution was last saved by the Service.
+	unsaved []Transition
+	// notifications are built by the Service's notifiers right before the execution is saved.
+	notifications []Notification
 }
 
 func (e *Execution) IsEmpty() bool {
 	return reflect.DeepEqual(e, &Execution{})
 }
 
+// Notifications returns the notifications the ExecutionRepository must save along with the execution.
+func (e *Execution) Notifications() []Notification {
+	return e.notifications
+}
+
+// OutcomePending returns true if the execution finished but its outcome was not published yet,
+// e.g. because publishing it failed after the final status was saved.
+func (e *Execution) OutcomePending() bool {
+	return e.Status.IsFinished() && !e.OutcomePublished
+}
+
+// Clone returns a copy of the execution that shares no maps or slices with it and has no unsaved transitions.
+func (e *Execution) Clone() *Execution {
+	c := *e
+	c.State = maps.Clone(e.State)
+	c.CompensationAttempts = maps.Clone(e.CompensationAttempts)
+	c.Transitions = slices.Clone(e.Transitions)
+	if e.Intervention != nil {
+		intervention := *e.Intervention
+		c.Intervention = &intervention
+	}
+	c.unsaved = nil
+	c.notifications = nil
+	return &c
+}
+
 func NewExecution(workflow *Workflow) *Execution {
+	now := time.Now().UTC()
 	return &Execution{
-		ID:       uuid.New(),
-		Workflow: workflow,
-		State:    make(map[string]interface{}),
+		ID:                   uuid.New(),
+		Workflow:             workflow,
+		State:                make(map[string]interface{}),
+		Status:               ExecutionStatusRunning,
+		CompensationAttempts: make(map[string]int),
+		Transitions:          make([]Transition, 0),
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	}
 }
 
@@ -47,3 +206,196 @@ func (e *Execution) Read(key string, dest interface{}) error {
 	}
 	return nil
 }
+
+// CompensationFailed records a failed compensation attempt of step.
+// Once the attempts reach the workflow limit, the execution needs intervention and true is returned.
+func (e *Execution) CompensationFailed(step string, reason string) bool {
+	if e.CompensationAttempts == nil {
+		e.CompensationAttempts = make(map[string]int)
+	}
+	e.CompensationAttempts[step]++
+	attempts := e.CompensationAttempts[step]
+	if attempts < e.Workflow.CompensationAttempts() {
+		return false
+	}
+	e.Status = ExecutionStatusNeedsIntervention
+	e.Intervention = &Intervention{
+		Step:      step,
+		Error:     reason,
+		Attempts:  attempts,
+		CreatedAt: time.Now().UTC(),
+	}
+	return true
+}
+
+// NeedsIntervention returns true if the execution is waiting for an operator action.
+func (e *Execution) NeedsIntervention() bool {
+	return e.Status == ExecutionStatusNeedsIntervention && e.Intervention != nil
+}
+
+// ClearIntervention removes the pending intervention and resets the compensation attempts of its step.
+func (e *Execution) ClearIntervention() {
+	if e.Intervention != nil {
+		delete(e.CompensationAttempts, e.Intervention.Step)
+	}
+	e.Intervention = nil
+}
+
+// StepRequested records that the command of the given action was sent to step.
+func (e *Execution) StepRequested(step *Step, action ActionType) {
+	transition := Transition{
+		Step:      step.Name,
+		Type:      TransitionTypeRequested,
+		EventType: step.EventTypes.Request,
+		At:        time.Now().UTC(),
+	}
+	if action.IsCompensationRequest() {
+		transition.Type = TransitionTypeCompensationRequested
+		transition.EventType = step.EventTypes.CompesationRequest
+	}
+	e.CurrentStep = step.Name
+	e.addTransition(transition)
+}
+
+// StepReplied records the reply event received from step.
+func (e *Execution) StepReplied(step *Step, event *events.Event) {
+	transition := Transition{
+		Step:      step.Name,
+		EventType: event.Type,
+		At:        time.Now().UTC(),
+	}
+	switch {
+	case step.IsSuccess(event.Type):
+		transition.Type = TransitionTypeSucceeded
+	case step.IsFailure(event.Type):
+		transition.Type = TransitionTypeFailed
+		transition.Error = eventError(event)
+	case step.IsCompensation(event.Type):
+		transition.Type = TransitionTypeCompensated
+	case step.IsCompensationFailure(event.Type):
+		transition.Type = TransitionTypeCompensationFailed
+		transition.Error = eventError(event)
+	default:
+		return
+	}
+	e.addTransition(transition)
+}
+
+// StepResolved records that the compensation of step was done by an operator.
+func (e *Execution) StepResolved(step *Step) {
+	e.addTransition(Transition{
+		Step:      step.Name,
+		Type:      TransitionTypeCompensated,
+		EventType: step.EventTypes.Compensation,
+		At:        time.Now().UTC(),
+	})
+}
+
+// LateStepCompensationRequested records that the compensation of step was sent because it succeeded after the execution was cancelled.
+// The current step is kept, as the compensation of the cancelled step runs alongside the cancellation.
+func (e *Execution) LateStepCompensationRequested(step *Step) {
+	e.addTransition(Transition{
+		Step:      step.Name,
+		Type:      TransitionTypeCompensationRequested,
+		EventType: step.EventTypes.CompesationRequest,
+		At:        time.Now().UTC(),
+	})
+}
+
+// StepCancelled records that the execution was cancelled while waiting for the reply of step.
+func (e *Execution) StepCancelled(step *Step) {
+	e.addTransition(Transition{
+		Step: step.Name,
+		Type: TransitionTypeCancelled,
+		At:   time.Now().UTC(),
+	})
+}
+
+func (e *Execution) addTransition(transition Transition) {
+	e.Transitions = append(e.Transitions, transition)
+	e.unsaved = append(e.unsaved, transition)
+}
+
+// takeUnsaved returns the transitions recorded since the last call.
+func (e *Execution) takeUnsaved() []Transition {
+	unsaved := e.unsaved
+	e.unsaved = nil
+	return unsaved
+}
+
+// awaitingFirstReply returns the first step of the running execution if its command was sent and no reply of it was received yet.
+func (e *Execution) awaitingFirstReply() (*Step, bool) {
+	if e.Status != ExecutionStatusRunning || len(e.Transitions) != 1 || e.Transitions[0].Type != TransitionTypeRequested {
+		return nil, false
+	}
+	return e.Workflow.Steps.GetStep(e.Transitions[0].Step)
+}
+
+// IsCancelled returns true if the execution was cancelled.
+func (e *Execution) IsCancelled() bool {
+	for _, transition := range e.Transitions {
+		if transition.Type == TransitionTypeCancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelledStep returns the name of the step the execution was waiting for when it was cancelled.
+func (e *Execution) cancelledStep() (string, bool) {
+	for _, transition := range e.Transitions {
+		if transition.Type == TransitionTypeCancelled {
+			return transition.Step, true
+		}
+	}
+	return "", false
+}
+
+// lastFailure returns the last failed or compensation failed transition of the execution.
+func (e *Execution) lastFailure() (Transition, bool) {
+	for i := len(e.Transitions) - 1; i >= 0; i-- {
+		transition := e.Transitions[i]
+		if transition.Type == TransitionTypeFailed || transition.Type == TransitionTypeCompensationFailed {
+			return transition, true
+		}
+	}
+	return Transition{}, false
+}
+
+// WebhookURL returns the URL receiving the webhook notifications of the execution or an empty string if there is none.
+func (e *Execution) WebhookURL() string {
+	if e.CallbackURL != "" {
+		return e.CallbackURL
+	}
+	return e.Workflow.CallbackURL
+}
+
+// CompensatingStatus returns the status of the execution while it compensates its completed steps.
+func (e *Execution) CompensatingStatus() ExecutionStatus {
+	if e.IsCancelled() {
+		return ExecutionStatusCancelling
+	}
+	return ExecutionStatusCompensating
+}
+
+// StepOutcomes returns the outcome of every step of the workflow, in workflow order.
+func (e *Execution) StepOutcomes() []StepOutcome {
+	last := make(map[string]Transition)
+	for _, transition := range e.Transitions {
+		last[transition.Step] = transition
+	}
+	steps := e.Workflow.Steps.ToList()
+	outcomes := make([]StepOutcome, len(steps))
+	for i, step := range steps {
+		outcomes[i] = StepOutcome{
+			Step:   step.Name,
+			Status: StepOutcomePending,
+		}
+		if transition, ok := last[step.Name]; ok {
+			at := transition.At
+			outcomes[i].Status = transition.Type.String()
+			outcomes[i].UpdatedAt = &at
+		}
+	}
+	return outcomes
+}
@@ -3,7 +3,9 @@ package saga
 import (
 	"testing"
 
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewExecution(t *testing.T) {
@@ -59,3 +61,36 @@ func TestExecution_Read(t *testing.T) {
 		assert.Equal(t, "value", dest)
 	})
 }
+
+func TestExecution_StepOutcomes(t *testing.T) {
+	t.Run("should return pending for steps without transitions", func(t *testing.T) {
+		execution := NewExecution(newTestWorkflow())
+		outcomes := execution.StepOutcomes()
+		require.Len(t, outcomes, 2)
+		assert.Equal(t, "create_order", outcomes[0].Step)
+		assert.Equal(t, StepOutcomePending, outcomes[0].Status)
+		assert.Nil(t, outcomes[0].UpdatedAt)
+	})
+
+	t.Run("should return the last transition of each step", func(t *testing.T) {
+		workflow := newTestWorkflow()
+		execution := NewExecution(workflow)
+		createOrder, _ := workflow.Steps.GetStep("create_order")
+		verifyCustomer, _ := workflow.Steps.GetStep("verify_customer")
+
+		execution.StepRequested(createOrder, REQUEST_ACTION_TYPE)
+		execution.StepReplied(createOrder, events.NewEvent("order_created", "orders", nil))
+		execution.StepRequested(verifyCustomer, REQUEST_ACTION_TYPE)
+		execution.StepReplied(verifyCustomer, events.NewEvent("customer_verification_failed", "customers", map[string]interface{}{"error": "boom"}))
+		execution.StepRequested(createOrder, COMPESATION_REQUEST_ACTION_TYPE)
+
+		assert.Equal(t, "create_order", execution.CurrentStep)
+		require.Len(t, execution.Transitions, 5)
+		assert.Equal(t, "boom", execution.Transitions[3].Error)
+		assert.Equal(t, "reject_order", execution.Transitions[4].EventType)
+		outcomes := execution.StepOutcomes()
+		assert.Equal(t, TransitionTypeCompensationRequested.String(), outcomes[0].Status)
+		assert.Equal(t, TransitionTypeFailed.String(), outcomes[1].Status)
+		assert.NotNil(t, outcomes[1].UpdatedAt)
+	})
+}
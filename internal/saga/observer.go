@@ -0,0 +1,84 @@
+package saga
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Observer is notified by the Service every time an execution is saved,
+// with the transitions recorded since it was last saved.
+// Implementations must not block, since they are called while the saga messages are processed.
+type Observer interface {
+	ExecutionUpdated(execution *Execution, transitions []Transition)
+}
+
+// Notification is a message about an execution to be POSTed to URL.
+type Notification struct {
+	URL       string
+	EventType string
+	// Payload is the JSON body of the notification.
+	Payload []byte
+}
+
+// Notifier builds the notifications of an execution the Service is about to save,
+// with the transitions recorded since it was last saved.
+// The notifications are saved by the ExecutionRepository in the same transaction as the execution,
+// so they are delivered if and only if the save is committed.
+type Notifier interface {
+	Notifications(execution *Execution, transitions []Transition) ([]Notification, error)
+}
+
+// Waiter is an Observer that lets callers wait for executions processed by this process to finish.
+type Waiter struct {
+	mu      sync.Mutex
+	waiters map[uuid.UUID][]chan ExecutionStatus
+}
+
+var (
+	_ Observer = (*Waiter)(nil)
+)
+
+func NewWaiter() *Waiter {
+	return &Waiter{
+		waiters: make(map[uuid.UUID][]chan ExecutionStatus),
+	}
+}
+
+// Wait returns a channel that receives the final status of the execution identified by id.
+// The returned function must be called once the caller stops waiting.
+func (w *Waiter) Wait(id uuid.UUID) (<-chan ExecutionStatus, func()) {
+	ch := make(chan ExecutionStatus, 1)
+	w.mu.Lock()
+	w.waiters[id] = append(w.waiters[id], ch)
+	w.mu.Unlock()
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		waiters := w.waiters[id]
+		for i, waiter := range waiters {
+			if waiter == ch {
+				waiters = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(w.waiters, id)
+			return
+		}
+		w.waiters[id] = waiters
+	}
+}
+
+func (w *Waiter) ExecutionUpdated(execution *Execution, _ []Transition) {
+	if !execution.Status.IsFinished() {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.waiters[execution.ID] {
+		ch <- execution.Status
+	}
+	delete(w.waiters, execution.ID)
+}
@@ -0,0 +1,123 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type notifierMock struct {
+	err error
+}
+
+func (n *notifierMock) Notifications(execution *Execution, transitions []Transition) ([]Notification, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	notifications := make([]Notification, 0, len(transitions))
+	for _, transition := range transitions {
+		notifications = append(notifications, Notification{URL: "https://partner.example.com/hooks", EventType: transition.Type.String()})
+	}
+	return notifications, nil
+}
+
+func TestWaiter(t *testing.T) {
+	t.Run("should notify waiters when execution finishes", func(t *testing.T) {
+		waiter := NewWaiter()
+		execution := NewExecution(newTestWorkflow())
+		first, stopFirst := waiter.Wait(execution.ID)
+		defer stopFirst()
+		second, stopSecond := waiter.Wait(execution.ID)
+		defer stopSecond()
+
+		waiter.ExecutionUpdated(execution, nil)
+		assert.Empty(t, first)
+
+		execution.Status = ExecutionStatusCompleted
+		waiter.ExecutionUpdated(execution, nil)
+		assert.Equal(t, ExecutionStatusCompleted, <-first)
+		assert.Equal(t, ExecutionStatusCompleted, <-second)
+	})
+
+	t.Run("should not notify waiters that stopped waiting", func(t *testing.T) {
+		waiter := NewWaiter()
+		execution := NewExecution(newTestWorkflow())
+		done, stop := waiter.Wait(execution.ID)
+		stop()
+
+		execution.Status = ExecutionStatusCompensated
+		waiter.ExecutionUpdated(execution, nil)
+
+		assert.Empty(t, done)
+		assert.Empty(t, waiter.waiters)
+	})
+
+	t.Run("should be notified by the service when an execution is saved", func(t *testing.T) {
+		waiter := NewWaiter()
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{}).WithObserver(waiter)
+		execution := NewExecution(newTestWorkflow())
+		current, _ := execution.Workflow.Steps.GetStep("create_order")
+		execution.StepRequested(current, REQUEST_ACTION_TYPE)
+		done, stop := waiter.Wait(execution.ID)
+		defer stop()
+
+		assert.NoError(t, service.Cancel(context.Background(), execution))
+
+		assert.Equal(t, ExecutionStatusCancelled, <-done)
+	})
+}
+
+func TestService_Notifications(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should save the notifications of the new transitions along with the execution", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{}).WithNotifier(&notifierMock{})
+
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+		execution, err := repo.Find(ctx, id.String())
+		require.NoError(t, err)
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), execution))
+
+		types := make([]string, 0, len(repo.notifications))
+		for _, notification := range repo.notifications {
+			types = append(types, notification.EventType)
+		}
+		assert.Equal(t, []string{"requested", "succeeded", "requested"}, types)
+		assert.Empty(t, execution.Notifications())
+	})
+
+	t.Run("should not save notifications when the execution is not saved", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{}).WithNotifier(&notifierMock{})
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+		stored, err := repo.Find(ctx, id.String())
+		require.NoError(t, err)
+		stale := stored.Clone()
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), stored))
+		saved := len(repo.notifications)
+
+		err = service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), stale)
+
+		assert.ErrorIs(t, err, ErrConcurrentUpdate)
+		assert.Len(t, repo.notifications, saved)
+	})
+
+	t.Run("should save the execution when its notifications can't be built", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{}).WithNotifier(&notifierMock{err: errors.New("boom")})
+
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+
+		require.NoError(t, err)
+		assert.NotNil(t, id)
+		assert.Empty(t, repo.notifications)
+	})
+}
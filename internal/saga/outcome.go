@@ -0,0 +1,60 @@
+package saga
+
+import (
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/bmviniciuss/sagas-golang/pkg/structs"
+)
+
+const (
+	// OutcomeOrigin is the origin of the events published to the workflow reply channel.
+	OutcomeOrigin = "orchestrator"
+
+	OutcomeEventCompleted   = "saga_completed"
+	OutcomeEventCompensated = "saga_compensated"
+	OutcomeEventCancelled   = "saga_cancelled"
+	// OutcomeEventFailed is published when an operator finished an execution whose compensation kept failing.
+	OutcomeEventFailed = "saga_failed"
+)
+
+// OutcomeEventType returns the type of the event published to the reply channel when an execution finishes with status.
+func OutcomeEventType(status ExecutionStatus) string {
+	switch status {
+	case ExecutionStatusCompleted:
+		return OutcomeEventCompleted
+	case ExecutionStatusCompensated:
+		return OutcomeEventCompensated
+	case ExecutionStatusCancelled:
+		return OutcomeEventCancelled
+	}
+	return OutcomeEventFailed
+}
+
+// NewOutcomeEvent returns the event announcing the outcome of a finished execution.
+// Its data summarizes the execution with the state fields in redactedFields hidden.
+func NewOutcomeEvent(execution *Execution, redactedFields []string) (*events.Event, error) {
+	state, err := structs.Redact(execution.State, redactedFields)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]map[string]interface{}, 0, execution.Workflow.Steps.Len())
+	for _, outcome := range execution.StepOutcomes() {
+		steps = append(steps, map[string]interface{}{
+			"name":   outcome.Step,
+			"status": outcome.Status,
+		})
+	}
+	data := map[string]interface{}{
+		"execution_id": execution.ID.String(),
+		"workflow":     execution.Workflow.Name,
+		"status":       execution.Status.String(),
+		"business_key": execution.BusinessKey,
+		"steps":        steps,
+		"state":        state,
+	}
+	if transition, ok := execution.lastFailure(); ok {
+		data["failed_step"] = transition.Step
+		data["error"] = transition.Error
+	}
+	return events.NewEvent(OutcomeEventType(execution.Status), OutcomeOrigin, data).
+		WithCorrelationID(execution.ID.String()), nil
+}
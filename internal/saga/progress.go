@@ -0,0 +1,92 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// ProgressEventFinished is the type of the last progress event of an execution.
+	ProgressEventFinished = "finished"
+)
+
+// ProgressEvent is a step transition or the end of an execution, streamed to the clients following it.
+type ProgressEvent struct {
+	ExecutionID uuid.UUID `json:"execution_id"`
+	// Type is the TransitionType of the step transition or ProgressEventFinished.
+	Type      string `json:"type"`
+	Step      string `json:"step,omitempty"`
+	EventType string `json:"event_type,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// Status is the execution status when the event was published.
+	Status ExecutionStatus `json:"status"`
+	At     time.Time       `json:"at"`
+}
+
+// ProgressBroker fans out the progress events of executions to their subscribers.
+type ProgressBroker interface {
+	Publish(ctx context.Context, event ProgressEvent) error
+	// Subscribe returns a channel with the events published for the execution identified by executionID.
+	// The channel is closed once ctx is done or the returned function is called.
+	Subscribe(ctx context.Context, executionID uuid.UUID) (<-chan ProgressEvent, func(), error)
+}
+
+// ProgressEvents returns the progress events of the given transitions of execution,
+// followed by a ProgressEventFinished event if the execution is finished.
+func ProgressEvents(execution *Execution, transitions []Transition) []ProgressEvent {
+	events := make([]ProgressEvent, 0, len(transitions)+1)
+	for _, transition := range transitions {
+		events = append(events, ProgressEvent{
+			ExecutionID: execution.ID,
+			Type:        transition.Type.String(),
+			Step:        transition.Step,
+			EventType:   transition.EventType,
+			Error:       transition.Error,
+			Status:      execution.Status,
+			At:          transition.At,
+		})
+	}
+	if execution.Status.IsFinished() {
+		events = append(events, ProgressEvent{
+			ExecutionID: execution.ID,
+			Type:        ProgressEventFinished,
+			Status:      execution.Status,
+			At:          execution.UpdatedAt,
+		})
+	}
+	return events
+}
+
+// ProgressPublisher is an Observer that publishes the progress of the saved executions to a ProgressBroker.
+type ProgressPublisher struct {
+	logger *zap.SugaredLogger
+	broker ProgressBroker
+}
+
+var (
+	_ Observer = (*ProgressPublisher)(nil)
+)
+
+func NewProgressPublisher(logger *zap.SugaredLogger, broker ProgressBroker) *ProgressPublisher {
+	return &ProgressPublisher{
+		logger: logger,
+		broker: broker,
+	}
+}
+
+func (p *ProgressPublisher) ExecutionUpdated(execution *Execution, transitions []Transition) {
+	events := ProgressEvents(execution, transitions)
+	if execution.Status.IsFinished() && execution.OutcomePublished {
+		// the finished event was published by the save that finished the execution
+		events = events[:len(events)-1]
+	}
+	for _, event := range events {
+		// progress is best effort, clients can always read the execution
+		if err := p.broker.Publish(context.Background(), event); err != nil {
+			p.logger.With(zap.Error(err)).Errorf("Got error publishing progress of execution [%s]", execution.ID)
+		}
+	}
+}
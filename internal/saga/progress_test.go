@@ -0,0 +1,65 @@
+package saga
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type progressBrokerMock struct {
+	events []ProgressEvent
+}
+
+func (b *progressBrokerMock) Publish(_ context.Context, event ProgressEvent) error {
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *progressBrokerMock) Subscribe(_ context.Context, _ uuid.UUID) (<-chan ProgressEvent, func(), error) {
+	return make(chan ProgressEvent), func() {}, nil
+}
+
+func TestProgressPublisher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should publish only the transitions recorded since the last save", func(t *testing.T) {
+		broker := &progressBrokerMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{}).
+			WithObserver(NewProgressPublisher(zap.NewNop().Sugar(), broker))
+
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+		require.Len(t, broker.events, 1)
+		assert.Equal(t, *id, broker.events[0].ExecutionID)
+		assert.Equal(t, TransitionTypeRequested.String(), broker.events[0].Type)
+		assert.Equal(t, "create_order", broker.events[0].Step)
+
+		execution, err := service.executionRepository.Find(ctx, id.String())
+		require.NoError(t, err)
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), execution))
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("customer_verified", "customers", nil), execution))
+
+		types := make([]string, 0, len(broker.events))
+		for _, event := range broker.events {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []string{"requested", "succeeded", "requested", "succeeded", ProgressEventFinished}, types)
+		assert.Equal(t, ExecutionStatusCompleted, broker.events[len(broker.events)-1].Status)
+	})
+
+	t.Run("should not publish finished event while execution is running", func(t *testing.T) {
+		execution := NewExecution(newTestWorkflow())
+		step, _ := execution.Workflow.Steps.Head()
+		execution.StepRequested(step, REQUEST_ACTION_TYPE)
+
+		progress := ProgressEvents(execution, execution.Transitions)
+
+		require.Len(t, progress, 1)
+		assert.Equal(t, ExecutionStatusRunning, progress[0].Status)
+	})
+}
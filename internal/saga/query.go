@@ -0,0 +1,127 @@
+package saga
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ExecutionFilter selects the executions returned by ExecutionRepository.List.
+// Zero value fields are not used to filter.
+type ExecutionFilter struct {
+	WorkflowName string
+	Status       ExecutionStatus
+	// CreatedFrom is inclusive and CreatedTo is exclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// CorrelationID is the execution ID, which is sent as the correlation ID of the step commands.
+	CorrelationID *uuid.UUID
+	BusinessKey   string
+	// After is the cursor of the last execution of the previous page.
+	After *Cursor
+	// Limit is the page size. Values out of (0, MaxPageSize] are replaced by DefaultPageSize or MaxPageSize.
+	Limit int
+}
+
+// PageSize returns the filter limit bounded to the accepted page sizes.
+func (f ExecutionFilter) PageSize() int {
+	if f.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return f.Limit
+}
+
+// ExecutionPage is a page of executions, newest created first.
+type ExecutionPage struct {
+	Executions []*Execution
+	// Next is the cursor of the next page, nil when this is the last page.
+	Next *Cursor
+}
+
+// Cursor points to an execution in the listing order.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the cursor pointing to execution.
+func CursorOf(execution *Execution) *Cursor {
+	return &Cursor{
+		CreatedAt: execution.CreatedAt,
+		ID:        execution.ID,
+	}
+}
+
+// Encode returns the opaque representation of the cursor sent to API clients.
+func (c *Cursor) Encode() string {
+	raw := fmt.Sprintf("%s|%s", c.CreatedAt.UTC().Format(time.RFC3339Nano), c.ID.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Cursor.Encode.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: t, ID: uid}, nil
+}
+
+// Before returns true if the execution comes after the cursor in the listing order.
+func (c *Cursor) Before(execution *Execution) bool {
+	if execution.CreatedAt.Equal(c.CreatedAt) {
+		return strings.Compare(execution.ID.String(), c.ID.String()) < 0
+	}
+	return execution.CreatedAt.Before(c.CreatedAt)
+}
+
+// Matches returns true if execution is selected by the filter, ignoring the cursor and limit.
+func (f ExecutionFilter) Matches(execution *Execution) bool {
+	if f.WorkflowName != "" && execution.Workflow.Name != f.WorkflowName {
+		return false
+	}
+	if f.Status != "" && execution.Status != f.Status {
+		return false
+	}
+	if f.CreatedFrom != nil && execution.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && !execution.CreatedAt.Before(*f.CreatedTo) {
+		return false
+	}
+	if f.CorrelationID != nil && execution.ID != *f.CorrelationID {
+		return false
+	}
+	if f.BusinessKey != "" && execution.BusinessKey != f.BusinessKey {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package saga
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should decode an encoded cursor", func(t *testing.T) {
+		cursor := &Cursor{CreatedAt: time.Date(2024, 6, 10, 22, 56, 53, 123456000, time.UTC), ID: uuid.New()}
+
+		decoded, err := DecodeCursor(cursor.Encode())
+
+		require.NoError(t, err)
+		assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+		assert.Equal(t, cursor.ID, decoded.ID)
+	})
+
+	t.Run("should return error for invalid cursors", func(t *testing.T) {
+		for _, encoded := range []string{"%%%", "bm9waXBl", "MjAyNHxub3QtYS11dWlk"} {
+			_, err := DecodeCursor(encoded)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		}
+	})
+
+	t.Run("should order executions by creation date then id, newest first", func(t *testing.T) {
+		now := time.Now()
+		cursor := &Cursor{CreatedAt: now, ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}
+
+		assert.True(t, cursor.Before(&Execution{CreatedAt: now.Add(-time.Second), ID: uuid.New()}))
+		assert.True(t, cursor.Before(&Execution{CreatedAt: now, ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}))
+		assert.False(t, cursor.Before(&Execution{CreatedAt: now, ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}))
+		assert.False(t, cursor.Before(&Execution{CreatedAt: now.Add(time.Second), ID: uuid.New()}))
+	})
+}
+
+func TestExecutionFilter(t *testing.T) {
+	t.Run("should bound the page size", func(t *testing.T) {
+		assert.Equal(t, DefaultPageSize, ExecutionFilter{}.PageSize())
+		assert.Equal(t, 5, ExecutionFilter{Limit: 5}.PageSize())
+		assert.Equal(t, MaxPageSize, ExecutionFilter{Limit: MaxPageSize + 1}.PageSize())
+	})
+
+	t.Run("should match executions by every field set", func(t *testing.T) {
+		workflow := newTestWorkflow()
+		workflow.BusinessKeyPath = "customer.id"
+		execution := NewExecution(workflow)
+		execution.BusinessKey = workflow.BusinessKey(map[string]interface{}{"customer": map[string]interface{}{"id": "123"}})
+		from := execution.CreatedAt.Add(-time.Minute)
+		to := execution.CreatedAt.Add(time.Minute)
+
+		assert.Equal(t, "123", execution.BusinessKey)
+		assert.True(t, ExecutionFilter{}.Matches(execution))
+		assert.True(t, ExecutionFilter{
+			WorkflowName:  workflow.Name,
+			Status:        ExecutionStatusRunning,
+			CreatedFrom:   &from,
+			CreatedTo:     &to,
+			CorrelationID: &execution.ID,
+			BusinessKey:   "123",
+		}.Matches(execution))
+		assert.False(t, ExecutionFilter{Status: ExecutionStatusCompleted}.Matches(execution))
+		assert.False(t, ExecutionFilter{BusinessKey: "456"}.Matches(execution))
+		assert.False(t, ExecutionFilter{CreatedTo: &from}.Matches(execution))
+	})
+}
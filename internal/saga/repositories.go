@@ -1,13 +1,35 @@
 package saga
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrDuplicateIdempotencyKey = errors.New("idempotency key is already used by another execution")
+	// ErrConcurrentUpdate is returned when saving an execution that was saved by someone else since it was read.
+	ErrConcurrentUpdate = errors.New("execution was updated concurrently")
+)
 
 type ExecutionRepository interface {
-	Insert(ctx context.Context, execution *Execution) error
+	// Insert saves a new execution along with its Notifications.
+	// It returns ErrDuplicateIdempotencyKey if another execution of the workflow retains the same idempotency key
+	// and ErrTooManyExecutions if the workflow already has maxActive active executions, see ExecutionStatus.IsActive.
+	// The count and the insert are atomic, so concurrent inserts can't exceed maxActive. Zero means no limit.
+	Insert(ctx context.Context, execution *Execution, maxActive int) error
 	Find(ctx context.Context, globalID string) (*Execution, error)
+	// Save updates the execution, increments its Version and saves its Notifications in the same transaction.
+	// It returns ErrConcurrentUpdate if the stored execution is no longer at the version it was read with.
 	Save(ctx context.Context, execution *Execution) error
+	// List returns a page of the executions selected by filter, newest created first.
+	List(ctx context.Context, filter ExecutionFilter) (ExecutionPage, error)
+	// FindByIdempotencyKey returns the execution of the workflow retaining the idempotency key
+	// or an empty execution if there is none.
+	FindByIdempotencyKey(ctx context.Context, workflowName string, key string) (*Execution, error)
 }
 
 type WorkflowRepository interface {
 	Find(ctx context.Context, name string) (*Workflow, error)
+	// List returns the registered workflows ordered by name.
+	List(ctx context.Context) ([]*Workflow, error)
 }
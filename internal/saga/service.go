@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/bmviniciuss/sagas-golang/pkg/events"
 	"github.com/google/uuid"
@@ -15,15 +16,63 @@ type Publisher interface {
 	Publish(ctx context.Context, destination string, data []byte) error
 }
 
+const (
+	DefaultIdempotencyRetention = time.Hour * 24
+)
+
+var (
+	ErrNoIntervention = errors.New("execution does not need intervention")
+	ErrNotCancellable = errors.New("execution is not running")
+	ErrNotResumable   = errors.New("execution is finished or needs intervention")
+	ErrStepNotFound   = errors.New("step not found in workflow")
+	// ErrStepNotCompensable is returned when a compensating execution is resumed from a step without compensation.
+	ErrStepNotCompensable = errors.New("step is not compensable")
+	// ErrTooManyExecutions is returned when starting an execution of a workflow that reached the maximum active executions.
+	ErrTooManyExecutions = errors.New("workflow has too many active executions")
+)
+
+// StartOptions customizes how an execution is started.
+type StartOptions struct {
+	// IdempotencyKey identifies the request starting the execution.
+	// While it is retained, starting the same workflow with the same key returns the ID of the execution it started.
+	IdempotencyKey string
+	// CallbackURL receives the webhook notifications of the execution instead of the workflow CallbackURL.
+	CallbackURL string
+	// StartedBy is the subject of the authenticated principal starting the execution.
+	StartedBy string
+}
+
 type ServicePort interface {
-	Start(ctx context.Context, workflow *Workflow, data map[string]interface{}) (*uuid.UUID, error)
+	Start(ctx context.Context, workflow *Workflow, data map[string]interface{}, opts StartOptions) (*uuid.UUID, error)
 	ProcessMessage(ctx context.Context, message *events.Event, execution *Execution) error
+	// RetryCompensation requests again the compensation that required intervention.
+	RetryCompensation(ctx context.Context, execution *Execution) error
+	// ResolveIntervention marks the compensation that required intervention as done by the operator
+	// and resumes compensating the previous steps.
+	ResolveIntervention(ctx context.Context, execution *Execution) error
+	// ForceComplete finishes an execution that required intervention without running any other step.
+	ForceComplete(ctx context.Context, execution *Execution) error
+	// Cancel stops a running execution and compensates the steps it already completed.
+	Cancel(ctx context.Context, execution *Execution) error
+	// RetryStep publishes again the command of the step the execution is waiting for,
+	// rebuilding it with the step PayloadBuilder.
+	RetryStep(ctx context.Context, execution *Execution) error
+	// ResumeFrom publishes the command of the given step and makes the execution wait for its reply.
+	// A running execution requests the step, a compensating one requests its compensation.
+	ResumeFrom(ctx context.Context, execution *Execution, stepName string) error
 }
 
 type Service struct {
-	logger              *zap.SugaredLogger
-	executionRepository ExecutionRepository
-	publisher           Publisher
+	logger               *zap.SugaredLogger
+	executionRepository  ExecutionRepository
+	publisher            Publisher
+	idempotencyRetention time.Duration
+	observers            []Observer
+	notifiers            []Notifier
+	// redactedFields are the dot separated paths of the execution state hidden from the outcome events.
+	redactedFields []string
+	// maxActiveExecutions is the maximum number of active executions of each workflow, unlimited if zero.
+	maxActiveExecutions int
 }
 
 var (
@@ -36,55 +85,140 @@ func NewService(
 	publisher Publisher,
 ) *Service {
 	return &Service{
-		logger:              logger,
-		executionRepository: executionRepository,
-		publisher:           publisher,
+		logger:               logger,
+		executionRepository:  executionRepository,
+		publisher:            publisher,
+		idempotencyRetention: DefaultIdempotencyRetention,
 	}
 }
 
-func (service *Service) Start(ctx context.Context, workflow *Workflow, data map[string]interface{}) (*uuid.UUID, error) {
+// WithIdempotencyRetention sets for how long the idempotency key of a started execution is retained.
+func (service *Service) WithIdempotencyRetention(retention time.Duration) *Service {
+	service.idempotencyRetention = retention
+	return service
+}
+
+// WithObserver registers an observer notified every time an execution is saved.
+func (service *Service) WithObserver(observer Observer) *Service {
+	service.observers = append(service.observers, observer)
+	return service
+}
+
+// WithNotifier registers a notifier whose notifications are saved along with every save of an execution.
+func (service *Service) WithNotifier(notifier Notifier) *Service {
+	service.notifiers = append(service.notifiers, notifier)
+	return service
+}
+
+// WithRedactedFields sets the execution state fields hidden from the events published to the workflow reply channel.
+func (service *Service) WithRedactedFields(paths []string) *Service {
+	service.redactedFields = paths
+	return service
+}
+
+// WithMaxActiveExecutions sets the maximum number of active executions of each workflow.
+// Starting more returns ErrTooManyExecutions. Zero means unlimited.
+func (service *Service) WithMaxActiveExecutions(limit int) *Service {
+	service.maxActiveExecutions = limit
+	return service
+}
+
+func (service *Service) Start(ctx context.Context, workflow *Workflow, data map[string]interface{}, opts StartOptions) (*uuid.UUID, error) {
 	lggr := service.logger
 	lggr.Info("Starting workflow")
+	if workflow.Steps == nil || workflow.Steps.Len() == 0 {
+		lggr.Error("Workflow has no steps")
+		return nil, fmt.Errorf("%w: workflow [%s] has no steps", ErrInvalidWorkflow, workflow.Name)
+	}
+	if opts.IdempotencyKey != "" {
+		existing, err := service.executionRepository.FindByIdempotencyKey(ctx, workflow.Name, opts.IdempotencyKey)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error finding execution by idempotency key")
+			return nil, err
+		}
+		if !existing.IsEmpty() {
+			lggr.Infof("Idempotency key was already used by execution [%s]", existing.ID)
+			err = service.republishFirstStep(ctx, existing)
+			if err != nil {
+				return nil, err
+			}
+			return &existing.ID, nil
+		}
+	}
+
 	execution := NewExecution(workflow)
 	lggr.Infof("Starting saga with ID: %s", execution.ID.String())
 	execution.SetState("input", data)
-	err := service.executionRepository.Insert(ctx, execution)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error while saving execution")
-		return nil, err
+	if opts.IdempotencyKey != "" {
+		execution.IdempotencyKey = opts.IdempotencyKey
+		execution.IdempotencyExpiresAt = execution.CreatedAt.Add(service.idempotencyRetention)
 	}
-
-	firstStep, ok := execution.Workflow.Steps.Head()
-	if !ok {
-		lggr.Info("There are no steps to process. Successfully finished workflow.")
-		return nil, nil
-	}
-	actionType := REQUEST_ACTION_TYPE
-	event, err := firstStep.PayloadBuilder.Build(ctx, execution, actionType)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error while building payload")
+	execution.BusinessKey = workflow.BusinessKey(data)
+	execution.CallbackURL = opts.CallbackURL
+	execution.StartedBy = opts.StartedBy
+	firstStep, _ := execution.Workflow.Steps.Head()
+	execution.StepRequested(firstStep, REQUEST_ACTION_TYPE)
+	service.buildNotifications(execution)
+	err := service.executionRepository.Insert(ctx, execution, service.maxActiveExecutions)
+	execution.notifications = nil
+	if errors.Is(err, ErrTooManyExecutions) {
+		lggr.Warnf("Workflow has reached the maximum of %d active executions", service.maxActiveExecutions)
 		return nil, err
 	}
-
-	eventJSON, err := json.Marshal(event)
+	if errors.Is(err, ErrDuplicateIdempotencyKey) {
+		// a concurrent request with the same key started the execution first
+		existing, err := service.executionRepository.FindByIdempotencyKey(ctx, workflow.Name, opts.IdempotencyKey)
+		if err != nil {
+			lggr.With(zap.Error(err)).Error("Got error finding execution by idempotency key")
+			return nil, err
+		}
+		if existing.IsEmpty() {
+			return nil, ErrDuplicateIdempotencyKey
+		}
+		lggr.Infof("Idempotency key was already used by execution [%s]", existing.ID)
+		return &existing.ID, nil
+	}
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error while marshalling event data")
+		lggr.With(zap.Error(err)).Error("Got error while saving execution")
 		return nil, err
 	}
+	service.notify(execution)
 
-	err = service.publisher.Publish(ctx, firstStep.Topics.Request, eventJSON)
+	err = service.publishStep(ctx, execution, NextStep{Step: firstStep, ActionType: REQUEST_ACTION_TYPE})
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error publishing message to destination")
 		return nil, err
 	}
 	lggr.Info("Successfully started workflow")
 	return &execution.ID, nil
 }
 
-// TODO: add unit tests
+// republishFirstStep publishes the command of the first step again while the execution waits for its reply.
+// The request that started the execution may have failed to publish it after inserting the execution,
+// so a retry of the request can't assume it was published.
+func (service *Service) republishFirstStep(ctx context.Context, execution *Execution) error {
+	step, ok := execution.awaitingFirstReply()
+	if !ok {
+		return nil
+	}
+	service.logger.Infof("Publishing the request of step [%s] of execution [%s] again", step.Name, execution.ID)
+	return service.publishStep(ctx, execution, NextStep{Step: step, ActionType: REQUEST_ACTION_TYPE})
+}
+
 func (service *Service) ProcessMessage(ctx context.Context, event *events.Event, execution *Execution) (err error) {
 	lggr := service.logger
 	lggr.Infof("Saga Service started processing message with event: %s", event.Type)
+	if step, ok := service.cancelledStepOf(execution, event); ok {
+		return service.handleCancelledStepReply(ctx, execution, step, event)
+	}
+	if execution.OutcomePending() {
+		lggr.Infof("Execution is [%s] but its outcome was not published. Publishing it", execution.Status)
+		return service.publishOutcome(ctx, execution)
+	}
+	if execution.Status.IsFinished() || execution.NeedsIntervention() {
+		lggr.Infof("Execution is [%s]. Message will be ignored", execution.Status)
+		return nil
+	}
+
 	workflow := execution.Workflow
 	currentStep, ok := workflow.Steps.GetStepFromServiceEvent(event.Origin, event.Type)
 	if !ok {
@@ -94,10 +228,14 @@ func (service *Service) ProcessMessage(ctx context.Context, event *events.Event,
 	currenctStepResponseKey := fmt.Sprintf("%s.response.%s", currentStep.Name, event.Type)
 	// Saving response data to execution state
 	execution.SetState(currenctStepResponseKey, event.Data)
-	err = service.executionRepository.Save(ctx, execution)
-	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error saving execution state")
-		return err
+	execution.StepReplied(currentStep, event)
+
+	if execution.Status == ExecutionStatusCancelling && (currentStep.IsSuccess(event.Type) || currentStep.IsFailure(event.Type)) {
+		return service.ignoreCancelledReply(ctx, execution, currentStep, event)
+	}
+
+	if currentStep.IsCompensationFailure(event.Type) {
+		return service.handleCompensationFailure(ctx, execution, currentStep, event)
 	}
 
 	// Aquring next step
@@ -106,20 +244,308 @@ func (service *Service) ProcessMessage(ctx context.Context, event *events.Event,
 		lggr.With(zap.Error(err)).Error("Got error while getting next step")
 		return err
 	}
+	if currentStep.IsFailure(event.Type) {
+		execution.Status = execution.CompensatingStatus()
+	}
 	if nextStep.Step == nil {
-		lggr.Info("There are no more steps to process. Successfully finished workflow.")
-		return nil
+		execution.Status = finalStatus(execution.Status)
+	} else {
+		execution.StepRequested(nextStep.Step, nextStep.ActionType)
+	}
+	err = service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	if nextStep.Step == nil {
+		lggr.Infof("There are no more steps to process. Workflow finished as [%s].", execution.Status)
+		return service.publishOutcome(ctx, execution)
 	}
 	lggr.Infof("Next step: %s", nextStep.Step.Name)
 
-	// building next event event
-	nextEvent, err := nextStep.Step.PayloadBuilder.Build(ctx, execution, nextStep.ActionType)
+	err = service.publishStep(ctx, execution, nextStep)
+	if err != nil {
+		return err
+	}
+
+	lggr.Infof("Successfully processed message and produce")
+	return nil
+}
+
+// handleCompensationFailure requests the compensation of step again or, once its attempts are exhausted,
+// leaves the execution waiting for an operator action.
+func (service *Service) handleCompensationFailure(ctx context.Context, execution *Execution, step *Step, event *events.Event) error {
+	lggr := service.logger
+	needsIntervention := execution.CompensationFailed(step.Name, eventError(event))
+	if !needsIntervention {
+		execution.StepRequested(step, COMPESATION_REQUEST_ACTION_TYPE)
+	}
+	err := service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	if needsIntervention {
+		lggr.Warnf("Compensation of step [%s] failed %d times. Execution needs intervention", step.Name, execution.Intervention.Attempts)
+		return nil
+	}
+	lggr.Infof("Compensation of step [%s] failed. Requesting it again", step.Name)
+	return service.publishStep(ctx, execution, NextStep{Step: step, ActionType: COMPESATION_REQUEST_ACTION_TYPE})
+}
+
+func (service *Service) RetryCompensation(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	step, err := service.interventionStep(execution)
+	if err != nil {
+		return err
+	}
+	lggr.Infof("Retrying compensation of step [%s] of execution [%s]", step.Name, execution.ID)
+	execution.ClearIntervention()
+	execution.Status = execution.CompensatingStatus()
+	execution.StepRequested(step, COMPESATION_REQUEST_ACTION_TYPE)
+	err = service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	return service.publishStep(ctx, execution, NextStep{Step: step, ActionType: COMPESATION_REQUEST_ACTION_TYPE})
+}
+
+func (service *Service) ResolveIntervention(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	step, err := service.interventionStep(execution)
+	if err != nil {
+		return err
+	}
+	lggr.Infof("Compensation of step [%s] of execution [%s] was resolved by an operator", step.Name, execution.ID)
+	execution.ClearIntervention()
+	execution.StepResolved(step)
+	execution.Status = execution.CompensatingStatus()
+	nextStep, err := execution.Workflow.GetNextStep(ctx, step, step.EventTypes.Compensation)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error while getting next step")
+		return err
+	}
+	if nextStep.Step == nil {
+		execution.Status = finalStatus(execution.Status)
+	} else {
+		execution.StepRequested(nextStep.Step, nextStep.ActionType)
+	}
+	err = service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	if nextStep.Step == nil {
+		lggr.Infof("There are no more steps to compensate. Workflow finished as [%s].", execution.Status)
+		return service.publishOutcome(ctx, execution)
+	}
+	return service.publishStep(ctx, execution, nextStep)
+}
+
+func (service *Service) ForceComplete(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	if _, err := service.interventionStep(execution); err != nil {
+		return err
+	}
+	lggr.Infof("Execution [%s] was force completed by an operator", execution.ID)
+	execution.ClearIntervention()
+	execution.Status = ExecutionStatusForceCompleted
+	err := service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	return service.publishOutcome(ctx, execution)
+}
+
+func (service *Service) Cancel(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	if execution.Status != ExecutionStatusRunning {
+		return ErrNotCancellable
+	}
+	step, ok := execution.Workflow.Steps.GetStep(execution.CurrentStep)
+	if !ok {
+		return fmt.Errorf("step [%s] not found in workflow [%s]", execution.CurrentStep, execution.Workflow.Name)
+	}
+	lggr.Infof("Cancelling execution [%s] while waiting for step [%s]", execution.ID, step.Name)
+	execution.StepCancelled(step)
+	execution.Status = ExecutionStatusCancelling
+
+	// the pending step is handled as failed, so the compensation starts from the step completed before it
+	nextStep, err := execution.Workflow.GetNextStep(ctx, step, step.EventTypes.Failure)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error while getting next step")
+		return err
+	}
+	if nextStep.Step == nil {
+		execution.Status = finalStatus(execution.Status)
+	} else {
+		execution.StepRequested(nextStep.Step, nextStep.ActionType)
+	}
+	err = service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	if nextStep.Step == nil {
+		lggr.Infof("There are no steps to compensate. Workflow finished as [%s].", execution.Status)
+		return service.publishOutcome(ctx, execution)
+	}
+	return service.publishStep(ctx, execution, nextStep)
+}
+
+// RetryStep publishes the command of the current step again or, when the execution finished
+// but its outcome was not published, the outcome.
+func (service *Service) RetryStep(ctx context.Context, execution *Execution) error {
+	if execution.OutcomePending() {
+		service.logger.Infof("Publishing the outcome of execution [%s] again", execution.ID)
+		return service.publishOutcome(ctx, execution)
+	}
+	return service.ResumeFrom(ctx, execution, execution.CurrentStep)
+}
+
+func (service *Service) ResumeFrom(ctx context.Context, execution *Execution, stepName string) error {
+	lggr := service.logger
+	if execution.Status.IsFinished() || execution.Status == ExecutionStatusNeedsIntervention {
+		return ErrNotResumable
+	}
+	step, ok := execution.Workflow.Steps.GetStep(stepName)
+	if !ok {
+		return ErrStepNotFound
+	}
+	nextStep := NextStep{Step: step, ActionType: REQUEST_ACTION_TYPE}
+	if execution.Status != ExecutionStatusRunning {
+		if !step.Compensable {
+			return ErrStepNotCompensable
+		}
+		nextStep.ActionType = COMPESATION_REQUEST_ACTION_TYPE
+	}
+	lggr.Infof("Resuming execution [%s] with [%s] of step [%s]", execution.ID, nextStep.ActionType, step.Name)
+	execution.StepRequested(step, nextStep.ActionType)
+	err := service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	return service.publishStep(ctx, execution, nextStep)
+}
+
+// ignoreCancelledReply records a forward progress reply received after the execution was cancelled without acting on it.
+func (service *Service) ignoreCancelledReply(ctx context.Context, execution *Execution, step *Step, event *events.Event) error {
+	lggr := service.logger
+	lggr.Infof("Execution [%s] is cancelling. Reply [%s] will be ignored", execution.ID, event.Type)
+	err := service.save(ctx, execution)
 	if err != nil {
-		lggr.With(zap.Error(err)).Error("Got error building next step event")
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
 		return err
 	}
+	return nil
+}
+
+// cancelledStepOf returns the step the execution was waiting for when it was cancelled, if event is a reply of it.
+// Its replies arrive after the cancellation, even once the execution is cancelled, and must not move the execution.
+func (service *Service) cancelledStepOf(execution *Execution, event *events.Event) (*Step, bool) {
+	if execution.Status != ExecutionStatusCancelling && execution.Status != ExecutionStatusCancelled {
+		return nil, false
+	}
+	name, ok := execution.cancelledStep()
+	if !ok {
+		return nil, false
+	}
+	step, ok := execution.Workflow.Steps.GetStepFromServiceEvent(event.Origin, event.Type)
+	if !ok || step.Name != name {
+		return nil, false
+	}
+	return step, true
+}
 
-	eventJSON, err := json.Marshal(nextEvent)
+// handleCancelledStepReply records a reply of the step the execution was cancelled while waiting for.
+// A compensable step that succeeded anyway is compensated, so its effects do not outlive the cancelled execution.
+func (service *Service) handleCancelledStepReply(ctx context.Context, execution *Execution, step *Step, event *events.Event) error {
+	lggr := service.logger
+	execution.SetState(fmt.Sprintf("%s.response.%s", step.Name, event.Type), event.Data)
+	execution.StepReplied(step, event)
+	compensate := step.Compensable && step.IsSuccess(event.Type)
+	switch {
+	case compensate:
+		lggr.Warnf("Step [%s] succeeded after execution [%s] was cancelled. Requesting its compensation", step.Name, execution.ID)
+		execution.LateStepCompensationRequested(step)
+	case step.IsCompensationFailure(event.Type):
+		lggr.Errorf("Compensation of step [%s], which succeeded after execution [%s] was cancelled, failed: %s", step.Name, execution.ID, eventError(event))
+	default:
+		lggr.Infof("Execution [%s] was cancelled while waiting for step [%s]. Reply [%s] was recorded", execution.ID, step.Name, event.Type)
+	}
+	err := service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
+	if compensate {
+		err = service.publishStep(ctx, execution, NextStep{Step: step, ActionType: COMPESATION_REQUEST_ACTION_TYPE})
+		if err != nil {
+			return err
+		}
+	}
+	if execution.OutcomePending() {
+		return service.publishOutcome(ctx, execution)
+	}
+	return nil
+}
+
+func (service *Service) interventionStep(execution *Execution) (*Step, error) {
+	if !execution.NeedsIntervention() {
+		return nil, ErrNoIntervention
+	}
+	step, ok := execution.Workflow.Steps.GetStep(execution.Intervention.Step)
+	if !ok {
+		return nil, fmt.Errorf("step [%s] not found in workflow [%s]", execution.Intervention.Step, execution.Workflow.Name)
+	}
+	return step, nil
+}
+
+// save persists the execution along with its notifications and notifies the observers.
+func (service *Service) save(ctx context.Context, execution *Execution) error {
+	service.buildNotifications(execution)
+	err := service.executionRepository.Save(ctx, execution)
+	execution.notifications = nil
+	if err != nil {
+		return err
+	}
+	service.notify(execution)
+	return nil
+}
+
+// buildNotifications sets the notifications of the unsaved transitions of execution.
+func (service *Service) buildNotifications(execution *Execution) {
+	execution.notifications = nil
+	for _, notifier := range service.notifiers {
+		notifications, err := notifier.Notifications(execution, execution.unsaved)
+		if err != nil {
+			// a notification that can't be built must not stop the execution
+			service.logger.With(zap.Error(err)).Errorf("Got error building notifications of execution [%s]", execution.ID)
+			continue
+		}
+		execution.notifications = append(execution.notifications, notifications...)
+	}
+}
+
+func (service *Service) notify(execution *Execution) {
+	transitions := execution.takeUnsaved()
+	for _, observer := range service.observers {
+		observer.ExecutionUpdated(execution, transitions)
+	}
+}
+
+func (service *Service) publishStep(ctx context.Context, execution *Execution, nextStep NextStep) error {
+	lggr := service.logger
+	event, err := nextStep.Step.PayloadBuilder.Build(ctx, execution, nextStep.ActionType)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error building step event")
+		return err
+	}
+
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		lggr.With(zap.Error(err)).Error("Got error while marshalling event data")
 		return err
@@ -130,7 +556,65 @@ func (service *Service) ProcessMessage(ctx context.Context, event *events.Event,
 		lggr.With(zap.Error(err)).Error("Got error publishing message to destination")
 		return err
 	}
+	return nil
+}
 
-	lggr.Infof("Successfully processed message and produce")
+// publishOutcome publishes the outcome of a finished execution to its workflow reply channel, if it has one,
+// and saves that it was published. Until then the outcome is published again by the next message of the execution.
+func (service *Service) publishOutcome(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	err := service.publishOutcomeEvent(ctx, execution)
+	if err != nil {
+		return err
+	}
+	execution.OutcomePublished = true
+	err = service.save(ctx, execution)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error saving execution state")
+		return err
+	}
 	return nil
 }
+
+func (service *Service) publishOutcomeEvent(ctx context.Context, execution *Execution) error {
+	lggr := service.logger
+	replyChannel := execution.Workflow.ReplyChannel
+	if replyChannel == "" {
+		return nil
+	}
+	event, err := NewOutcomeEvent(execution, service.redactedFields)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error building outcome event")
+		return err
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error while marshalling outcome event")
+		return err
+	}
+	err = service.publisher.Publish(ctx, replyChannel, eventJSON)
+	if err != nil {
+		lggr.With(zap.Error(err)).Error("Got error publishing outcome to reply channel")
+		return err
+	}
+	lggr.Infof("Published [%s] to reply channel [%s]", event.Type, replyChannel)
+	return nil
+}
+
+// finalStatus returns the status of an execution with no more steps to run.
+func finalStatus(status ExecutionStatus) ExecutionStatus {
+	switch status {
+	case ExecutionStatusCompensating:
+		return ExecutionStatusCompensated
+	case ExecutionStatusCancelling:
+		return ExecutionStatusCancelled
+	}
+	return ExecutionStatusCompleted
+}
+
+func eventError(event *events.Event) string {
+	if reason, ok := event.Data["error"].(string); ok {
+		return reason
+	}
+	return fmt.Sprintf("received [%s]", event.Type)
+}
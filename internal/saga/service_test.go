@@ -0,0 +1,661 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type executionRepositoryMock struct {
+	mu   sync.Mutex
+	data map[string]*Execution
+	// notifications are the notifications saved along with the executions.
+	notifications []Notification
+}
+
+func (r *executionRepositoryMock) Insert(ctx context.Context, execution *Execution, maxActive int) error {
+	if execution.IdempotencyKey != "" {
+		existing, err := r.FindByIdempotencyKey(ctx, execution.Workflow.Name, execution.IdempotencyKey)
+		if err != nil {
+			return err
+		}
+		if !existing.IsEmpty() {
+			return ErrDuplicateIdempotencyKey
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		r.data = make(map[string]*Execution)
+	}
+	if maxActive > 0 && r.countActive(execution.Workflow.Name) >= maxActive {
+		return ErrTooManyExecutions
+	}
+	execution.Version = 1
+	r.data[execution.ID.String()] = execution
+	r.notifications = append(r.notifications, execution.Notifications()...)
+	return nil
+}
+
+func (r *executionRepositoryMock) FindByIdempotencyKey(_ context.Context, workflowName string, key string) (*Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, execution := range r.data {
+		if execution.Workflow.Name == workflowName && execution.IdempotencyKey == key && time.Now().Before(execution.IdempotencyExpiresAt) {
+			return execution, nil
+		}
+	}
+	return &Execution{}, nil
+}
+
+func (r *executionRepositoryMock) Find(_ context.Context, globalID string) (*Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if execution, ok := r.data[globalID]; ok {
+		return execution, nil
+	}
+	return &Execution{}, nil
+}
+
+func (r *executionRepositoryMock) Save(_ context.Context, execution *Execution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		r.data = make(map[string]*Execution)
+	}
+	if stored, ok := r.data[execution.ID.String()]; ok && stored.Version != execution.Version {
+		return ErrConcurrentUpdate
+	}
+	execution.Version++
+	r.data[execution.ID.String()] = execution
+	r.notifications = append(r.notifications, execution.Notifications()...)
+	return nil
+}
+
+func (r *executionRepositoryMock) List(_ context.Context, filter ExecutionFilter) (ExecutionPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	executions := make([]*Execution, 0)
+	for _, execution := range r.data {
+		if filter.Matches(execution) {
+			executions = append(executions, execution)
+		}
+	}
+	return ExecutionPage{Executions: executions}, nil
+}
+
+func (r *executionRepositoryMock) countActive(workflowName string) int {
+	count := 0
+	for _, execution := range r.data {
+		if execution.Workflow.Name == workflowName && execution.Status.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
+type publisherMock struct {
+	destinations []string
+	// err is returned by Publish without publishing while it is set.
+	err error
+}
+
+func (p *publisherMock) Publish(_ context.Context, destination string, _ []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.destinations = append(p.destinations, destination)
+	return nil
+}
+
+func newTestWorkflow() *Workflow {
+	return &Workflow{
+		Name: "create_order_v1",
+		Steps: NewStepList(
+			&StepData{Name: "create_order", Compensable: true, PayloadBuilder: &payloadBuilderMock{}, StepContract: newContract()},
+			&StepData{Name: "verify_customer", PayloadBuilder: &payloadBuilderMock{}, StepContract: StepContract{
+				ServiceName: "customers",
+				EventTypes: EventTypes{
+					Request: "verify_customer",
+					Success: "customer_verified",
+					Failure: "customer_verification_failed",
+				},
+				Topics: Topics{
+					Request:  "service.customers.request",
+					Response: "service.customers.events",
+				},
+			}},
+		),
+		MaxCompensationAttempts: 2,
+	}
+}
+
+func TestService_ProcessMessage(t *testing.T) {
+	ctx := context.Background()
+	newService := func() (*Service, *executionRepositoryMock, *publisherMock) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{}
+		return NewService(zap.NewNop().Sugar(), repo, publisher), repo, publisher
+	}
+	reply := func(eventType, origin string, data map[string]interface{}) *events.Event {
+		return events.NewEvent(eventType, origin, data)
+	}
+
+	t.Run("should complete execution when last step succeeds", func(t *testing.T) {
+		service, _, _ := newService()
+		execution := NewExecution(newTestWorkflow())
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("order_created", "orders", nil), execution))
+		assert.Equal(t, ExecutionStatusRunning, execution.Status)
+		require.NoError(t, service.ProcessMessage(ctx, reply("customer_verified", "customers", nil), execution))
+		assert.Equal(t, ExecutionStatusCompleted, execution.Status)
+		assert.Equal(t, "verify_customer", execution.CurrentStep)
+		assert.Len(t, execution.Transitions, 3)
+	})
+
+	t.Run("should compensate previous steps when a step fails", func(t *testing.T) {
+		service, _, publisher := newService()
+		execution := NewExecution(newTestWorkflow())
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("customer_verification_failed", "customers", nil), execution))
+		assert.Equal(t, ExecutionStatusCompensating, execution.Status)
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("order_rejected", "orders", nil), execution))
+		assert.Equal(t, ExecutionStatusCompensated, execution.Status)
+	})
+
+	t.Run("should request compensation again until attempts are exhausted", func(t *testing.T) {
+		service, repo, publisher := newService()
+		execution := NewExecution(newTestWorkflow())
+		execution.Status = ExecutionStatusCompensating
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("order_rejection_failed", "orders", map[string]interface{}{"error": "boom"}), execution))
+		assert.Equal(t, ExecutionStatusCompensating, execution.Status)
+		assert.Len(t, publisher.destinations, 1)
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("order_rejection_failed", "orders", map[string]interface{}{"error": "boom"}), execution))
+		assert.Equal(t, ExecutionStatusNeedsIntervention, execution.Status)
+		assert.Len(t, publisher.destinations, 1)
+		require.NotNil(t, execution.Intervention)
+		assert.Equal(t, "create_order", execution.Intervention.Step)
+		assert.Equal(t, "boom", execution.Intervention.Error)
+		assert.Equal(t, 2, execution.Intervention.Attempts)
+
+		pending, err := repo.List(ctx, ExecutionFilter{Status: ExecutionStatusNeedsIntervention})
+		require.NoError(t, err)
+		assert.Len(t, pending.Executions, 1)
+
+		require.NoError(t, service.ProcessMessage(ctx, reply("order_rejected", "orders", nil), execution))
+		assert.Equal(t, ExecutionStatusNeedsIntervention, execution.Status)
+	})
+}
+
+func TestService_Interventions(t *testing.T) {
+	ctx := context.Background()
+	newStuckExecution := func() *Execution {
+		execution := NewExecution(newTestWorkflow())
+		execution.Status = ExecutionStatusCompensating
+		execution.CompensationFailed("create_order", "boom")
+		execution.CompensationFailed("create_order", "boom")
+		return execution
+	}
+
+	t.Run("should request the compensation again on retry", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newStuckExecution()
+
+		require.NoError(t, service.RetryCompensation(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusCompensating, execution.Status)
+		assert.Nil(t, execution.Intervention)
+		assert.Zero(t, execution.CompensationAttempts["create_order"])
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+	})
+
+	t.Run("should continue compensating when resolved", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newStuckExecution()
+
+		require.NoError(t, service.ResolveIntervention(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusCompensated, execution.Status)
+		assert.Nil(t, execution.Intervention)
+		assert.Empty(t, publisher.destinations)
+	})
+
+	t.Run("should finish execution when force completed", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newStuckExecution()
+
+		require.NoError(t, service.ForceComplete(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusForceCompleted, execution.Status)
+		assert.Nil(t, execution.Intervention)
+		assert.Empty(t, publisher.destinations)
+	})
+
+	t.Run("should return error when execution does not need intervention", func(t *testing.T) {
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{})
+		execution := NewExecution(newTestWorkflow())
+
+		assert.ErrorIs(t, service.RetryCompensation(ctx, execution), ErrNoIntervention)
+		assert.ErrorIs(t, service.ResolveIntervention(ctx, execution), ErrNoIntervention)
+		assert.ErrorIs(t, service.ForceComplete(ctx, execution), ErrNoIntervention)
+	})
+}
+
+func TestService_Start(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return the original execution when idempotency key is reused", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, publisher)
+		opts := StartOptions{IdempotencyKey: "key"}
+
+		first, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+		second, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, *first, *second)
+		assert.Len(t, repo.data, 1)
+	})
+
+	t.Run("should publish the first step again when the idempotency key is reused after publishing it failed", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{err: errors.New("broker is down")}
+		service := NewService(zap.NewNop().Sugar(), repo, publisher)
+		opts := StartOptions{IdempotencyKey: "key"}
+
+		_, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.Error(t, err)
+		assert.Len(t, repo.data, 1)
+		assert.Empty(t, publisher.destinations)
+
+		publisher.err = nil
+		_, err = service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+
+		assert.Len(t, repo.data, 1)
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+	})
+
+	t.Run("should not publish a step again when the idempotency key is reused after the step replied", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, publisher)
+		opts := StartOptions{IdempotencyKey: "key"}
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+		execution := repo.data[id.String()]
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), execution))
+		require.Len(t, publisher.destinations, 2)
+
+		_, err = service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+
+		assert.Len(t, publisher.destinations, 2)
+	})
+
+	t.Run("should start a new execution when idempotency key has expired", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{}).WithIdempotencyRetention(-time.Second)
+		opts := StartOptions{IdempotencyKey: "key"}
+
+		first, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+		second, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, opts)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, *first, *second)
+		assert.Len(t, repo.data, 2)
+	})
+
+	t.Run("should start a new execution for each request without idempotency key", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{})
+
+		first, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+		second, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, *first, *second)
+	})
+
+	t.Run("should return error when the workflow has no steps", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{})
+
+		id, err := service.Start(ctx, &Workflow{Name: "empty", Steps: NewStepList()}, map[string]interface{}{}, StartOptions{})
+
+		assert.ErrorIs(t, err, ErrInvalidWorkflow)
+		assert.Nil(t, id)
+		assert.Empty(t, repo.data)
+	})
+
+	t.Run("should record the principal that started the execution", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, &publisherMock{})
+
+		id, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{StartedBy: "partner"})
+		require.NoError(t, err)
+
+		execution, err := repo.Find(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, "partner", execution.StartedBy)
+	})
+
+	t.Run("should reject executions over the maximum active executions", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, publisher).WithMaxActiveExecutions(1)
+
+		first, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{IdempotencyKey: "key"})
+		require.NoError(t, err)
+		_, err = service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		assert.ErrorIs(t, err, ErrTooManyExecutions)
+		assert.Len(t, repo.data, 1)
+		assert.Len(t, publisher.destinations, 1)
+
+		again, err := service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{IdempotencyKey: "key"})
+		require.NoError(t, err, "retried requests should get the execution they started")
+		assert.Equal(t, *first, *again)
+
+		repo.data[first.String()].Status = ExecutionStatusCompleted
+		_, err = service.Start(ctx, newTestWorkflow(), map[string]interface{}{}, StartOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func TestService_Cancel(t *testing.T) {
+	ctx := context.Background()
+	newRunningExecution := func(step string) *Execution {
+		execution := NewExecution(newTestWorkflow())
+		current, _ := execution.Workflow.Steps.GetStep(step)
+		execution.StepRequested(current, REQUEST_ACTION_TYPE)
+		return execution
+	}
+
+	t.Run("should compensate completed steps and ignore forward progress replies", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("verify_customer")
+
+		require.NoError(t, service.Cancel(ctx, execution))
+		assert.Equal(t, ExecutionStatusCancelling, execution.Status)
+		assert.Equal(t, "create_order", execution.CurrentStep)
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("customer_verified", "customers", nil), execution))
+		assert.Equal(t, ExecutionStatusCancelling, execution.Status)
+		assert.Len(t, publisher.destinations, 1)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_rejected", "orders", nil), execution))
+		assert.Equal(t, ExecutionStatusCancelled, execution.Status)
+		assert.Len(t, publisher.destinations, 1)
+	})
+
+	t.Run("should compensate a compensable step that succeeds after the execution was cancelled", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("create_order")
+		require.NoError(t, service.Cancel(ctx, execution))
+		require.Equal(t, ExecutionStatusCancelled, execution.Status)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), execution))
+
+		assert.Equal(t, ExecutionStatusCancelled, execution.Status)
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+		last := execution.Transitions[len(execution.Transitions)-1]
+		assert.Equal(t, TransitionTypeCompensationRequested, last.Type)
+		assert.Equal(t, "create_order", last.Step)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_rejected", "orders", nil), execution))
+		assert.Equal(t, ExecutionStatusCancelled, execution.Status)
+		assert.Len(t, publisher.destinations, 1)
+		assert.Equal(t, TransitionTypeCompensated.String(), execution.StepOutcomes()[0].Status)
+	})
+
+	t.Run("should not overwrite an execution saved concurrently", func(t *testing.T) {
+		repo := &executionRepositoryMock{}
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), repo, publisher)
+		execution := newRunningExecution("verify_customer")
+		require.NoError(t, repo.Insert(ctx, execution, 0))
+		// the consumer saved the execution since the API read it
+		stale := *execution
+		require.NoError(t, repo.Save(ctx, execution))
+
+		err := service.Cancel(ctx, &stale)
+
+		assert.ErrorIs(t, err, ErrConcurrentUpdate)
+		assert.Empty(t, publisher.destinations)
+		assert.Equal(t, ExecutionStatusRunning, execution.Status)
+	})
+
+	t.Run("should finish as cancelled when there are no completed steps", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("create_order")
+
+		require.NoError(t, service.Cancel(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusCancelled, execution.Status)
+		assert.Empty(t, publisher.destinations)
+	})
+
+	t.Run("should keep cancelling after a failed compensation is resolved", func(t *testing.T) {
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{})
+		execution := newRunningExecution("verify_customer")
+		require.NoError(t, service.Cancel(ctx, execution))
+		execution.CompensationFailed("create_order", "boom")
+		execution.CompensationFailed("create_order", "boom")
+
+		require.NoError(t, service.ResolveIntervention(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusCancelled, execution.Status)
+	})
+
+	t.Run("should return error when execution is not running", func(t *testing.T) {
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{})
+		execution := newRunningExecution("verify_customer")
+		execution.Status = ExecutionStatusCompensated
+
+		assert.ErrorIs(t, service.Cancel(ctx, execution), ErrNotCancellable)
+	})
+}
+
+func TestService_ResumeFrom(t *testing.T) {
+	ctx := context.Background()
+	newRunningExecution := func(step string) *Execution {
+		execution := NewExecution(newTestWorkflow())
+		current, _ := execution.Workflow.Steps.GetStep(step)
+		execution.StepRequested(current, REQUEST_ACTION_TYPE)
+		return execution
+	}
+
+	t.Run("should publish the request of the current step again on retry", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("verify_customer")
+
+		require.NoError(t, service.RetryStep(ctx, execution))
+
+		assert.Equal(t, ExecutionStatusRunning, execution.Status)
+		assert.Equal(t, "verify_customer", execution.CurrentStep)
+		assert.Equal(t, []string{"service.customers.request"}, publisher.destinations)
+		assert.Equal(t, TransitionTypeRequested, execution.Transitions[len(execution.Transitions)-1].Type)
+	})
+
+	t.Run("should request the given step of a running execution", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("verify_customer")
+
+		require.NoError(t, service.ResumeFrom(ctx, execution, "create_order"))
+
+		assert.Equal(t, "create_order", execution.CurrentStep)
+		assert.Equal(t, []string{"service.orders.request"}, publisher.destinations)
+	})
+
+	t.Run("should request the compensation of the given step of a compensating execution", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := newRunningExecution("verify_customer")
+		execution.Status = ExecutionStatusCompensating
+
+		require.NoError(t, service.ResumeFrom(ctx, execution, "create_order"))
+		assert.Equal(t, TransitionTypeCompensationRequested, execution.Transitions[len(execution.Transitions)-1].Type)
+
+		assert.ErrorIs(t, service.ResumeFrom(ctx, execution, "verify_customer"), ErrStepNotCompensable)
+	})
+
+	t.Run("should return error when step does not exist", func(t *testing.T) {
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, &publisherMock{})
+		execution := newRunningExecution("verify_customer")
+
+		assert.ErrorIs(t, service.ResumeFrom(ctx, execution, "unknown"), ErrStepNotFound)
+	})
+
+	t.Run("should return error when execution is finished or needs intervention", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		for _, status := range []ExecutionStatus{ExecutionStatusCompleted, ExecutionStatusCancelled, ExecutionStatusNeedsIntervention} {
+			execution := newRunningExecution("verify_customer")
+			execution.Status = status
+			execution.OutcomePublished = true
+
+			assert.ErrorIs(t, service.RetryStep(ctx, execution), ErrNotResumable)
+		}
+		assert.Empty(t, publisher.destinations)
+	})
+}
+
+func TestService_PublishOutcome(t *testing.T) {
+	ctx := context.Background()
+	newWorkflow := func() *Workflow {
+		workflow := newTestWorkflow()
+		workflow.ReplyChannel = "saga.create_order_v1.response"
+		return workflow
+	}
+
+	t.Run("should publish completion to the reply channel", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newWorkflow())
+		verifyCustomer, _ := execution.Workflow.Steps.GetStep("verify_customer")
+		execution.StepRequested(verifyCustomer, REQUEST_ACTION_TYPE)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("customer_verified", "customers", nil), execution))
+
+		assert.Equal(t, ExecutionStatusCompleted, execution.Status)
+		assert.Equal(t, []string{"saga.create_order_v1.response"}, publisher.destinations)
+	})
+
+	t.Run("should not publish while the execution is running", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newWorkflow())
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("order_created", "orders", nil), execution))
+
+		assert.Equal(t, []string{"service.customers.request"}, publisher.destinations)
+	})
+
+	t.Run("should publish failure when an intervention is force completed", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newWorkflow())
+		execution.Status = ExecutionStatusCompensating
+		execution.CompensationFailed("create_order", "boom")
+		execution.CompensationFailed("create_order", "boom")
+
+		require.NoError(t, service.ForceComplete(ctx, execution))
+
+		assert.Equal(t, []string{"saga.create_order_v1.response"}, publisher.destinations)
+	})
+
+	t.Run("should publish the outcome again when the message is redelivered after publishing it failed", func(t *testing.T) {
+		publisher := &publisherMock{err: errors.New("broker is down")}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newWorkflow())
+		verifyCustomer, _ := execution.Workflow.Steps.GetStep("verify_customer")
+		execution.StepRequested(verifyCustomer, REQUEST_ACTION_TYPE)
+		event := events.NewEvent("customer_verified", "customers", nil)
+
+		require.Error(t, service.ProcessMessage(ctx, event, execution))
+		assert.Equal(t, ExecutionStatusCompleted, execution.Status)
+		assert.True(t, execution.OutcomePending())
+
+		publisher.err = nil
+		require.NoError(t, service.ProcessMessage(ctx, event, execution))
+
+		assert.False(t, execution.OutcomePending())
+		assert.Equal(t, []string{"saga.create_order_v1.response"}, publisher.destinations)
+
+		require.NoError(t, service.ProcessMessage(ctx, event, execution))
+		assert.Len(t, publisher.destinations, 1)
+	})
+
+	t.Run("should publish a pending outcome again when the execution is retried", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newWorkflow())
+		execution.Status = ExecutionStatusCancelled
+
+		require.NoError(t, service.RetryStep(ctx, execution))
+
+		assert.True(t, execution.OutcomePublished)
+		assert.Equal(t, []string{"saga.create_order_v1.response"}, publisher.destinations)
+	})
+
+	t.Run("should record the outcome as published when the workflow has no reply channel", func(t *testing.T) {
+		publisher := &publisherMock{}
+		service := NewService(zap.NewNop().Sugar(), &executionRepositoryMock{}, publisher)
+		execution := NewExecution(newTestWorkflow())
+		verifyCustomer, _ := execution.Workflow.Steps.GetStep("verify_customer")
+		execution.StepRequested(verifyCustomer, REQUEST_ACTION_TYPE)
+
+		require.NoError(t, service.ProcessMessage(ctx, events.NewEvent("customer_verified", "customers", nil), execution))
+
+		assert.True(t, execution.OutcomePublished)
+		assert.Empty(t, publisher.destinations)
+	})
+}
+
+func TestNewOutcomeEvent(t *testing.T) {
+	t.Run("should summarize the finished execution", func(t *testing.T) {
+		execution := NewExecution(newTestWorkflow())
+		execution.SetState("input", map[string]interface{}{"card": "0000000000000001", "amount": 140})
+		createOrder, _ := execution.Workflow.Steps.GetStep("create_order")
+		verifyCustomer, _ := execution.Workflow.Steps.GetStep("verify_customer")
+		execution.StepReplied(createOrder, events.NewEvent("order_created", "orders", nil))
+		execution.StepReplied(verifyCustomer, events.NewEvent("customer_verification_failed", "customers", map[string]interface{}{"error": "boom"}))
+		execution.StepReplied(createOrder, events.NewEvent("order_rejected", "orders", nil))
+		execution.Status = ExecutionStatusCompensated
+
+		event, err := NewOutcomeEvent(execution, []string{"input.card"})
+		require.NoError(t, err)
+
+		assert.Equal(t, OutcomeEventCompensated, event.Type)
+		assert.Equal(t, execution.ID.String(), event.CorrelationID)
+		assert.Equal(t, "compensated", event.Data["status"])
+		assert.Equal(t, "verify_customer", event.Data["failed_step"])
+		assert.Equal(t, "boom", event.Data["error"])
+		input := event.Data["state"].(map[string]interface{})["input"].(map[string]interface{})
+		assert.Equal(t, "[REDACTED]", input["card"])
+	})
+}
@@ -14,12 +14,10 @@ type PayloadBuilder interface {
 type (
 	// StepData represents the data of a step in the workflow.
 	StepData struct {
+		StepContract
 		Name           string
-		ServiceName    string
 		Compensable    bool
 		PayloadBuilder PayloadBuilder
-		EventTypes     EventTypes
-		Topics         Topics
 	}
 
 	EventTypes struct {
@@ -28,6 +26,8 @@ type (
 		Failure            string
 		CompesationRequest string
 		Compensation       string
+		// CompensationFailure is replied when the compensation request could not be fulfilled.
+		CompensationFailure string
 	}
 
 	Topics struct {
@@ -55,6 +55,10 @@ func (sd *StepData) IsCompensation(eventType string) bool {
 	return eventType == sd.EventTypes.Compensation
 }
 
+func (sd *StepData) IsCompensationFailure(eventType string) bool {
+	return sd.EventTypes.CompensationFailure != "" && eventType == sd.EventTypes.CompensationFailure
+}
+
 // Next returns the next step in the workflow.
 //
 // Returns the next step if it exists
@@ -104,7 +108,6 @@ type StepsList struct {
 	len  int
 }
 
-// TODO: prevent duplicated step names
 func NewStepList(stepsData ...*StepData) *StepsList {
 	list := &StepsList{}
 	for _, data := range stepsData {
@@ -170,6 +173,9 @@ func (sl *StepsList) GetStepFromServiceEvent(serviceName string, eventType strin
 			if current.EventTypes.Compensation == eventType {
 				return current, true
 			}
+			if current.IsCompensationFailure(eventType) {
+				return current, true
+			}
 		}
 		current, _ = current.Next()
 	}
@@ -4,16 +4,61 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 var (
 	ErrUnknownActionType = fmt.Errorf("unknown action type")
+	ErrInvalidWorkflow   = fmt.Errorf("invalid workflow")
+)
+
+const (
+	DefaultMaxCompensationAttempts = 3
 )
 
 type Workflow struct {
 	Name         string
 	ReplyChannel string
 	Steps        *StepsList
+	// MaxCompensationAttempts is the number of failed attempts of a compensation before the execution needs intervention.
+	// Zero means DefaultMaxCompensationAttempts.
+	MaxCompensationAttempts int
+	// BusinessKeyPath is the dot separated path of the input field that identifies the business entity of an execution.
+	// Executions can be searched by it.
+	BusinessKeyPath string
+	// NewInput returns a pointer to a new value of the workflow input type.
+	// The payload starting an execution is decoded into it and validated with its `validate` struct tags.
+	// When nil, any JSON object is accepted as input.
+	NewInput func() interface{}
+	// CallbackURL receives the webhook notifications of the executions started without a callback URL.
+	CallbackURL string
+}
+
+// BusinessKey returns the value of the BusinessKeyPath field of input or an empty string if it is not set.
+func (w *Workflow) BusinessKey(input map[string]interface{}) string {
+	if w.BusinessKeyPath == "" {
+		return ""
+	}
+	var value interface{} = input
+	for _, key := range strings.Split(w.BusinessKeyPath, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value, ok = m[key]
+		if !ok || value == nil {
+			return ""
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// CompensationAttempts returns the number of failed attempts of a compensation before the execution needs intervention.
+func (w *Workflow) CompensationAttempts() int {
+	if w.MaxCompensationAttempts <= 0 {
+		return DefaultMaxCompensationAttempts
+	}
+	return w.MaxCompensationAttempts
 }
 
 // IsEmpty returns true if the workflow is empty
@@ -21,6 +66,34 @@ func (w *Workflow) IsEmpty() bool {
 	return reflect.DeepEqual(w, &Workflow{})
 }
 
+// Validate checks the workflow definition, returning an error wrapping ErrInvalidWorkflow or ErrInvalidContract
+// when steps are duplicated, have no payload builder or declare an incomplete contract.
+func (w *Workflow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalidWorkflow)
+	}
+	if w.Steps == nil || w.Steps.Len() == 0 {
+		return fmt.Errorf("%w: workflow [%s] has no steps", ErrInvalidWorkflow, w.Name)
+	}
+	names := make(map[string]bool)
+	for _, step := range w.Steps.ToList() {
+		if names[step.Name] {
+			return fmt.Errorf("%w: step [%s] is declared more than once in workflow [%s]", ErrInvalidWorkflow, step.Name, w.Name)
+		}
+		names[step.Name] = true
+		if step.PayloadBuilder == nil {
+			return fmt.Errorf("%w: step [%s] has no payload builder", ErrInvalidWorkflow, step.Name)
+		}
+		if err := step.StepContract.Validate(); err != nil {
+			return fmt.Errorf("step [%s]: %w", step.Name, err)
+		}
+		if step.Compensable && !step.StepContract.IsCompensable() {
+			return fmt.Errorf("%w: compensable step [%s] does not declare its compensation events", ErrInvalidContract, step.Name)
+		}
+	}
+	return nil
+}
+
 type NextStep struct {
 	Step       *Step
 	ActionType ActionType
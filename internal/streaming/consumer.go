@@ -2,6 +2,7 @@ package streaming
 
 import (
 	"context"
+	"errors"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
@@ -11,6 +12,23 @@ type Handler interface {
 	Handle(ctx context.Context, msg *kafka.Message, commitFn func() error) error
 }
 
+type stoppingKey struct{}
+
+// WithStopping returns a copy of ctx whose Stopping channel is stopping.
+func WithStopping(ctx context.Context, stopping <-chan struct{}) context.Context {
+	return context.WithValue(ctx, stoppingKey{}, stopping)
+}
+
+// Stopping returns a channel that is closed when the consumer handling the message of ctx is stopping.
+// Handlers waiting before they handle the message can give up on it, leaving it uncommitted to be redelivered.
+// Outside of a consumer, it returns ctx.Done().
+func Stopping(ctx context.Context) <-chan struct{} {
+	if stopping, ok := ctx.Value(stoppingKey{}).(<-chan struct{}); ok {
+		return stopping
+	}
+	return ctx.Done()
+}
+
 type Consumer struct {
 	logger   *zap.SugaredLogger
 	topics   []string
@@ -34,6 +52,9 @@ func NewConsumer(logger *zap.SugaredLogger, topics []string, kfkCfg *kafka.Confi
 	}, nil
 }
 
+// Start polls the topics and handles their messages until ctx is cancelled.
+// The message in flight is handled to the end, as its handler gets a context that is not cancelled with ctx,
+// but the handler can watch Stopping to give up on it. The stored offsets are committed before the consumer leaves the group.
 func (c *Consumer) Start(ctx context.Context) (err error) {
 	l := c.logger
 	l.Info("Starting consumer")
@@ -47,6 +68,9 @@ func (c *Consumer) Start(ctx context.Context) (err error) {
 		if err != nil {
 			l.With(zap.Error(err)).Error("Closing with error")
 		}
+		if _, cErr := c.consumer.Commit(); cErr != nil && !isNoOffset(cErr) {
+			l.With(zap.Error(cErr)).Error("Got error committing offsets")
+		}
 		cErr := c.consumer.Close()
 		if cErr != nil {
 			l.With(zap.Error(cErr)).Error("Got error closing consumer")
@@ -68,7 +92,8 @@ func (c *Consumer) Start(ctx context.Context) (err error) {
 			switch e := ev.(type) {
 			case *kafka.Message:
 				l.Infof("Message received: %s", string(e.Value))
-				err = c.handler.Handle(ctx, e, func() error {
+				handlerCtx := WithStopping(context.WithoutCancel(ctx), ctx.Done())
+				err = c.handler.Handle(handlerCtx, e, func() error {
 					_, err := c.consumer.CommitMessage(e)
 					return err
 				})
@@ -86,3 +111,9 @@ func (c *Consumer) Start(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// isNoOffset returns true if err reports there was no offset to commit.
+func isNoOffset(err error) bool {
+	var kErr kafka.Error
+	return errors.As(err, &kErr) && kErr.Code() == kafka.ErrNoOffset
+}
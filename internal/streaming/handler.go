@@ -3,6 +3,7 @@ package streaming
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxConcurrentUpdateAttempts is how many times a message is processed
+// before a concurrent update of its execution is returned as an error.
+const maxConcurrentUpdateAttempts = 3
+
 type IdempotenceService interface {
 	Has(ctx context.Context, key string) (bool, error)
 	Set(ctx context.Context, key string, ttl time.Duration) error
@@ -69,26 +74,8 @@ func (h *MessageHandler) Handle(ctx context.Context, msg *kafka.Message, commitF
 		return nil
 	}
 
-	// Get execution
-	execution, err := h.executionRepository.Find(ctx, event.CorrelationID)
+	err = h.processMessage(ctx, &event)
 	if err != nil {
-		l.With(zap.Error(err)).Error("Got error getting workflow")
-		return err // TODO: handle error
-	}
-
-	if execution.IsEmpty() {
-		l.Info("execution not found. Message will be ignored")
-		err = commitFn()
-		if err != nil {
-			l.With(zap.Error(err)).Error("Got error committing message")
-			return err
-		}
-		return nil
-	}
-
-	err = h.sagaService.ProcessMessage(ctx, &event, execution)
-	if err != nil {
-		l.With(zap.Error(err)).Error("Got error processing workflow message")
 		return err
 	}
 
@@ -104,3 +91,32 @@ func (h *MessageHandler) Handle(ctx context.Context, msg *kafka.Message, commitF
 
 	return nil
 }
+
+// processMessage processes the event with the latest saved version of its execution,
+// reloading it when the execution was updated concurrently, e.g. by an API action.
+func (h *MessageHandler) processMessage(ctx context.Context, event *events.Event) error {
+	l := h.logger
+	for attempt := 1; ; attempt++ {
+		execution, err := h.executionRepository.Find(ctx, event.CorrelationID)
+		if err != nil {
+			l.With(zap.Error(err)).Error("Got error getting workflow")
+			return err // TODO: handle error
+		}
+
+		if execution.IsEmpty() {
+			l.Info("execution not found. Message will be ignored")
+			return nil
+		}
+
+		err = h.sagaService.ProcessMessage(ctx, event, execution)
+		if errors.Is(err, saga.ErrConcurrentUpdate) && attempt < maxConcurrentUpdateAttempts {
+			l.Infof("Execution [%s] was updated concurrently. Reloading it", execution.ID)
+			continue
+		}
+		if err != nil {
+			l.With(zap.Error(err)).Error("Got error processing workflow message")
+			return err
+		}
+		return nil
+	}
+}
@@ -2,14 +2,29 @@ package streaming
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
 )
 
+const (
+	flushInterval = 100 * time.Millisecond
+)
+
+var (
+	ErrPublisherClosed = errors.New("publisher is closed")
+)
+
 type Publisher struct { // TODO: add interface
 	logger *zap.SugaredLogger
 	kfkCfg *kafka.ConfigMap
+
+	mu       sync.Mutex
+	producer *kafka.Producer
+	closed   bool
 }
 
 func NewPublisher(logger *zap.SugaredLogger, kfkCfg *kafka.ConfigMap) *Publisher {
@@ -20,19 +35,13 @@ func NewPublisher(logger *zap.SugaredLogger, kfkCfg *kafka.ConfigMap) *Publisher
 }
 
 // TODO: add key
+// Publish returns once the message is delivered, or with the error that failed its delivery.
 func (p *Publisher) Publish(ctx context.Context, destination string, data []byte) error {
 	l := p.logger
 	l.Infof("Publishing message to destination %s", destination)
 
-	prod, err := kafka.NewProducer(p.kfkCfg)
-	if err != nil {
-		p.logger.With(zap.Error(err)).Error("Failed to create producer")
-		return err
-	}
-
-	deliveryChan := make(chan kafka.Event, 100)
-	defer close(deliveryChan)
-	err = prod.Produce(&kafka.Message{
+	deliveryChan := make(chan kafka.Event, 1)
+	err := p.produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &destination, Partition: kafka.PartitionAny},
 		Value:          data},
 		deliveryChan,
@@ -42,13 +51,56 @@ func (p *Publisher) Publish(ctx context.Context, destination string, data []byte
 		return err
 	}
 
-	e := <-deliveryChan
+	var e kafka.Event
+	select {
+	case e = <-deliveryChan:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	m := e.(*kafka.Message)
 	if m.TopicPartition.Error != nil {
-		p.logger.Errorf("Delivery failed: %v\n", m.TopicPartition.Error)
-	} else {
-		p.logger.Infof("Delivered message to topic %s [%d] at offset %v\n",
-			*m.TopicPartition.Topic, m.TopicPartition.Partition, m.TopicPartition.Offset)
+		p.logger.With(zap.Error(m.TopicPartition.Error)).Error("Delivery failed")
+		return m.TopicPartition.Error
+	}
+	p.logger.Infof("Delivered message to topic %s [%d] at offset %v",
+		*m.TopicPartition.Topic, m.TopicPartition.Partition, m.TopicPartition.Offset)
+	return nil
+}
+
+// produce enqueues the message in the producer shared by the publishes, created by the first of them.
+func (p *Publisher) produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	if p.producer == nil {
+		prod, err := kafka.NewProducer(p.kfkCfg)
+		if err != nil {
+			p.logger.With(zap.Error(err)).Error("Failed to create producer")
+			return err
+		}
+		p.producer = prod
+	}
+	return p.producer.Produce(msg, deliveryChan)
+}
+
+// Close rejects new publishes, waits until ctx is done for the queued messages to be delivered and closes the producer.
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	prod := p.producer
+	p.mu.Unlock()
+	if prod == nil {
+		return nil
+	}
+	defer prod.Close()
+
+	for remaining := prod.Flush(int(flushInterval.Milliseconds())); remaining > 0; remaining = prod.Flush(int(flushInterval.Milliseconds())) {
+		if ctx.Err() != nil {
+			p.logger.Errorf("Closing publisher with %d undelivered messages", remaining)
+			return ctx.Err()
+		}
 	}
 	return nil
 }
@@ -0,0 +1,280 @@
+// Package topics computes the Kafka topics required by the registered workflows
+// and creates the missing ones, reporting the drift between them and the cluster.
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmviniciuss/sagas-golang/internal/participant"
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"go.uber.org/zap"
+)
+
+// ManagedPrefixes are the prefixes of the topics owned by the sagas. Existing topics with these prefixes
+// that no workflow requires are reported as unmanaged.
+var ManagedPrefixes = []string{"service.", "saga."}
+
+// Topic is the name and layout of a Kafka topic.
+type Topic struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+}
+
+// Admin lists and creates the topics of a Kafka cluster.
+type Admin interface {
+	List(ctx context.Context) ([]Topic, error)
+	Create(ctx context.Context, topics []Topic) error
+}
+
+var (
+	ErrInvalidRetries = errors.New("invalid retries")
+	// ErrRetryTopicsMismatch is returned when the retry topics of the cluster don't match a participant retry policy.
+	ErrRetryTopicsMismatch = errors.New("retry topics do not match the retry policy")
+)
+
+// Config is the layout of the created topics and the number of retry topics of each participant.
+type Config struct {
+	Partitions        int
+	ReplicationFactor int
+	// Retries is the number of retry topics of the request topic of each participant, by service name.
+	// It is the length of the participant retry policy, a dead letter topic is also required when it is positive.
+	Retries map[string]int
+}
+
+// ParseRetries parses the retries of each participant from comma separated service:retries pairs, e.g. "orders:3".
+func ParseRetries(value string) (map[string]int, error) {
+	retries := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		service, count, ok := strings.Cut(pair, ":")
+		if !ok || service == "" {
+			return nil, fmt.Errorf("%w: [%s] is not a service:retries pair", ErrInvalidRetries, pair)
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: retries of service [%s] must be a non negative integer", ErrInvalidRetries, service)
+		}
+		retries[service] = n
+	}
+	return retries, nil
+}
+
+// Required returns the sorted topics used by workflows: the request and response topics of their steps,
+// their reply channels and the retry and dead letter topics of the request topics of the participants with retries.
+func Required(workflows []saga.Workflow, retries map[string]int) []string {
+	set := make(map[string]bool)
+	for _, workflow := range workflows {
+		if workflow.ReplyChannel != "" {
+			set[workflow.ReplyChannel] = true
+		}
+		for _, step := range workflow.Steps.ToList() {
+			set[step.Topics.Response] = true
+			set[step.Topics.Request] = true
+			for _, name := range RetryTopics(step.Topics.Request, retries[step.ServiceName]) {
+				set[name] = true
+			}
+		}
+	}
+	delete(set, "")
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RetryTopics returns the retry topics of a request topic with the given retries, followed by its dead letter topic.
+func RetryTopics(requestTopic string, retries int) []string {
+	if retries <= 0 {
+		return nil
+	}
+	names := make([]string, 0, retries+1)
+	for attempt := 1; attempt <= retries; attempt++ {
+		names = append(names, participant.RetryTopic(requestTopic, attempt))
+	}
+	return append(names, participant.DeadLetterTopic(requestTopic))
+}
+
+// CheckRetryTopics returns ErrRetryTopicsMismatch unless each of the request topics has, in the cluster,
+// exactly the retry topics of a policy with the given retries and, when it has any, a dead letter topic.
+// Participants run it at startup, so a retry policy that differs from the provisioned topics is not left to fail at runtime.
+func CheckRetryTopics(ctx context.Context, admin Admin, requestTopics []string, retries int) error {
+	existing, err := admin.List(ctx)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, topic := range existing {
+		exists[topic.Name] = true
+	}
+
+	var (
+		missing = make([]string, 0)
+		extra   = make([]string, 0)
+	)
+	for _, requestTopic := range requestTopics {
+		for _, name := range RetryTopics(requestTopic, retries) {
+			if !exists[name] {
+				missing = append(missing, name)
+			}
+		}
+		if next := participant.RetryTopic(requestTopic, retries+1); exists[next] {
+			extra = append(extra, next)
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		return fmt.Errorf("%w of %d retries: missing topics %v, unexpected topics %v", ErrRetryTopicsMismatch, retries, missing, extra)
+	}
+	return nil
+}
+
+// Mismatch is an existing required topic whose layout differs from the configured one.
+type Mismatch struct {
+	Expected Topic
+	Actual   Topic
+}
+
+// Report is the drift between the required topics and the cluster.
+type Report struct {
+	// Missing are the required topics that did not exist. They were created when Created is true.
+	Missing    []string
+	Mismatched []Mismatch
+	// Unmanaged are the existing topics with a managed prefix that no workflow requires.
+	Unmanaged []string
+	// Created is true when the missing topics were created.
+	Created bool
+}
+
+// HasDrift returns true if the cluster does not match the required topics.
+func (r Report) HasDrift() bool {
+	return len(r.Missing) > 0 || len(r.Mismatched) > 0 || len(r.Unmanaged) > 0
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	missing := "Missing"
+	if r.Created {
+		missing = "Created"
+	}
+	fmt.Fprintf(&b, "%s topics: %d\n", missing, len(r.Missing))
+	for _, name := range r.Missing {
+		fmt.Fprintf(&b, "  %s\n", name)
+	}
+	fmt.Fprintf(&b, "Mismatched topics: %d\n", len(r.Mismatched))
+	for _, m := range r.Mismatched {
+		fmt.Fprintf(&b, "  %s: %d partitions and replication factor %d, expected %d and %d\n",
+			m.Actual.Name, m.Actual.Partitions, m.Actual.ReplicationFactor, m.Expected.Partitions, m.Expected.ReplicationFactor)
+	}
+	fmt.Fprintf(&b, "Unmanaged topics: %d\n", len(r.Unmanaged))
+	for _, name := range r.Unmanaged {
+		fmt.Fprintf(&b, "  %s\n", name)
+	}
+	return b.String()
+}
+
+// Provisioner compares the topics required by the workflows with the cluster and creates the missing ones.
+type Provisioner struct {
+	logger    *zap.SugaredLogger
+	admin     Admin
+	config    Config
+	workflows []saga.Workflow
+}
+
+func NewProvisioner(logger *zap.SugaredLogger, admin Admin, config Config, workflows []saga.Workflow) *Provisioner {
+	return &Provisioner{
+		logger:    logger,
+		admin:     admin,
+		config:    config,
+		workflows: workflows,
+	}
+}
+
+// Diff returns the drift between the required topics and the cluster without changing it.
+func (p *Provisioner) Diff(ctx context.Context) (Report, error) {
+	existing, err := p.admin.List(ctx)
+	if err != nil {
+		p.logger.With(zap.Error(err)).Error("Got error listing topics")
+		return Report{}, err
+	}
+	byName := make(map[string]Topic, len(existing))
+	for _, topic := range existing {
+		byName[topic.Name] = topic
+	}
+
+	var (
+		report   = Report{Missing: []string{}, Mismatched: []Mismatch{}, Unmanaged: []string{}}
+		required = make(map[string]bool)
+	)
+	for _, name := range Required(p.workflows, p.config.Retries) {
+		required[name] = true
+		actual, ok := byName[name]
+		if !ok {
+			report.Missing = append(report.Missing, name)
+			continue
+		}
+		expected := p.topic(name)
+		if actual.Partitions != expected.Partitions || actual.ReplicationFactor != expected.ReplicationFactor {
+			report.Mismatched = append(report.Mismatched, Mismatch{Expected: expected, Actual: actual})
+		}
+	}
+	for _, topic := range existing {
+		if !required[topic.Name] && isManaged(topic.Name) {
+			report.Unmanaged = append(report.Unmanaged, topic.Name)
+		}
+	}
+	sort.Strings(report.Unmanaged)
+	return report, nil
+}
+
+// Provision creates the missing topics and returns the drift found before creating them.
+// Mismatched and unmanaged topics are only reported.
+func (p *Provisioner) Provision(ctx context.Context) (Report, error) {
+	report, err := p.Diff(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(report.Missing) == 0 {
+		return report, nil
+	}
+
+	topics := make([]Topic, 0, len(report.Missing))
+	for _, name := range report.Missing {
+		topics = append(topics, p.topic(name))
+	}
+	err = p.admin.Create(ctx, topics)
+	if err != nil {
+		p.logger.With(zap.Error(err)).Error("Got error creating topics")
+		return Report{}, err
+	}
+	p.logger.Infof("Created topics %v", report.Missing)
+	report.Created = true
+	return report, nil
+}
+
+func (p *Provisioner) topic(name string) Topic {
+	return Topic{
+		Name:              name,
+		Partitions:        p.config.Partitions,
+		ReplicationFactor: p.config.ReplicationFactor,
+	}
+}
+
+func isManaged(name string) bool {
+	for _, prefix := range ManagedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
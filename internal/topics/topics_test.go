@@ -0,0 +1,188 @@
+package topics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type adminMock struct {
+	topics  []Topic
+	created []Topic
+}
+
+func (a *adminMock) List(_ context.Context) ([]Topic, error) {
+	return a.topics, nil
+}
+
+func (a *adminMock) Create(_ context.Context, topics []Topic) error {
+	a.created = append(a.created, topics...)
+	a.topics = append(a.topics, topics...)
+	return nil
+}
+
+func newWorkflows() []saga.Workflow {
+	ordersTopics := saga.Topics{Request: "service.orders.request", Response: "service.orders.events"}
+	return []saga.Workflow{
+		{
+			Name:         "create_order_v1",
+			ReplyChannel: "saga.create_order_v1.response",
+			Steps: saga.NewStepList(
+				&saga.StepData{Name: "create_order", StepContract: saga.StepContract{ServiceName: "orders", Topics: ordersTopics}},
+				&saga.StepData{Name: "verify_customer", StepContract: saga.StepContract{
+					ServiceName: "customers",
+					Topics:      saga.Topics{Request: "service.customers.request", Response: "service.customers.events"},
+				}},
+				&saga.StepData{Name: "approve_order", StepContract: saga.StepContract{ServiceName: "orders", Topics: ordersTopics}},
+			),
+		},
+	}
+}
+
+func TestRequired(t *testing.T) {
+	t.Run("should return the step, reply, retry and dead letter topics once", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"saga.create_order_v1.response",
+			"service.customers.events",
+			"service.customers.request",
+			"service.orders.events",
+			"service.orders.request",
+			"service.orders.request.dlq",
+			"service.orders.request.retry.1",
+			"service.orders.request.retry.2",
+		}, Required(newWorkflows(), map[string]int{"orders": 2}))
+	})
+
+	t.Run("should not require retry topics without retries", func(t *testing.T) {
+		assert.Len(t, Required(newWorkflows(), nil), 5)
+		assert.Len(t, Required(newWorkflows(), map[string]int{"orders": 0}), 5)
+	})
+}
+
+func TestParseRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]int
+		wantErr bool
+	}{
+		{name: "should parse the retries of each service", value: "orders:3, customers:0", want: map[string]int{"orders": 3, "customers": 0}},
+		{name: "should parse an empty value", value: "", want: map[string]int{}},
+		{name: "should reject a pair without retries", value: "orders", wantErr: true},
+		{name: "should reject a pair without service", value: ":3", wantErr: true},
+		{name: "should reject negative retries", value: "orders:-1", wantErr: true},
+		{name: "should reject retries that are not a number", value: "orders:three", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRetries(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRetries)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckRetryTopics(t *testing.T) {
+	ctx := context.Background()
+	topics := func(names ...string) *adminMock {
+		admin := &adminMock{}
+		for _, name := range names {
+			admin.topics = append(admin.topics, Topic{Name: name})
+		}
+		return admin
+	}
+	tests := []struct {
+		name    string
+		admin   *adminMock
+		retries int
+		wantErr bool
+	}{
+		{
+			name:    "should accept the retry and dead letter topics of the policy",
+			admin:   topics("service.orders.request", "service.orders.request.retry.1", "service.orders.request.retry.2", "service.orders.request.dlq"),
+			retries: 2,
+		},
+		{
+			name:    "should accept no retry topics without retries",
+			admin:   topics("service.orders.request"),
+			retries: 0,
+		},
+		{
+			name:    "should reject missing retry topics",
+			admin:   topics("service.orders.request", "service.orders.request.retry.1", "service.orders.request.dlq"),
+			retries: 2,
+			wantErr: true,
+		},
+		{
+			name:    "should reject a missing dead letter topic",
+			admin:   topics("service.orders.request", "service.orders.request.retry.1"),
+			retries: 1,
+			wantErr: true,
+		},
+		{
+			name:    "should reject retry topics beyond the policy",
+			admin:   topics("service.orders.request", "service.orders.request.retry.1", "service.orders.request.retry.2", "service.orders.request.dlq"),
+			retries: 1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRetryTopics(ctx, tt.admin, []string{"service.orders.request"}, tt.retries)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrRetryTopicsMismatch)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvisioner(t *testing.T) {
+	ctx := context.Background()
+	config := Config{Partitions: 3, ReplicationFactor: 1}
+
+	t.Run("should report missing, mismatched and unmanaged topics", func(t *testing.T) {
+		admin := &adminMock{topics: []Topic{
+			{Name: "service.orders.request", Partitions: 3, ReplicationFactor: 1},
+			{Name: "service.orders.events", Partitions: 1, ReplicationFactor: 1},
+			{Name: "service.kitchen.request", Partitions: 3, ReplicationFactor: 1},
+			{Name: "__consumer_offsets", Partitions: 50, ReplicationFactor: 1},
+		}}
+		provisioner := NewProvisioner(zap.NewNop().Sugar(), admin, config, newWorkflows())
+
+		report, err := provisioner.Diff(ctx)
+		require.NoError(t, err)
+
+		assert.True(t, report.HasDrift())
+		assert.Equal(t, []string{"saga.create_order_v1.response", "service.customers.events", "service.customers.request"}, report.Missing)
+		require.Len(t, report.Mismatched, 1)
+		assert.Equal(t, "service.orders.events", report.Mismatched[0].Actual.Name)
+		assert.Equal(t, []string{"service.kitchen.request"}, report.Unmanaged)
+		assert.Empty(t, admin.created)
+	})
+
+	t.Run("should create the missing topics with the configured layout", func(t *testing.T) {
+		admin := &adminMock{}
+		provisioner := NewProvisioner(zap.NewNop().Sugar(), admin, config, newWorkflows())
+
+		report, err := provisioner.Provision(ctx)
+		require.NoError(t, err)
+
+		assert.True(t, report.Created)
+		assert.Len(t, admin.created, 5)
+		assert.Contains(t, admin.created, Topic{Name: "service.orders.request", Partitions: 3, ReplicationFactor: 1})
+
+		report, err = provisioner.Diff(ctx)
+		require.NoError(t, err)
+		assert.False(t, report.HasDrift())
+	})
+}
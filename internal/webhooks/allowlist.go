@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Allowlist restricts the callback URLs a request can set, so the orchestrator can't be used
+// to POST to the hosts of its own network.
+type Allowlist struct {
+	// Schemes are the allowed URL schemes, e.g. https.
+	Schemes []string
+	// Hosts are the allowed host names. A host starting with "*." allows any subdomain of the rest of it.
+	Hosts []string
+}
+
+// Allows returns true if rawURL is an absolute URL with an allowed scheme and host.
+func (a Allowlist) Allows(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return false
+	}
+	return a.allowsScheme(u.Scheme) && a.allowsHost(u.Hostname())
+}
+
+func (a Allowlist) allowsScheme(scheme string) bool {
+	for _, allowed := range a.Schemes {
+		if strings.EqualFold(scheme, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Allowlist) allowsHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range a.Hosts {
+		allowed = strings.ToLower(allowed)
+		if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
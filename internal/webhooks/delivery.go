@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusFailed is the status of a delivery that exhausted its attempts.
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// DeliveryStatus represents the lifecycle stage of a webhook delivery.
+type DeliveryStatus string
+
+func (s DeliveryStatus) String() string {
+	return string(s)
+}
+
+// Delivery is a notification to be POSTed to a callback URL.
+type Delivery struct {
+	ID          uuid.UUID
+	ExecutionID uuid.UUID
+	URL         string
+	EventType   string
+	// Payload is the JSON body of the notification.
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func NewDelivery(executionID uuid.UUID, url string, eventType string, payload []byte) *Delivery {
+	now := time.Now().UTC()
+	return &Delivery{
+		ID:            uuid.New(),
+		ExecutionID:   executionID,
+		URL:           url,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Repository persists the deliveries, so pending ones survive restarts.
+// Deliveries are inserted along with the execution they notify, see saga.Notification.
+type Repository interface {
+	Save(ctx context.Context, delivery *Delivery) error
+	// ClaimDue returns up to limit pending deliveries whose next attempt is due
+	// and postpones their next attempt by lease, so other dispatchers skip them while they are attempted.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultBatchSize = 50
+	// DefaultLease is for how long a claimed delivery is hidden from other dispatchers while it is attempted.
+	DefaultLease = time.Minute
+)
+
+// RetryPolicy defines the delays between the attempts of a delivery.
+// A delivery is attempted once plus one time per delay before it is marked as failed.
+type RetryPolicy struct {
+	Delays []time.Duration
+}
+
+// Dispatcher POSTs the persisted deliveries to their URL, signing them with a shared secret
+// and retrying the failed attempts with the delays of its retry policy.
+type Dispatcher struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+	client     *http.Client
+	secret     []byte
+	policy     RetryPolicy
+	batchSize  int
+	lease      time.Duration
+}
+
+func NewDispatcher(
+	logger *zap.SugaredLogger,
+	repository Repository,
+	client *http.Client,
+	secret string,
+	policy RetryPolicy,
+) *Dispatcher {
+	return &Dispatcher{
+		logger:     logger,
+		repository: repository,
+		client:     client,
+		secret:     []byte(secret),
+		policy:     policy,
+		batchSize:  DefaultBatchSize,
+		lease:      DefaultLease,
+	}
+}
+
+// NewClient returns a client for the deliveries that doesn't follow redirects, as a callback URL
+// allowed by the Allowlist could redirect the signed deliveries anywhere. A redirect fails the attempt.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run dispatches the due deliveries every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := d.DispatchDue(ctx); err != nil {
+				d.logger.With(zap.Error(err)).Error("Got error dispatching webhook deliveries")
+			}
+		}
+	}
+}
+
+// DispatchDue attempts the deliveries that are due and returns how many were attempted.
+// Once ctx is done the attempt in flight is completed and the remaining deliveries are left to be claimed again when their lease expires.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repository.ClaimDue(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return 0, err
+	}
+	for i, delivery := range deliveries {
+		if ctx.Err() != nil {
+			d.logger.Infof("Stopping with %d claimed webhook deliveries left", len(deliveries)-i)
+			return i, nil
+		}
+		if err := d.attempt(context.WithoutCancel(ctx), delivery); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt POSTs the delivery and saves its outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) error {
+	l := d.logger.With("delivery_id", delivery.ID.String(), "execution_id", delivery.ExecutionID.String(), "event_type", delivery.EventType)
+	delivery.Attempts++
+	err := d.post(ctx, delivery)
+	switch {
+	case err == nil:
+		l.Info("Delivered webhook")
+		delivery.Status = DeliveryStatusDelivered
+		delivery.LastError = ""
+	case delivery.Attempts > len(d.policy.Delays):
+		l.With(zap.Error(err)).Errorf("Webhook delivery failed after %d attempts", delivery.Attempts)
+		delivery.Status = DeliveryStatusFailed
+		delivery.LastError = err.Error()
+	default:
+		delay := d.policy.Delays[delivery.Attempts-1]
+		l.With(zap.Error(err)).Infof("Webhook delivery failed. Retrying in %s", delay)
+		delivery.NextAttemptAt = time.Now().UTC().Add(delay)
+		delivery.LastError = err.Error()
+	}
+	err = d.repository.Save(ctx, delivery)
+	if err != nil {
+		l.With(zap.Error(err)).Error("Got error saving webhook delivery")
+		return err
+	}
+	return nil
+}
+
+func (d *Dispatcher) post(ctx context.Context, delivery *Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(d.secret, time.Now(), delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("callback responded with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type repositoryMock struct {
+	mu   sync.Mutex
+	data map[uuid.UUID]*Delivery
+}
+
+// add seeds the repository with pending deliveries of payload to url.
+func (r *repositoryMock) add(url string, eventType string, payload []byte, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		r.data = make(map[uuid.UUID]*Delivery)
+	}
+	for range count {
+		delivery := NewDelivery(uuid.New(), url, eventType, payload)
+		r.data[delivery.ID] = delivery
+	}
+}
+
+func (r *repositoryMock) Save(_ context.Context, delivery *Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[delivery.ID] = delivery
+	return nil
+}
+
+func (r *repositoryMock) ClaimDue(_ context.Context, limit int, lease time.Duration) ([]*Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	due := make([]*Delivery, 0)
+	for _, delivery := range r.data {
+		if len(due) == limit {
+			break
+		}
+		if delivery.Status == DeliveryStatusPending && !delivery.NextAttemptAt.After(now) {
+			delivery.NextAttemptAt = now.Add(lease)
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *repositoryMock) only(t *testing.T) *Delivery {
+	t.Helper()
+	require.Len(t, r.data, 1)
+	for _, delivery := range r.data {
+		return delivery
+	}
+	return nil
+}
+
+// dueNow makes the pending retries of the repository due.
+func (r *repositoryMock) dueNow() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range r.data {
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	secret := "secret"
+	payload := []byte(`{"type":"saga_completed"}`)
+
+	t.Run("should POST signed deliveries", func(t *testing.T) {
+		var (
+			received []byte
+			headers  http.Header
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			headers = r.Header.Clone()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		repo := &repositoryMock{}
+		dispatcher := NewDispatcher(zap.NewNop().Sugar(), repo, server.Client(), secret, RetryPolicy{})
+
+		repo.add(server.URL, "saga_completed", payload, 1)
+		attempted, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, attempted)
+		assert.Equal(t, payload, received)
+		assert.Equal(t, "saga_completed", headers.Get(EventHeader))
+		assert.True(t, Verify([]byte(secret), headers.Get(SignatureHeader), received, time.Minute))
+		delivery := repo.only(t)
+		assert.Equal(t, DeliveryStatusDelivered, delivery.Status)
+		assert.Equal(t, delivery.ID.String(), headers.Get(DeliveryHeader))
+		assert.Equal(t, 1, delivery.Attempts)
+	})
+
+	t.Run("should complete the attempt in flight and leave the other deliveries once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			cancel()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		repo := &repositoryMock{}
+		dispatcher := NewDispatcher(zap.NewNop().Sugar(), repo, server.Client(), secret, RetryPolicy{})
+		repo.add(server.URL, "saga_completed", payload, 2)
+
+		attempted, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, attempted)
+		assert.Equal(t, 1, calls)
+		statuses := make([]DeliveryStatus, 0, len(repo.data))
+		for _, delivery := range repo.data {
+			statuses = append(statuses, delivery.Status)
+		}
+		assert.ElementsMatch(t, []DeliveryStatus{DeliveryStatusDelivered, DeliveryStatusPending}, statuses)
+	})
+
+	t.Run("should fail the attempt instead of following redirects", func(t *testing.T) {
+		var redirected bool
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			redirected = true
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		defer server.Close()
+		repo := &repositoryMock{}
+		dispatcher := NewDispatcher(zap.NewNop().Sugar(), repo, NewClient(time.Second), secret, RetryPolicy{})
+		repo.add(server.URL, "saga_completed", payload, 1)
+
+		_, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		assert.False(t, redirected)
+		delivery := repo.only(t)
+		assert.Equal(t, DeliveryStatusFailed, delivery.Status)
+		assert.Equal(t, "callback responded with status 307", delivery.LastError)
+	})
+
+	t.Run("should retry failed deliveries until attempts are exhausted", func(t *testing.T) {
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		repo := &repositoryMock{}
+		dispatcher := NewDispatcher(zap.NewNop().Sugar(), repo, server.Client(), secret, RetryPolicy{
+			Delays: []time.Duration{time.Minute},
+		})
+		repo.add(server.URL, "saga_completed", payload, 1)
+
+		_, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+		delivery := repo.only(t)
+		assert.Equal(t, DeliveryStatusPending, delivery.Status)
+		assert.Equal(t, "callback responded with status 503", delivery.LastError)
+		assert.True(t, delivery.NextAttemptAt.After(time.Now().Add(50*time.Second)))
+
+		attempted, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, attempted)
+
+		repo.dueNow()
+		_, err = dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryStatusFailed, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should deliver on retry once the callback recovers", func(t *testing.T) {
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		repo := &repositoryMock{}
+		dispatcher := NewDispatcher(zap.NewNop().Sugar(), repo, server.Client(), secret, RetryPolicy{
+			Delays: []time.Duration{time.Second, time.Second},
+		})
+		repo.add(server.URL, "saga_started", payload, 1)
+
+		_, err := dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+		repo.dueNow()
+		_, err = dispatcher.DispatchDue(ctx)
+		require.NoError(t, err)
+
+		delivery := repo.only(t)
+		assert.Equal(t, DeliveryStatusDelivered, delivery.Status)
+		assert.Empty(t, delivery.LastError)
+		assert.Equal(t, 2, delivery.Attempts)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{}`)
+
+	t.Run("should accept a recent signature of the body", func(t *testing.T) {
+		assert.True(t, Verify(secret, Sign(secret, time.Now(), body), body, time.Minute))
+	})
+
+	t.Run("should reject signatures of other bodies, secrets or too old", func(t *testing.T) {
+		assert.False(t, Verify(secret, Sign(secret, time.Now(), body), []byte(`{"a":1}`), time.Minute))
+		assert.False(t, Verify(secret, Sign([]byte("other"), time.Now(), body), body, time.Minute))
+		assert.False(t, Verify(secret, Sign(secret, time.Now().Add(-time.Hour), body), body, time.Minute))
+		assert.False(t, Verify(secret, "invalid", body, time.Minute))
+	})
+}
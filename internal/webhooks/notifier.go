@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"encoding/json"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+)
+
+const (
+	EventStarted           = "saga_started"
+	EventNeedsIntervention = "saga_needs_intervention"
+)
+
+// Notifier is a saga.Notifier of the lifecycle of the executions with a webhook URL:
+// EventStarted, EventNeedsIntervention and the outcome event once the execution finishes.
+// Its notifications are saved as deliveries along with the execution and attempted by the Dispatcher.
+type Notifier struct {
+	// redactedFields are the dot separated paths of the execution state hidden from the outcome notifications.
+	redactedFields []string
+}
+
+var (
+	_ saga.Notifier = (*Notifier)(nil)
+)
+
+func NewNotifier(redactedFields []string) *Notifier {
+	return &Notifier{
+		redactedFields: redactedFields,
+	}
+}
+
+func (n *Notifier) Notifications(execution *saga.Execution, transitions []saga.Transition) ([]saga.Notification, error) {
+	url := execution.WebhookURL()
+	if url == "" {
+		return nil, nil
+	}
+	event, err := n.lifecycleEvent(execution, transitions)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return []saga.Notification{{URL: url, EventType: event.Type, Payload: payload}}, nil
+}
+
+// lifecycleEvent returns the notification of the given save of execution or nil if it is not a lifecycle event.
+func (n *Notifier) lifecycleEvent(execution *saga.Execution, transitions []saga.Transition) (*events.Event, error) {
+	switch {
+	case execution.OutcomePending():
+		// later saves of the finished execution, e.g. the one recording its outcome was published, are not notified again
+		return saga.NewOutcomeEvent(execution, n.redactedFields)
+	case execution.NeedsIntervention() && hasTransition(transitions, saga.TransitionTypeCompensationFailed):
+		return n.newEvent(EventNeedsIntervention, execution, map[string]interface{}{
+			"step":     execution.Intervention.Step,
+			"error":    execution.Intervention.Error,
+			"attempts": execution.Intervention.Attempts,
+		}), nil
+	case len(transitions) > 0 && len(transitions) == len(execution.Transitions):
+		// every transition is new only when the execution was just started
+		return n.newEvent(EventStarted, execution, nil), nil
+	}
+	return nil, nil
+}
+
+func (n *Notifier) newEvent(eventType string, execution *saga.Execution, extra map[string]interface{}) *events.Event {
+	data := map[string]interface{}{
+		"execution_id": execution.ID.String(),
+		"workflow":     execution.Workflow.Name,
+		"status":       execution.Status.String(),
+		"business_key": execution.BusinessKey,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+	return events.NewEvent(eventType, saga.OutcomeOrigin, data).WithCorrelationID(execution.ID.String())
+}
+
+func hasTransition(transitions []saga.Transition, transitionType saga.TransitionType) bool {
+	for _, transition := range transitions {
+		if transition.Type == transitionType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/internal/saga"
+	"github.com/bmviniciuss/sagas-golang/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExecution(callbackURL string) *saga.Execution {
+	workflow := &saga.Workflow{
+		Name: "create_order_v1",
+		Steps: saga.NewStepList(&saga.StepData{
+			Name:        "create_order",
+			Compensable: true,
+			StepContract: saga.StepContract{
+				ServiceName: "orders",
+				EventTypes: saga.EventTypes{
+					Request:            "create_order",
+					Success:            "order_created",
+					Failure:            "order_creation_failed",
+					CompesationRequest: "reject_order",
+					Compensation:       "order_rejected",
+				},
+			},
+		}),
+	}
+	execution := saga.NewExecution(workflow)
+	execution.CallbackURL = callbackURL
+	return execution
+}
+
+func TestNotifier(t *testing.T) {
+	notifier := NewNotifier([]string{"input.card"})
+	step := func(execution *saga.Execution) *saga.Step {
+		s, _ := execution.Workflow.Steps.Head()
+		return s
+	}
+
+	t.Run("should notify that a new execution started", func(t *testing.T) {
+		execution := newExecution("https://partner.example.com/hooks")
+		execution.StepRequested(step(execution), saga.REQUEST_ACTION_TYPE)
+
+		notifications, err := notifier.Notifications(execution, execution.Transitions)
+
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		notification := notifications[0]
+
+		assert.Equal(t, EventStarted, notification.EventType)
+		assert.Equal(t, "https://partner.example.com/hooks", notification.URL)
+		var event events.Event
+		require.NoError(t, json.Unmarshal(notification.Payload, &event))
+		assert.Equal(t, execution.ID.String(), event.CorrelationID)
+	})
+
+	t.Run("should notify the outcome when execution finishes", func(t *testing.T) {
+		execution := newExecution("https://partner.example.com/hooks")
+		execution.SetState("input", map[string]interface{}{"card": "0000000000000001"})
+		execution.StepRequested(step(execution), saga.REQUEST_ACTION_TYPE)
+		transitions := execution.Transitions
+		execution.StepReplied(step(execution), events.NewEvent("order_created", "orders", nil))
+		execution.Status = saga.ExecutionStatusCompleted
+
+		notifications, err := notifier.Notifications(execution, execution.Transitions[len(transitions):])
+
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		notification := notifications[0]
+
+		assert.Equal(t, saga.OutcomeEventCompleted, notification.EventType)
+		var event events.Event
+		require.NoError(t, json.Unmarshal(notification.Payload, &event))
+		assert.Equal(t, "completed", event.Data["status"])
+		assert.NotContains(t, string(notification.Payload), "0000000000000001")
+	})
+
+	t.Run("should not notify the outcome again once it was published", func(t *testing.T) {
+		execution := newExecution("https://partner.example.com/hooks")
+		execution.Status = saga.ExecutionStatusCompleted
+		execution.OutcomePublished = true
+
+		notifications, err := notifier.Notifications(execution, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+
+	t.Run("should not notify progress or executions without callback", func(t *testing.T) {
+		execution := newExecution("https://partner.example.com/hooks")
+		execution.StepRequested(step(execution), saga.REQUEST_ACTION_TYPE)
+		execution.StepReplied(step(execution), events.NewEvent("order_created", "orders", nil))
+		progress, err := notifier.Notifications(execution, execution.Transitions[1:])
+		require.NoError(t, err)
+
+		withoutCallback := newExecution("")
+		withoutCallback.Status = saga.ExecutionStatusCompleted
+		finished, err := notifier.Notifications(withoutCallback, nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, progress)
+		assert.Empty(t, finished)
+	})
+
+	t.Run("should fall back to the workflow callback url", func(t *testing.T) {
+		execution := newExecution("")
+		execution.Workflow.CallbackURL = "https://workflow.example.com/hooks"
+		execution.Status = saga.ExecutionStatusCompensated
+
+		notifications, err := notifier.Notifications(execution, nil)
+
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		notification := notifications[0]
+
+		assert.Equal(t, "https://workflow.example.com/hooks", notification.URL)
+	})
+}
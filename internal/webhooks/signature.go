@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Saga-Signature"
+	EventHeader     = "X-Saga-Event"
+	DeliveryHeader  = "X-Saga-Delivery"
+)
+
+// Sign returns the value of the SignatureHeader of a notification with body sent at timestamp.
+// It has the format t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<unix timestamp>.<body>" keyed by secret>,
+// so receivers can check the notification authenticity and reject old ones.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, signature(secret, t, body))
+}
+
+// Verify reports whether header is a valid signature of body by secret made at most tolerance ago.
+func Verify(secret []byte, header string, body []byte, tolerance time.Duration) bool {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return false
+	}
+	if time.Since(time.Unix(unix, 0)) > tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(v1), []byte(signature(secret, t, body)))
+}
+
+func signature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,204 @@
+// Package openapi builds OpenAPI 3 documents from the Go types of an API and validates requests against them.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version = "3.0.3"
+
+	// SecurityAPIKey and SecurityBearer name the security schemes added by WithAuthentication.
+	SecurityAPIKey = "apiKey"
+	SecurityBearer = "bearerAuth"
+)
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+
+	// names are the component names of the Go types with a schema in Components.Schemas.
+	names map[reflect.Type]string
+	// defined are the schemas of the Go types registered by Define.
+	defined map[reflect.Type]*Schema
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lower case HTTP methods to their operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to the scopes they require.
+type SecurityRequirement map[string][]string
+
+// New returns an empty document of the API.
+func New(title string, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+		names:   make(map[reflect.Type]string),
+		defined: make(map[reflect.Type]*Schema),
+	}
+}
+
+// WithAuthentication requires every operation to be called with an API key in the apiKeyHeader header or a JWT bearer token.
+// Use Public for the operations open to everyone.
+func (d *Document) WithAuthentication(apiKeyHeader string) *Document {
+	d.Components.SecuritySchemes = map[string]*SecurityScheme{
+		SecurityAPIKey: {Type: "apiKey", Name: apiKeyHeader, In: "header"},
+		SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
+	d.Security = []SecurityRequirement{
+		{SecurityAPIKey: {}},
+		{SecurityBearer: {}},
+	}
+	return d
+}
+
+// Add registers the operation handling method requests to path. Path parameters are written as {name}.
+func (d *Document) Add(method string, path string, operation *Operation) *Document {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+	return d
+}
+
+// Public removes the security requirements of the operation.
+func (o *Operation) Public() *Operation {
+	o.Security = &[]SecurityRequirement{}
+	return o
+}
+
+// RequiresScopes documents the scopes the principal calling the operation must be granted.
+func (o *Operation) RequiresScopes(scopes ...string) *Operation {
+	o.Description = strings.TrimSpace(o.Description + "\n\nRequires the scopes: `" + strings.Join(scopes, "`, `") + "`.")
+	return o
+}
+
+// JSONBody returns a required JSON request body of the given schema.
+func JSONBody(schema *Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// JSONResponse returns a response with a JSON body of the given schema.
+func JSONResponse(description string, schema *Schema) *Response {
+	return ContentResponse(description, "application/json", schema)
+}
+
+// ContentResponse returns a response with a body of the given media type.
+func ContentResponse(description string, mediaType string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{mediaType: {Schema: schema}},
+	}
+}
+
+// Responses maps status codes to responses.
+func Responses(responses map[int]*Response) map[string]*Response {
+	res := make(map[string]*Response, len(responses))
+	for status, response := range responses {
+		res[strconv.Itoa(status)] = response
+	}
+	return res
+}
+
+// PathParam returns a required path parameter.
+func PathParam(name string, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// QueryParam returns an optional query parameter.
+func QueryParam(name string, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// HeaderParam returns an optional header parameter.
+func HeaderParam(name string, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: schema}
+}
+
+// Handler serves the document as JSON.
+func (d *Document) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(d)
+	}
+}
@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// String returns a string schema of the given format, if any.
+func String(format string) *Schema {
+	return &Schema{Type: "string", Format: format}
+}
+
+// Integer returns an integer schema.
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// Enum returns a string schema accepting only values.
+func Enum(values ...string) *Schema {
+	schema := String("")
+	for _, v := range values {
+		schema.Enum = append(schema.Enum, v)
+	}
+	return schema
+}
+
+// Object returns an object schema of the given properties, all of them required.
+func Object(properties map[string]*Schema) *Schema {
+	schema := &Schema{Type: "object", Properties: properties}
+	for name := range properties {
+		schema.Required = append(schema.Required, name)
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// WithMaxLength sets the maximum length of a string schema.
+func (s *Schema) WithMaxLength(length int) *Schema {
+	s.MaxLength = &length
+	return s
+}
+
+// WithRange sets the inclusive minimum and maximum of a number schema.
+func (s *Schema) WithRange(min float64, max float64) *Schema {
+	s.Minimum, s.Maximum = &min, &max
+	return s
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Define sets the schema of the type of value, for types marshaled to JSON by custom methods.
+func (d *Document) Define(value interface{}, schema *Schema) *Document {
+	d.defined[reflect.TypeOf(value)] = schema
+	return d
+}
+
+// SchemaOf returns the schema of the type of value following its JSON encoding.
+// Named structs are added to the document components and referenced, and the constraints
+// of their validate tags (required, gt, gte, lt, lte, min, max, len, oneof, uuid, email and url) are documented.
+func (d *Document) SchemaOf(value interface{}) *Schema {
+	return d.schemaOf(reflect.TypeOf(value))
+}
+
+// Named is SchemaOf, naming the component of the struct type of value instead of using its type name.
+// A type already in the components keeps its name.
+func (d *Document) Named(name string, value interface{}) *Schema {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Struct {
+		if _, ok := d.names[t]; !ok {
+			d.register(t, name)
+		}
+	}
+	return d.schemaOf(t)
+}
+
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if schema, ok := d.defined[t]; ok {
+		copied := *schema
+		return &copied
+	}
+	if t.Kind() == reflect.Pointer {
+		schema := d.schemaOf(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+	if t == timeType {
+		return String("date-time")
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		if t.PkgPath() == "github.com/google/uuid" && t.Name() == "UUID" {
+			return String("uuid")
+		}
+		return String("")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return String("")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return String("byte")
+		}
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return d.structSchema(t)
+	}
+	return &Schema{}
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return d.objectSchema(t)
+	}
+	name, ok := d.names[t]
+	if !ok {
+		name = d.componentName(t)
+		d.register(t, name)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (d *Document) register(t reflect.Type, name string) {
+	d.names[t] = name
+	// registered before its properties so recursive types reference it
+	d.Components.Schemas[name] = &Schema{}
+	*d.Components.Schemas[name] = *d.objectSchema(t)
+}
+
+// componentName returns the type name, prefixed by its package name if another type already uses it.
+func (d *Document) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := d.Components.Schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	pkg = strings.ReplaceAll(pkg, "-", "")
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+func (d *Document) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	d.addFields(schema, t)
+	return schema
+}
+
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				d.addFields(schema, embedded)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := d.schemaOf(field.Type)
+		required := applyValidateTag(property, field.Tag.Get("validate"))
+		if required {
+			property.Nullable = false
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyValidateTag documents the constraints of a validate tag in schema and returns true if the field is required.
+// The rules after dive apply to the items of an array.
+func applyValidateTag(schema *Schema, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+	rules, itemRules, dive := strings.Cut(tag, ",dive")
+	if dive && schema.Items != nil {
+		applyValidateTag(schema.Items, strings.TrimPrefix(itemRules, ","))
+	}
+
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, v)
+			}
+		case "gt", "gte", "min":
+			setLowerBound(schema, param, name == "gt")
+		case "lt", "lte", "max":
+			setUpperBound(schema, param, name == "lt")
+		case "len":
+			setLowerBound(schema, param, false)
+			setUpperBound(schema, param, false)
+		}
+	}
+	return required
+}
+
+func setLowerBound(schema *Schema, param string, exclusive bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "integer", "number":
+		schema.Minimum = &n
+		schema.ExclusiveMinimum = exclusive
+	case "string":
+		length := bound(n, exclusive, 1)
+		schema.MinLength = &length
+	case "array":
+		length := bound(n, exclusive, 1)
+		schema.MinItems = &length
+	}
+}
+
+func setUpperBound(schema *Schema, param string, exclusive bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type {
+	case "integer", "number":
+		schema.Maximum = &n
+		schema.ExclusiveMaximum = exclusive
+	case "string":
+		length := bound(n, exclusive, -1)
+		schema.MaxLength = &length
+	case "array":
+		length := bound(n, exclusive, -1)
+		schema.MaxItems = &length
+	}
+}
+
+// bound returns the inclusive length bound of n, moved by step when the bound is exclusive.
+func bound(n float64, exclusive bool, step int) int {
+	if exclusive {
+		return int(n) + step
+	}
+	return int(n)
+}
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type money int64
+
+func (m money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(m) / 100)
+}
+
+type label string
+
+func (l label) MarshalText() ([]byte, error) {
+	return []byte(l), nil
+}
+
+type item struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gt=0"`
+}
+
+type base struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type order struct {
+	base
+	Items     []item            `json:"items" validate:"required,min=1,dive"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Note      *string           `json:"note"`
+	Parent    *order            `json:"parent,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Ignored   string            `json:"-"`
+	internal  string
+	Untagged  bool
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDocument_SchemaOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  *Schema
+	}{
+		{name: "should map booleans", value: true, want: &Schema{Type: "boolean"}},
+		{name: "should map small integers to int32", value: int32(1), want: &Schema{Type: "integer", Format: "int32"}},
+		{name: "should map integers to int64", value: 1, want: &Schema{Type: "integer", Format: "int64"}},
+		{name: "should map float32 to float", value: float32(1), want: &Schema{Type: "number", Format: "float"}},
+		{name: "should map float64 to double", value: 1.5, want: &Schema{Type: "number", Format: "double"}},
+		{name: "should map strings", value: "", want: String("")},
+		{name: "should map byte slices to base64 strings", value: []byte{}, want: String("byte")},
+		{name: "should map times to date-times", value: time.Time{}, want: String("date-time")},
+		{name: "should map UUIDs to uuid strings", value: uuid.UUID{}, want: String("uuid")},
+		{name: "should map text marshalers to strings", value: label(""), want: String("")},
+		{name: "should map JSON marshalers to any value", value: money(0), want: &Schema{}},
+		{name: "should map slices to arrays of their items", value: []string{}, want: &Schema{Type: "array", Items: String("")}},
+		{name: "should map arrays to arrays of their items", value: [2]int{}, want: &Schema{Type: "array", Items: &Schema{Type: "integer", Format: "int64"}}},
+		{
+			name:  "should map maps to objects of their values",
+			value: map[string]int{},
+			want:  &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer", Format: "int64"}},
+		},
+		{name: "should map pointers to nullable schemas", value: ptr(""), want: &Schema{Type: "string", Nullable: true}},
+		{name: "should map nil to any value", value: nil, want: &Schema{}},
+		{
+			name:  "should inline anonymous structs",
+			value: struct{ Name string }{},
+			want:  &Schema{Type: "object", Properties: map[string]*Schema{"Name": String("")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New("test", "1.0.0")
+
+			assert.Equal(t, tt.want, doc.SchemaOf(tt.value))
+			assert.Empty(t, doc.Components.Schemas)
+		})
+	}
+
+	t.Run("should reference named structs and add them to the components", func(t *testing.T) {
+		doc := New("test", "1.0.0")
+
+		schema := doc.SchemaOf(order{})
+
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/order"}, schema)
+		require.Contains(t, doc.Components.Schemas, "order")
+		require.Contains(t, doc.Components.Schemas, "item")
+		component := doc.Components.Schemas["order"]
+		assert.Equal(t, "object", component.Type)
+		assert.ElementsMatch(t, []string{"id", "items", "tags", "note", "parent", "created_at", "Untagged"}, keys(component.Properties))
+		assert.Equal(t, []string{"items"}, component.Required)
+		assert.Equal(t, String("uuid"), component.Properties["id"], "embedded struct fields are flattened")
+		assert.Equal(t, &Schema{Type: "string", Nullable: true}, component.Properties["note"])
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/order"}, component.Properties["parent"], "recursive types reference their component")
+		assert.Equal(t, &Schema{Type: "object", AdditionalProperties: String("")}, component.Properties["tags"])
+	})
+
+	t.Run("should reference pointers to named structs without making them nullable", func(t *testing.T) {
+		doc := New("test", "1.0.0")
+
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/item"}, doc.SchemaOf(&item{}))
+	})
+
+	t.Run("should use the schemas of the defined types", func(t *testing.T) {
+		doc := New("test", "1.0.0").Define(money(0), &Schema{Type: "number", Format: "decimal"})
+
+		schema := doc.SchemaOf(money(0))
+		schema.Description = "changed"
+
+		assert.Equal(t, &Schema{Type: "number", Format: "decimal"}, doc.SchemaOf(money(0)), "returned schemas are copies")
+	})
+
+	t.Run("should name the component of a struct", func(t *testing.T) {
+		doc := New("test", "1.0.0")
+
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/LineItem"}, doc.Named("LineItem", &item{}))
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/LineItem"}, doc.SchemaOf(item{}), "the type keeps its name")
+		assert.NotContains(t, doc.Components.Schemas, "item")
+	})
+}
+
+func keys(m map[string]*Schema) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestApplyValidateTag(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       *Schema
+		tag          string
+		want         *Schema
+		wantRequired bool
+	}{
+		{name: "should ignore empty tags", schema: String(""), tag: "", want: String("")},
+		{name: "should ignore skipped fields", schema: String(""), tag: "-", want: String("")},
+		{name: "should report required fields", schema: String(""), tag: "required", want: String(""), wantRequired: true},
+		{name: "should set the uuid format", schema: String(""), tag: "uuid4", want: String("uuid")},
+		{name: "should set the email format", schema: String(""), tag: "email", want: String("email")},
+		{name: "should set the uri format", schema: String(""), tag: "url", want: String("uri")},
+		{name: "should enumerate oneof values", schema: String(""), tag: "oneof=BRL USD", want: Enum("BRL", "USD")},
+		{name: "should set an exclusive minimum with gt", schema: Integer(), tag: "gt=0", want: &Schema{Type: "integer", Minimum: ptr(0.0), ExclusiveMinimum: true}},
+		{name: "should set an inclusive minimum with gte", schema: Integer(), tag: "gte=1", want: &Schema{Type: "integer", Minimum: ptr(1.0)}},
+		{name: "should set an exclusive maximum with lt", schema: Integer(), tag: "lt=10", want: &Schema{Type: "integer", Maximum: ptr(10.0), ExclusiveMaximum: true}},
+		{name: "should set an inclusive maximum with lte", schema: Integer(), tag: "lte=10", want: &Schema{Type: "integer", Maximum: ptr(10.0)}},
+		{name: "should set the length bounds of strings", schema: String(""), tag: "min=1,max=255", want: &Schema{Type: "string", MinLength: ptr(1), MaxLength: ptr(255)}},
+		{name: "should make the exclusive length bounds of strings inclusive", schema: String(""), tag: "gt=1,lt=5", want: &Schema{Type: "string", MinLength: ptr(2), MaxLength: ptr(4)}},
+		{name: "should set the exact length with len", schema: String(""), tag: "len=3", want: &Schema{Type: "string", MinLength: ptr(3), MaxLength: ptr(3)}},
+		{name: "should set the item bounds of arrays", schema: &Schema{Type: "array", Items: String("")}, tag: "min=1,max=3", want: &Schema{Type: "array", Items: String(""), MinItems: ptr(1), MaxItems: ptr(3)}},
+		{
+			name:         "should apply the rules after dive to the items",
+			schema:       &Schema{Type: "array", Items: String("")},
+			tag:          "required,dive,uuid",
+			want:         &Schema{Type: "array", Items: String("uuid")},
+			wantRequired: true,
+		},
+		{name: "should ignore invalid bounds", schema: Integer(), tag: "gt=abc", want: Integer()},
+		{name: "should ignore unknown rules", schema: String(""), tag: "alphanum", want: String("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			required := applyValidateTag(tt.schema, tt.tag)
+
+			assert.Equal(t, tt.wantRequired, required)
+			assert.Equal(t, tt.want, tt.schema)
+		})
+	}
+
+	t.Run("should make required pointers not nullable", func(t *testing.T) {
+		doc := New("test", "1.0.0")
+
+		schema := doc.SchemaOf(struct {
+			Name *string `json:"name" validate:"required"`
+		}{})
+
+		assert.Equal(t, String(""), schema.Properties["name"])
+		assert.Equal(t, []string{"name"}, schema.Required)
+	})
+}
@@ -0,0 +1,339 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBodySize is the size in bytes of the largest JSON body ValidateRequest reads.
+const MaxBodySize = 1 << 20
+
+// FieldError is a value of a request that does not match the document.
+// Field is the parameter name, "body" or the dot separated path of the invalid body property.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ErrorHandler writes the response of a request rejected with status 400.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, errs []FieldError)
+
+// Validator returns a middleware rejecting the requests whose parameters or JSON body do not match
+// the operation of the document. Requests to paths without an operation are let through.
+func (d *Document) Validator(onError ErrorHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operation, pathParams := d.Find(r.Method, r.URL.Path)
+			if operation == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			errs, err := d.ValidateRequest(r, operation, pathParams)
+			if err != nil {
+				errs = []FieldError{{Field: "body", Message: "Could not read body"}}
+			}
+			if len(errs) > 0 {
+				onError(w, r, errs)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Find returns the operation handling method requests to path and the values of its path parameters.
+// Concrete paths are preferred to templated ones.
+func (d *Document) Find(method string, path string) (*Operation, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var (
+		found     *Operation
+		params    map[string]string
+		templates = -1
+	)
+	for template, item := range d.Paths {
+		operation, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+		values, count, ok := match(strings.Split(strings.Trim(template, "/"), "/"), segments)
+		if !ok || (found != nil && count >= templates) {
+			continue
+		}
+		found, params, templates = operation, values, count
+	}
+	return found, params
+}
+
+// match returns the values of the template parameters and how many there are, if segments match the template.
+func match(template []string, segments []string) (map[string]string, int, bool) {
+	if len(template) != len(segments) {
+		return nil, 0, false
+	}
+	values := make(map[string]string)
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			values[part[1:len(part)-1]] = segments[i]
+			continue
+		}
+		if part != segments[i] {
+			return nil, 0, false
+		}
+	}
+	return values, len(values), true
+}
+
+// ValidateRequest validates the parameters and JSON body of a request to operation.
+// The body is read up to MaxBodySize and replaced, so handlers can decode it again.
+func (d *Document) ValidateRequest(r *http.Request, operation *Operation, pathParams map[string]string) ([]FieldError, error) {
+	var errs []FieldError
+	for _, param := range operation.Parameters {
+		var (
+			value   string
+			present bool
+		)
+		switch param.In {
+		case "path":
+			value, present = pathParams[param.Name]
+		case "query":
+			value = r.URL.Query().Get(param.Name)
+			present = value != ""
+		case "header":
+			value = r.Header.Get(param.Name)
+			present = value != ""
+		}
+		if !present {
+			if param.Required {
+				errs = append(errs, FieldError{Field: param.Name, Message: "Is required"})
+			}
+			continue
+		}
+		if msg := d.checkParam(param.Schema, value); msg != "" {
+			errs = append(errs, FieldError{Field: param.Name, Message: msg})
+		}
+	}
+
+	if operation.RequestBody == nil {
+		return errs, nil
+	}
+	media, ok := operation.RequestBody.Content["application/json"]
+	if !ok {
+		return errs, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return append(errs, FieldError{Field: "body", Message: fmt.Sprintf("Must be at most %d bytes", MaxBodySize)}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			errs = append(errs, FieldError{Field: "body", Message: "Empty body"})
+		}
+		return errs, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return append(errs, FieldError{Field: "body", Message: "Invalid JSON"}), nil
+	}
+	return append(errs, d.checkValue(media.Schema, value, "")...), nil
+}
+
+// checkParam validates a parameter value, which is parsed according to the schema type, and returns the error message.
+func (d *Document) checkParam(schema *Schema, raw string) string {
+	if schema == nil {
+		return ""
+	}
+	var value interface{} = raw
+	switch d.resolve(schema).Type {
+	case "integer", "number":
+		value = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "Must be a boolean"
+		}
+		value = b
+	}
+	errs := d.checkValue(schema, value, "")
+	if len(errs) == 0 {
+		return ""
+	}
+	return errs[0].Message
+}
+
+// resolve returns the component schema referenced by schema, or schema itself.
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return &Schema{}
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+func (d *Document) checkValue(schema *Schema, value interface{}, path string) []FieldError {
+	if schema == nil {
+		return nil
+	}
+	schema = d.resolve(schema)
+	field := path
+	if field == "" {
+		field = "body"
+	}
+	fail := func(format string, args ...interface{}) []FieldError {
+		return []FieldError{{Field: field, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fail("Must not be null")
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return fail("Must be one of %v", schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("Must be an object")
+		}
+		return d.checkObject(schema, object, path)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fail("Must be an array")
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			return fail("Must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			return fail("Must have at most %d items", *schema.MaxItems)
+		}
+		var errs []FieldError
+		for i, item := range items {
+			errs = append(errs, d.checkValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+		return errs
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fail("Must be a string")
+		}
+		length := len([]rune(s))
+		if schema.MinLength != nil && length < *schema.MinLength {
+			return fail("Must have at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			return fail("Must have at most %d characters", *schema.MaxLength)
+		}
+		if msg := checkFormat(schema.Format, s); msg != "" {
+			return fail("%s", msg)
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fail("Must be a number")
+		}
+		n, err := number.Float64()
+		if err != nil {
+			return fail("Must be a number")
+		}
+		if schema.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return fail("Must be an integer")
+			}
+		}
+		if schema.Minimum != nil && (n < *schema.Minimum || (schema.ExclusiveMinimum && n == *schema.Minimum)) {
+			if schema.ExclusiveMinimum {
+				return fail("Must be greater than %v", *schema.Minimum)
+			}
+			return fail("Must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && (n > *schema.Maximum || (schema.ExclusiveMaximum && n == *schema.Maximum)) {
+			if schema.ExclusiveMaximum {
+				return fail("Must be less than %v", *schema.Maximum)
+			}
+			return fail("Must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("Must be a boolean")
+		}
+	}
+	return nil
+}
+
+func (d *Document) checkObject(schema *Schema, object map[string]interface{}, path string) []FieldError {
+	var errs []FieldError
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, FieldError{Field: join(path, name), Message: "Is required"})
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			property = schema.AdditionalProperties
+		}
+		errs = append(errs, d.checkValue(property, object[name], join(path, name))...)
+	}
+	return errs
+}
+
+func checkFormat(format string, value string) string {
+	switch format {
+	case "uuid":
+		if _, err := uuid.Parse(value); err != nil {
+			return "Must be a UUID"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "Must be an RFC 3339 date-time"
+		}
+	}
+	return ""
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, v := range enum {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
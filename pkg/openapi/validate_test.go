@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createOrderRequest struct {
+	CustomerID   string  `json:"customer_id" validate:"required,uuid"`
+	CurrencyCode string  `json:"currency_code" validate:"required,oneof=BRL USD"`
+	Amount       float64 `json:"amount" validate:"gt=0"`
+	Note         *string `json:"note" validate:"max=5"`
+	Items        []item  `json:"items" validate:"required,min=1,dive"`
+	Express      bool    `json:"express"`
+}
+
+func newTestDocument() *Document {
+	doc := New("test", "1.0.0")
+	doc.Add(http.MethodPost, "/v1/customers/{id}/orders", &Operation{
+		OperationID: "createOrder",
+		Parameters: []Parameter{
+			PathParam("id", "Customer ID", String("uuid")),
+			QueryParam("limit", "Page size", Integer().WithRange(1, 100)),
+			QueryParam("dry_run", "Validate only", &Schema{Type: "boolean"}),
+			HeaderParam("Idempotency-Key", "Idempotency key", String("").WithMaxLength(8)),
+		},
+		RequestBody: JSONBody(doc.SchemaOf(createOrderRequest{})),
+	})
+	doc.Add(http.MethodGet, "/v1/customers/{id}", &Operation{OperationID: "getCustomer"})
+	doc.Add(http.MethodGet, "/v1/customers/me", &Operation{OperationID: "getMe"})
+	return doc
+}
+
+func TestDocument_ValidateRequest(t *testing.T) {
+	const (
+		customerID = "018f6058-66f6-7110-82ac-8fd0034b1363"
+		validBody  = `{"customer_id":"` + customerID + `","currency_code":"BRL","amount":10.5,"items":[{"sku":"A","quantity":1}]}`
+	)
+	doc := newTestDocument()
+
+	tests := []struct {
+		name    string
+		path    string
+		query   string
+		header  string
+		body    string
+		want    []FieldError
+		wantNil bool
+	}{
+		{name: "should accept a valid request", path: customerID, query: "limit=10&dry_run=true", header: "key", body: validBody, wantNil: true},
+		{name: "should accept unknown properties", path: customerID, body: `{"customer_id":"` + customerID + `","currency_code":"USD","amount":1,"items":[{"sku":"A","quantity":1}],"other":1}`, wantNil: true},
+		{name: "should reject an invalid path parameter", path: "123", body: validBody, want: []FieldError{{Field: "id", Message: "Must be a UUID"}}},
+		{name: "should reject a query parameter out of range", path: customerID, query: "limit=101", body: validBody, want: []FieldError{{Field: "limit", Message: "Must be at most 100"}}},
+		{name: "should reject a query parameter that is not an integer", path: customerID, query: "limit=1.5", body: validBody, want: []FieldError{{Field: "limit", Message: "Must be an integer"}}},
+		{name: "should reject a query parameter that is not a boolean", path: customerID, query: "dry_run=maybe", body: validBody, want: []FieldError{{Field: "dry_run", Message: "Must be a boolean"}}},
+		{name: "should reject a header that is too long", path: customerID, header: "123456789", body: validBody, want: []FieldError{{Field: "Idempotency-Key", Message: "Must have at most 8 characters"}}},
+		{name: "should reject an empty required body", path: customerID, body: " ", want: []FieldError{{Field: "body", Message: "Empty body"}}},
+		{name: "should reject invalid JSON", path: customerID, body: `{"customer_id":`, want: []FieldError{{Field: "body", Message: "Invalid JSON"}}},
+		{name: "should reject a body that is not an object", path: customerID, body: `[]`, want: []FieldError{{Field: "body", Message: "Must be an object"}}},
+		{
+			name: "should reject missing required properties",
+			path: customerID,
+			body: `{"amount":1}`,
+			want: []FieldError{
+				{Field: "customer_id", Message: "Is required"},
+				{Field: "currency_code", Message: "Is required"},
+				{Field: "items", Message: "Is required"},
+			},
+		},
+		{
+			name: "should reject properties that break their constraints",
+			path: customerID,
+			body: `{"customer_id":"abc","currency_code":"EUR","amount":0,"note":"too long","items":[],"express":"yes"}`,
+			want: []FieldError{
+				{Field: "amount", Message: "Must be greater than 0"},
+				{Field: "currency_code", Message: "Must be one of [BRL USD]"},
+				{Field: "customer_id", Message: "Must be a UUID"},
+				{Field: "express", Message: "Must be a boolean"},
+				{Field: "items", Message: "Must have at least 1 items"},
+				{Field: "note", Message: "Must have at most 5 characters"},
+			},
+		},
+		{
+			name: "should reject invalid items with their index",
+			path: customerID,
+			body: `{"customer_id":"` + customerID + `","currency_code":"BRL","amount":1,"items":[{"sku":"A","quantity":1},{"quantity":"1"}]}`,
+			want: []FieldError{
+				{Field: "items[1].sku", Message: "Is required"},
+				{Field: "items[1].quantity", Message: "Must be a number"},
+			},
+		},
+		{
+			name: "should reject null for properties that are not nullable",
+			path: customerID,
+			body: `{"customer_id":null,"currency_code":"BRL","amount":1,"note":null,"items":[{"sku":"A","quantity":1}]}`,
+			want: []FieldError{{Field: "customer_id", Message: "Must not be null"}},
+		},
+		{
+			name: "should reject a body larger than the maximum",
+			path: customerID,
+			body: `{"customer_id":"` + strings.Repeat("a", MaxBodySize) + `"}`,
+			want: []FieldError{{Field: "body", Message: "Must be at most 1048576 bytes"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/customers/"+tt.path+"/orders?"+tt.query, strings.NewReader(tt.body))
+			if tt.header != "" {
+				r.Header.Set("Idempotency-Key", tt.header)
+			}
+			operation, params := doc.Find(r.Method, r.URL.Path)
+			require.NotNil(t, operation)
+
+			errs, err := doc.ValidateRequest(r, operation, params)
+
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Empty(t, errs)
+				return
+			}
+			assert.Equal(t, tt.want, errs)
+		})
+	}
+
+	t.Run("should replace the body so handlers can read it again", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/v1/customers/"+customerID+"/orders", strings.NewReader(validBody))
+		operation, params := doc.Find(r.Method, r.URL.Path)
+
+		errs, err := doc.ValidateRequest(r, operation, params)
+		require.NoError(t, err)
+		require.Empty(t, errs)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, validBody, string(body))
+	})
+}
+
+func TestDocument_Find(t *testing.T) {
+	doc := newTestDocument()
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		want       string
+		wantParams map[string]string
+	}{
+		{name: "should find a templated path", method: http.MethodGet, path: "/v1/customers/42", want: "getCustomer", wantParams: map[string]string{"id": "42"}},
+		{name: "should prefer concrete paths", method: http.MethodGet, path: "/v1/customers/me", want: "getMe", wantParams: map[string]string{}},
+		{name: "should not match other methods", method: http.MethodDelete, path: "/v1/customers/42"},
+		{name: "should not match other paths", method: http.MethodGet, path: "/v1/customers/42/orders"},
+		{name: "should not match empty segments", method: http.MethodGet, path: "/v1/customers//"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, params := doc.Find(tt.method, tt.path)
+
+			if tt.want == "" {
+				assert.Nil(t, operation)
+				return
+			}
+			require.NotNil(t, operation)
+			assert.Equal(t, tt.want, operation.OperationID)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestDocument_Validator(t *testing.T) {
+	doc := newTestDocument()
+	var rejected []FieldError
+	handler := doc.Validator(func(w http.ResponseWriter, _ *http.Request, errs []FieldError) {
+		rejected = errs
+		w.WriteHeader(http.StatusBadRequest)
+	})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantErrs   []FieldError
+	}{
+		{name: "should call the handler of a valid request", method: http.MethodGet, path: "/v1/customers/42", wantStatus: http.StatusNoContent},
+		{name: "should call the handler of a path without operation", method: http.MethodGet, path: "/v1/unknown", wantStatus: http.StatusNoContent},
+		{name: "should reject an invalid request", method: http.MethodPost, path: "/v1/customers/42/orders", wantStatus: http.StatusBadRequest, wantErrs: []FieldError{
+			{Field: "id", Message: "Must be a UUID"},
+			{Field: "body", Message: "Empty body"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected = nil
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+			assert.Equal(t, tt.wantErrs, rejected)
+		})
+	}
+}
@@ -1,17 +1,38 @@
 package responses
 
-import "net/http"
+import (
+	"net/http"
+)
 
-type Error struct {
-	Status int         `json:"-"`
-	ID     string      `json:"id"`
-	Err    ErrorDetail `json:"error"`
-}
+const (
+	// ProblemContentType is the media type of the problem details responses, see RFC 7807.
+	ProblemContentType = "application/problem+json"
+
+	// ProblemTypeBaseURI prefixes the problem types. Each type is documented in docs/problems.md.
+	ProblemTypeBaseURI = "https://github.com/bmviniciuss/sagas-golang/blob/main/docs/problems.md#"
+
+	TypeValidation      = ProblemTypeBaseURI + "validation-error"
+	TypeUnauthorized    = ProblemTypeBaseURI + "unauthorized"
+	TypeForbidden       = ProblemTypeBaseURI + "forbidden"
+	TypeNotFound        = ProblemTypeBaseURI + "not-found"
+	TypeConflict        = ProblemTypeBaseURI + "conflict"
+	TypeInvalidState    = ProblemTypeBaseURI + "invalid-state"
+	TypeTooManyRequests = ProblemTypeBaseURI + "too-many-requests"
+	TypeInternal        = ProblemTypeBaseURI + "internal-error"
+)
 
-type ErrorDetail struct {
-	Code    int          `json:"code"`
-	Message string       `json:"message"`
-	Details []FieldError `json:"details,omitempty"`
+// Problem is the body of the error responses, a problem details object as defined by RFC 7807.
+type Problem struct {
+	// Type is a URI identifying the problem type.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request, set by RenderError.
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
 }
 
 type FieldError struct {
@@ -19,36 +40,47 @@ type FieldError struct {
 	Message string `json:"message"`
 }
 
-func NewInternalServerErrorResponse(id string) Error {
-	return Error{
-		ID:     id,
-		Status: http.StatusInternalServerError,
-		Err: ErrorDetail{
-			Code:    http.StatusInternalServerError,
-			Message: "Internal Server Error",
-		},
+func newProblem(id string, problemType string, status int, detail string) Problem {
+	return Problem{
+		Type:      problemType,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		RequestID: id,
 	}
 }
 
-func NewNotFoundErrorResponse(id string) Error {
-	return Error{
-		ID:     id,
-		Status: http.StatusNotFound,
-		Err: ErrorDetail{
-			Code:    http.StatusNotFound,
-			Message: "Not Found",
-		},
-	}
+func NewInternalServerErrorResponse(id string) Problem {
+	return newProblem(id, TypeInternal, http.StatusInternalServerError, "")
 }
 
-func NewBadRequestErrorResponse(id string, details []FieldError) Error {
-	return Error{
-		ID:     id,
-		Status: http.StatusBadRequest,
-		Err: ErrorDetail{
-			Code:    http.StatusBadRequest,
-			Message: "Bad Request",
-			Details: details,
-		},
-	}
+func NewNotFoundErrorResponse(id string) Problem {
+	return newProblem(id, TypeNotFound, http.StatusNotFound, "")
+}
+
+func NewBadRequestErrorResponse(id string, details []FieldError) Problem {
+	problem := newProblem(id, TypeValidation, http.StatusBadRequest, "The request has invalid fields")
+	problem.Errors = details
+	return problem
+}
+
+func NewConflictErrorResponse(id string, detail string) Problem {
+	return newProblem(id, TypeConflict, http.StatusConflict, detail)
+}
+
+// NewInvalidStateErrorResponse is the response of an action the current state of the resource does not allow.
+func NewInvalidStateErrorResponse(id string, detail string) Problem {
+	return newProblem(id, TypeInvalidState, http.StatusConflict, detail)
+}
+
+func NewUnauthorizedErrorResponse(id string) Problem {
+	return newProblem(id, TypeUnauthorized, http.StatusUnauthorized, "Missing or invalid credentials")
+}
+
+func NewForbiddenErrorResponse(id string) Problem {
+	return newProblem(id, TypeForbidden, http.StatusForbidden, "The principal was not granted the scopes of the route")
+}
+
+func NewTooManyRequestsErrorResponse(id string, detail string) Problem {
+	return newProblem(id, TypeTooManyRequests, http.StatusTooManyRequests, detail)
 }
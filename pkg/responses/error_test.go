@@ -0,0 +1,137 @@
+package responses
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name       string
+		problem    Problem
+		wantType   string
+		wantStatus int
+		wantTitle  string
+	}{
+		{name: "should describe internal errors", problem: NewInternalServerErrorResponse("id"), wantType: TypeInternal, wantStatus: http.StatusInternalServerError, wantTitle: "Internal Server Error"},
+		{name: "should describe missing resources", problem: NewNotFoundErrorResponse("id"), wantType: TypeNotFound, wantStatus: http.StatusNotFound, wantTitle: "Not Found"},
+		{name: "should describe invalid requests", problem: NewBadRequestErrorResponse("id", nil), wantType: TypeValidation, wantStatus: http.StatusBadRequest, wantTitle: "Bad Request"},
+		{name: "should describe conflicts", problem: NewConflictErrorResponse("id", "detail"), wantType: TypeConflict, wantStatus: http.StatusConflict, wantTitle: "Conflict"},
+		{name: "should describe invalid states as conflicts", problem: NewInvalidStateErrorResponse("id", "detail"), wantType: TypeInvalidState, wantStatus: http.StatusConflict, wantTitle: "Conflict"},
+		{name: "should describe missing credentials", problem: NewUnauthorizedErrorResponse("id"), wantType: TypeUnauthorized, wantStatus: http.StatusUnauthorized, wantTitle: "Unauthorized"},
+		{name: "should describe missing scopes", problem: NewForbiddenErrorResponse("id"), wantType: TypeForbidden, wantStatus: http.StatusForbidden, wantTitle: "Forbidden"},
+		{name: "should describe rate limited requests", problem: NewTooManyRequestsErrorResponse("id", "detail"), wantType: TypeTooManyRequests, wantStatus: http.StatusTooManyRequests, wantTitle: "Too Many Requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantType, tt.problem.Type)
+			assert.Equal(t, tt.wantStatus, tt.problem.Status)
+			assert.Equal(t, tt.wantTitle, tt.problem.Title)
+			assert.Equal(t, "id", tt.problem.RequestID)
+		})
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	t.Run("should write the problem with its status and the request path as instance", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		problem := NewBadRequestErrorResponse("id", []FieldError{{Field: "step", Message: "Is required"}})
+
+		RenderError(res, httptest.NewRequest(http.MethodPost, "/v1/executions/42/resume", nil), problem)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, ProblemContentType, res.Header().Get("Content-Type"))
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+		assert.Equal(t, map[string]interface{}{
+			"type":       TypeValidation,
+			"title":      "Bad Request",
+			"status":     float64(http.StatusBadRequest),
+			"detail":     "The request has invalid fields",
+			"instance":   "/v1/executions/42/resume",
+			"request_id": "id",
+			"errors":     []interface{}{map[string]interface{}{"field": "step", "message": "Is required"}},
+		}, got)
+	})
+
+	t.Run("should keep the instance of the problem", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		problem := NewNotFoundErrorResponse("id")
+		problem.Instance = "/v1/executions/42"
+
+		RenderError(res, httptest.NewRequest(http.MethodGet, "/other", nil), problem)
+
+		var got Problem
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+		assert.Equal(t, "/v1/executions/42", got.Instance)
+	})
+}
+
+type requestIDKey struct{}
+
+// contextRequestID reads the request ID set by newRequestWithID.
+func contextRequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+func newRequestWithID(method, target string) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, "request-id"))
+}
+
+func TestAuthErrorRenderer(t *testing.T) {
+	render := AuthErrorRenderer(contextRequestID)
+	tests := []struct {
+		name       string
+		status     int
+		wantType   string
+		wantStatus int
+	}{
+		{name: "should render unauthorized requests", status: http.StatusUnauthorized, wantType: TypeUnauthorized, wantStatus: http.StatusUnauthorized},
+		{name: "should render forbidden requests", status: http.StatusForbidden, wantType: TypeForbidden, wantStatus: http.StatusForbidden},
+		{name: "should render other statuses as internal errors", status: http.StatusBadGateway, wantType: TypeInternal, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+
+			render(res, newRequestWithID(http.MethodGet, "/v1/executions"), tt.status)
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+			var got Problem
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantType, got.Type)
+			assert.Equal(t, "request-id", got.RequestID)
+			assert.Equal(t, "/v1/executions", got.Instance)
+		})
+	}
+}
+
+func TestValidationErrorRenderer(t *testing.T) {
+	t.Run("should render the field errors as a validation problem", func(t *testing.T) {
+		res := httptest.NewRecorder()
+
+		ValidationErrorRenderer(contextRequestID)(res, newRequestWithID(http.MethodGet, "/v1/orders"), []openapi.FieldError{
+			{Field: "limit", Message: "Must be at most 100"},
+			{Field: "cursor", Message: "Is invalid"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		var got Problem
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+		assert.Equal(t, TypeValidation, got.Type)
+		assert.Equal(t, "request-id", got.RequestID)
+		assert.Equal(t, []FieldError{
+			{Field: "limit", Message: "Must be at most 100"},
+			{Field: "cursor", Message: "Is invalid"},
+		}, got.Errors)
+	})
+}
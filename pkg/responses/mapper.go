@@ -0,0 +1,82 @@
+package responses
+
+import (
+	"errors"
+	"strings"
+)
+
+// Mapper maps domain errors to problems. Errors are matched with errors.Is, in the order their rules were added.
+type Mapper struct {
+	rules []rule
+}
+
+type rule struct {
+	target  error
+	problem func(id string, err error) Problem
+}
+
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// NotFound maps targets to 404 Not Found.
+func (m *Mapper) NotFound(targets ...error) *Mapper {
+	return m.add(targets, func(id string, _ error) Problem {
+		return NewNotFoundErrorResponse(id)
+	})
+}
+
+// Conflict maps targets to 409 Conflict problems detailed by the target message.
+func (m *Mapper) Conflict(targets ...error) *Mapper {
+	return m.add(targets, func(id string, err error) Problem {
+		return NewConflictErrorResponse(id, detail(err))
+	})
+}
+
+// InvalidState maps targets to 409 Conflict invalid state problems detailed by the target message.
+func (m *Mapper) InvalidState(targets ...error) *Mapper {
+	return m.add(targets, func(id string, err error) Problem {
+		return NewInvalidStateErrorResponse(id, detail(err))
+	})
+}
+
+// InvalidField maps targets to 400 Bad Request problems with an error of field.
+func (m *Mapper) InvalidField(field string, targets ...error) *Mapper {
+	return m.add(targets, func(id string, err error) Problem {
+		return NewBadRequestErrorResponse(id, []FieldError{{Field: field, Message: detail(err)}})
+	})
+}
+
+// TooManyRequests maps targets to 429 Too Many Requests problems detailed by the target message.
+func (m *Mapper) TooManyRequests(targets ...error) *Mapper {
+	return m.add(targets, func(id string, err error) Problem {
+		return NewTooManyRequestsErrorResponse(id, detail(err))
+	})
+}
+
+func (m *Mapper) add(targets []error, problem func(id string, err error) Problem) *Mapper {
+	for _, target := range targets {
+		m.rules = append(m.rules, rule{target: target, problem: problem})
+	}
+	return m
+}
+
+// Problem returns the problem of err, or a 500 Internal Server Error problem if no rule matches it.
+func (m *Mapper) Problem(id string, err error) Problem {
+	for _, r := range m.rules {
+		if errors.Is(err, r.target) {
+			// the target message is shown, the error may wrap it with internal details
+			return r.problem(id, r.target)
+		}
+	}
+	return NewInternalServerErrorResponse(id)
+}
+
+// detail returns the error message as a sentence.
+func detail(err error) string {
+	msg := err.Error()
+	if msg == "" {
+		return msg
+	}
+	return strings.ToUpper(msg[:1]) + msg[1:]
+}
@@ -0,0 +1,82 @@
+package responses
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper_Problem(t *testing.T) {
+	var (
+		errNotFound  = errors.New("execution not found")
+		errConflict  = errors.New("idempotency key is already used")
+		errState     = errors.New("execution is not running")
+		errInvalid   = errors.New("step not found in workflow")
+		errTooMany   = errors.New("workflow has too many active executions")
+		errUnmatched = errors.New("connection refused")
+		mapper       = NewMapper().
+				NotFound(errNotFound).
+				Conflict(errConflict).
+				InvalidState(errState).
+				InvalidField("step", errInvalid).
+				TooManyRequests(errTooMany)
+	)
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "should map not found errors",
+			err:  errNotFound,
+			want: Problem{Type: TypeNotFound, Title: "Not Found", Status: http.StatusNotFound, RequestID: "id"},
+		},
+		{
+			name: "should map conflicts with the target message",
+			err:  errConflict,
+			want: Problem{Type: TypeConflict, Title: "Conflict", Status: http.StatusConflict, Detail: "Idempotency key is already used", RequestID: "id"},
+		},
+		{
+			name: "should map invalid states with the target message",
+			err:  errState,
+			want: Problem{Type: TypeInvalidState, Title: "Conflict", Status: http.StatusConflict, Detail: "Execution is not running", RequestID: "id"},
+		},
+		{
+			name: "should map invalid fields to validation errors",
+			err:  errInvalid,
+			want: Problem{
+				Type: TypeValidation, Title: "Bad Request", Status: http.StatusBadRequest, Detail: "The request has invalid fields", RequestID: "id",
+				Errors: []FieldError{{Field: "step", Message: "Step not found in workflow"}},
+			},
+		},
+		{
+			name: "should map too many requests with the target message",
+			err:  errTooMany,
+			want: Problem{Type: TypeTooManyRequests, Title: "Too Many Requests", Status: http.StatusTooManyRequests, Detail: "Workflow has too many active executions", RequestID: "id"},
+		},
+		{
+			name: "should hide the details of wrapped errors",
+			err:  fmt.Errorf("%w: execution [42] is compensating", errState),
+			want: Problem{Type: TypeInvalidState, Title: "Conflict", Status: http.StatusConflict, Detail: "Execution is not running", RequestID: "id"},
+		},
+		{
+			name: "should map unmatched errors to internal errors",
+			err:  errUnmatched,
+			want: Problem{Type: TypeInternal, Title: "Internal Server Error", Status: http.StatusInternalServerError, RequestID: "id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mapper.Problem("id", tt.err))
+		})
+	}
+
+	t.Run("should apply the first rule matching the error", func(t *testing.T) {
+		mapper := NewMapper().Conflict(errState).InvalidState(errState)
+
+		assert.Equal(t, TypeConflict, mapper.Problem("id", errState).Type)
+	})
+}
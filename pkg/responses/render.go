@@ -1,12 +1,51 @@
 package responses
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 
-	"github.com/go-chi/render"
+	"github.com/bmviniciuss/sagas-golang/pkg/openapi"
 )
 
-func RenderError(w http.ResponseWriter, r *http.Request, errorResponse Error) {
-	w.WriteHeader(errorResponse.Status)
-	render.JSON(w, r, errorResponse)
+// RequestIDFunc returns the ID of the request carried by ctx.
+type RequestIDFunc func(ctx context.Context) (string, bool)
+
+// RenderError writes problem as an application/problem+json response, setting its instance to the request path.
+func RenderError(w http.ResponseWriter, r *http.Request, problem Problem) {
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// AuthErrorRenderer returns a function rendering the problem of a request rejected by authentication with status,
+// identified by the request ID read by requestID.
+func AuthErrorRenderer(requestID RequestIDFunc) func(w http.ResponseWriter, r *http.Request, status int) {
+	return func(w http.ResponseWriter, r *http.Request, status int) {
+		reqID, _ := requestID(r.Context())
+		switch status {
+		case http.StatusUnauthorized:
+			RenderError(w, r, NewUnauthorizedErrorResponse(reqID))
+		case http.StatusForbidden:
+			RenderError(w, r, NewForbiddenErrorResponse(reqID))
+		default:
+			RenderError(w, r, NewInternalServerErrorResponse(reqID))
+		}
+	}
+}
+
+// ValidationErrorRenderer returns a function rendering the field errors of a request that doesn't match its OpenAPI operation,
+// identified by the request ID read by requestID.
+func ValidationErrorRenderer(requestID RequestIDFunc) func(w http.ResponseWriter, r *http.Request, errs []openapi.FieldError) {
+	return func(w http.ResponseWriter, r *http.Request, errs []openapi.FieldError) {
+		reqID, _ := requestID(r.Context())
+		fieldErrs := make([]FieldError, len(errs))
+		for i, err := range errs {
+			fieldErrs[i] = FieldError{Field: err.Field, Message: err.Message}
+		}
+		RenderError(w, r, NewBadRequestErrorResponse(reqID, fieldErrs))
+	}
 }
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"reflect"
 )
 
-func ParseErrorToResponse(reqID string, err error) Error {
+// ParseErrorToResponse returns the problem of an error decoding a JSON request body.
+func ParseErrorToResponse(reqID string, err error) Problem {
 	if err == nil {
 		return NewInternalServerErrorResponse(reqID)
 	}
@@ -33,7 +35,7 @@ func ParseErrorToResponse(reqID string, err error) Error {
 		return NewBadRequestErrorResponse(reqID, []FieldError{
 			{
 				Field:   unmarshalTypeError.Field,
-				Message: "Invalid value",
+				Message: "Must be " + jsonType(unmarshalTypeError.Type),
 			},
 		})
 	}
@@ -49,3 +51,26 @@ func ParseErrorToResponse(reqID string, err error) Error {
 
 	return NewInternalServerErrorResponse(reqID)
 }
+
+// jsonType names the JSON type decoded into t, with its article.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a valid value"
+}
@@ -1,14 +1,89 @@
 package responses
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidatorErrorToFieldError converts the errors of a validator into field errors with human-readable messages.
+// Fields are named by the dot separated path of their JSON names, such as items[0].quantity.
+// Errors other than validator.ValidationErrors, such as a nil or non struct value, are reported as an invalid body.
 func ValidatorErrorToFieldError(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []FieldError{{Field: "body", Message: "Is invalid"}}
+	}
 	var fieldErrors []FieldError
-	for _, err := range err.(validator.ValidationErrors) {
+	for _, err := range validationErrs {
 		fieldErrors = append(fieldErrors, FieldError{
-			Field:   err.Field(),
-			Message: err.Error(),
+			Field:   fieldPath(err),
+			Message: validationMessage(err),
 		})
 	}
 	return fieldErrors
 }
+
+// fieldPath returns the namespace of the field without the name of the validated struct.
+func fieldPath(err validator.FieldError) string {
+	_, path, ok := strings.Cut(err.Namespace(), ".")
+	if !ok {
+		return err.Field()
+	}
+	return path
+}
+
+func validationMessage(err validator.FieldError) string {
+	var (
+		param = err.Param()
+		unit  = ""
+	)
+	switch err.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch err.Tag() {
+	case "required":
+		return "Is required"
+	case "uuid", "uuid4":
+		return "Must be a UUID"
+	case "email":
+		return "Must be an email address"
+	case "url", "uri":
+		return "Must be a URL"
+	case "oneof":
+		return fmt.Sprintf("Must be one of [%s]", param)
+	case "len":
+		if unit == "" {
+			return fmt.Sprintf("Must be %s", param)
+		}
+		return fmt.Sprintf("Must have %s%s", param, unit)
+	case "gt":
+		if unit == "" {
+			return fmt.Sprintf("Must be greater than %s", param)
+		}
+		return fmt.Sprintf("Must have more than %s%s", param, unit)
+	case "gte", "min":
+		if unit == "" {
+			return fmt.Sprintf("Must be at least %s", param)
+		}
+		return fmt.Sprintf("Must have at least %s%s", param, unit)
+	case "lt":
+		if unit == "" {
+			return fmt.Sprintf("Must be less than %s", param)
+		}
+		return fmt.Sprintf("Must have less than %s%s", param, unit)
+	case "lte", "max":
+		if unit == "" {
+			return fmt.Sprintf("Must be at most %s", param)
+		}
+		return fmt.Sprintf("Must have at most %s%s", param, unit)
+	}
+	return fmt.Sprintf("Must satisfy the %s rule", err.Tag())
+}
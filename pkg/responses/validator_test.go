@@ -0,0 +1,83 @@
+package responses
+
+import (
+	"testing"
+
+	"github.com/bmviniciuss/sagas-golang/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type itemRequest struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gt=0,lte=10"`
+}
+
+type orderRequest struct {
+	CustomerID   string        `json:"customer_id" validate:"required,uuid"`
+	Email        string        `json:"email" validate:"omitempty,email"`
+	CallbackURL  string        `json:"callback_url" validate:"omitempty,url"`
+	CurrencyCode string        `json:"currency_code" validate:"oneof=BRL USD"`
+	Code         string        `json:"code" validate:"len=3"`
+	Note         string        `json:"note" validate:"max=5"`
+	Amount       float64       `json:"amount" validate:"gte=1"`
+	Priority     int           `json:"priority" validate:"lt=3"`
+	Tags         []string      `json:"tags" validate:"min=1"`
+	Items        []itemRequest `json:"items" validate:"dive"`
+	Reference    string        `json:"reference" validate:"alphanum"`
+}
+
+func TestValidatorErrorToFieldError(t *testing.T) {
+	valid := func() orderRequest {
+		return orderRequest{
+			CustomerID:   "018f6058-66f6-7110-82ac-8fd0034b1363",
+			CurrencyCode: "BRL",
+			Code:         "abc",
+			Amount:       1,
+			Tags:         []string{"a"},
+			Items:        []itemRequest{{SKU: "A", Quantity: 1}},
+			Reference:    "ref1",
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(r *orderRequest)
+		want   FieldError
+	}{
+		{name: "should report required fields", modify: func(r *orderRequest) { r.CustomerID = "" }, want: FieldError{Field: "customer_id", Message: "Is required"}},
+		{name: "should report invalid UUIDs", modify: func(r *orderRequest) { r.CustomerID = "abc" }, want: FieldError{Field: "customer_id", Message: "Must be a UUID"}},
+		{name: "should report invalid emails", modify: func(r *orderRequest) { r.Email = "abc" }, want: FieldError{Field: "email", Message: "Must be an email address"}},
+		{name: "should report invalid URLs", modify: func(r *orderRequest) { r.CallbackURL = "abc" }, want: FieldError{Field: "callback_url", Message: "Must be a URL"}},
+		{name: "should report values out of oneof", modify: func(r *orderRequest) { r.CurrencyCode = "EUR" }, want: FieldError{Field: "currency_code", Message: "Must be one of [BRL USD]"}},
+		{name: "should report string lengths in characters", modify: func(r *orderRequest) { r.Code = "ab" }, want: FieldError{Field: "code", Message: "Must have 3 characters"}},
+		{name: "should report maximum string lengths", modify: func(r *orderRequest) { r.Note = "too long" }, want: FieldError{Field: "note", Message: "Must have at most 5 characters"}},
+		{name: "should report number minimums", modify: func(r *orderRequest) { r.Amount = 0.5 }, want: FieldError{Field: "amount", Message: "Must be at least 1"}},
+		{name: "should report exclusive number maximums", modify: func(r *orderRequest) { r.Priority = 3 }, want: FieldError{Field: "priority", Message: "Must be less than 3"}},
+		{name: "should report slice lengths in items", modify: func(r *orderRequest) { r.Tags = []string{} }, want: FieldError{Field: "tags", Message: "Must have at least 1 items"}},
+		{name: "should name nested fields by their path", modify: func(r *orderRequest) { r.Items[0].Quantity = 0 }, want: FieldError{Field: "items[0].quantity", Message: "Must be greater than 0"}},
+		{name: "should report inclusive number maximums", modify: func(r *orderRequest) { r.Items[0].Quantity = 11 }, want: FieldError{Field: "items[0].quantity", Message: "Must be at most 10"}},
+		{name: "should name the rules without a message", modify: func(r *orderRequest) { r.Reference = "a-b" }, want: FieldError{Field: "reference", Message: "Must satisfy the alphanum rule"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+
+			err := validator.New().Struct(req)
+
+			require.Error(t, err)
+			assert.Equal(t, []FieldError{tt.want}, ValidatorErrorToFieldError(err))
+		})
+	}
+
+	t.Run("should accept a valid request", func(t *testing.T) {
+		assert.NoError(t, validator.New().Struct(valid()))
+	})
+
+	t.Run("should report an invalid body when the value can't be validated", func(t *testing.T) {
+		err := validator.New().Struct(nil)
+
+		require.Error(t, err)
+		assert.Equal(t, []FieldError{{Field: "body", Message: "Is invalid"}}, ValidatorErrorToFieldError(err))
+	})
+}
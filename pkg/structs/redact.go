@@ -0,0 +1,45 @@
+package structs
+
+import "strings"
+
+const (
+	RedactedValue = "[REDACTED]"
+)
+
+// Redact returns a deep copy of data with the values at the given dot separated paths replaced by RedactedValue.
+// A path crossing a list is applied to every element of the list, so "items.price" redacts the price of every item.
+func Redact(data map[string]interface{}, paths []string) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	redacted, err := ToMap(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		redact(redacted, strings.Split(path, "."))
+	}
+	return redacted, nil
+}
+
+func redact(value interface{}, keys []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := v[keys[0]]
+		if !ok {
+			return
+		}
+		if len(keys) == 1 {
+			v[keys[0]] = RedactedValue
+			return
+		}
+		redact(child, keys[1:])
+	case []interface{}:
+		for _, item := range v {
+			redact(item, keys)
+		}
+	}
+}
@@ -1,13 +1,10 @@
 #!/bin/bash
 
-services=(
-    "orders"
-    "customers"
-    "accounting"
-)
-
-for service in "${services[@]}"
-do
-    docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --create --topic service."$service".request --partitions 1 --replication-factor 1
-    docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --create --topic service."$service".events --partitions 1 --replication-factor 1
-done
+# The topics are computed from the workflows registered in the orchestrator: the request and response topics
+# of their steps, their reply channels and the retry and dead letter topics of the participants in KAFKA_TOPIC_RETRIES.
+# Run without "apply" to only report the drift between the workflows and the cluster.
+KAFKA_BOOTSTRAP_SERVERS="${KAFKA_BOOTSTRAP_SERVERS:-localhost:9092}" \
+KAFKA_TOPIC_PARTITIONS="${KAFKA_TOPIC_PARTITIONS:-1}" \
+KAFKA_TOPIC_REPLICATION_FACTOR="${KAFKA_TOPIC_REPLICATION_FACTOR:-1}" \
+KAFKA_TOPIC_RETRIES="${KAFKA_TOPIC_RETRIES:-orders:3}" \
+    go run ./cmd/local/orchestrator topics "${1:-apply}"
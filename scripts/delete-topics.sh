@@ -11,3 +11,30 @@ do
     docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --delete --topic service."$service".request
     docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --delete --topic service."$service".events
 done
+
+# The retry and dead letter topics of the participants with a retry policy, as service:retries pairs.
+# It must match the KAFKA_TOPIC_RETRIES the topics were created with.
+IFS=',' read -r -a retries <<< "${KAFKA_TOPIC_RETRIES:-orders:3}"
+
+for pair in "${retries[@]}"
+do
+    service="${pair%%:*}"
+    count="${pair##*:}"
+    for attempt in $(seq 1 "$count")
+    do
+        docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --delete --topic service."$service".request.retry."$attempt"
+    done
+    if [ "$count" -gt 0 ]
+    then
+        docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --delete --topic service."$service".request.dlq
+    fi
+done
+
+sagas=(
+    "create_order_v1"
+)
+
+for saga in "${sagas[@]}"
+do
+    docker exec broker bash /bin/kafka-topics --bootstrap-server localhost:9092 --delete --topic saga."$saga".response
+done